@@ -45,13 +45,3 @@ func NewAccountMeta(publicKey PublicKey, isSigner bool, isWritable bool) *Accoun
 		IsWritable: isWritable,
 	}
 }
-
-func (a *AccountMeta) less(act *AccountMeta) bool {
-	if a.IsSigner != act.IsSigner {
-		return a.IsSigner
-	}
-	if a.IsWritable != act.IsWritable {
-		return a.IsWritable
-	}
-	return false
-}
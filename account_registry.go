@@ -0,0 +1,195 @@
+package solana
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// AccountDecoder decodes the data of an account into a typed value.
+type AccountDecoder func(key PublicKey, data []byte) (interface{}, error)
+
+// AccountMatcher selects, among the accounts owned by a program, the ones
+// handled by an AccountDecoder. At least one of DataSize or Discriminator must
+// be set, when both are set the account must satisfy both.
+type AccountMatcher struct {
+	// DataSize, when non-zero, is the exact length the account data must have.
+	DataSize int
+
+	// Discriminator, when non-empty, are the bytes the account data must hold
+	// at DiscriminatorOffset.
+	Discriminator       []byte
+	DiscriminatorOffset int
+}
+
+// MatchDataSize matches accounts whose data is exactly `size` bytes long.
+func MatchDataSize(size int) AccountMatcher {
+	return AccountMatcher{DataSize: size}
+}
+
+// MatchDiscriminator matches accounts whose data starts with `discriminator`.
+func MatchDiscriminator(discriminator []byte) AccountMatcher {
+	return AccountMatcher{Discriminator: discriminator}
+}
+
+func (m AccountMatcher) Matches(data []byte) bool {
+	if m.DataSize != 0 && len(data) != m.DataSize {
+		return false
+	}
+
+	if len(m.Discriminator) > 0 {
+		end := m.DiscriminatorOffset + len(m.Discriminator)
+		if len(data) < end || !bytes.Equal(data[m.DiscriminatorOffset:end], m.Discriminator) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m AccountMatcher) equals(other AccountMatcher) bool {
+	return m.DataSize == other.DataSize &&
+		m.DiscriminatorOffset == other.DiscriminatorOffset &&
+		bytes.Equal(m.Discriminator, other.Discriminator)
+}
+
+// specificity orders matchers so the most precise one is tried first, longer
+// discriminators win and, for equal discriminators, a data size requirement wins.
+func (m AccountMatcher) specificity() int {
+	out := len(m.Discriminator) * 2
+	if m.DataSize != 0 {
+		out++
+	}
+	return out
+}
+
+func (m AccountMatcher) String() string {
+	switch {
+	case m.DataSize != 0 && len(m.Discriminator) > 0:
+		return fmt.Sprintf("size %d and discriminator %x at offset %d", m.DataSize, m.Discriminator, m.DiscriminatorOffset)
+	case m.DataSize != 0:
+		return fmt.Sprintf("size %d", m.DataSize)
+	default:
+		return fmt.Sprintf("discriminator %x at offset %d", m.Discriminator, m.DiscriminatorOffset)
+	}
+}
+
+var ErrAccountDecoderNotFound = errors.New("no account decoder found")
+
+type accountDecoderEntry struct {
+	matcher AccountMatcher
+	decoder AccountDecoder
+}
+
+// AccountDecoderRegistry maps owner programs to the decoders of the accounts
+// they own, each decoder being selected by an AccountMatcher. When multiple
+// matchers accept an account, the most specific one is used.
+type AccountDecoderRegistry struct {
+	lock    sync.RWMutex
+	entries map[PublicKey][]*accountDecoderEntry
+}
+
+func NewAccountDecoderRegistry() *AccountDecoderRegistry {
+	return &AccountDecoderRegistry{
+		entries: map[PublicKey][]*accountDecoderEntry{},
+	}
+}
+
+// Register adds a decoder for the accounts owned by `owner` accepted by
+// `matcher`, it's an error to register the same matcher twice for an owner.
+func (r *AccountDecoderRegistry) Register(owner PublicKey, matcher AccountMatcher, decoder AccountDecoder) error {
+	if matcher.DataSize == 0 && len(matcher.Discriminator) == 0 {
+		return fmt.Errorf("account matcher for owner %q must have a data size or a discriminator", owner)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entries := r.entries[owner]
+	for _, entry := range entries {
+		if entry.matcher.equals(matcher) {
+			return fmt.Errorf("account decoder for owner %q matching %s already registered", owner, matcher)
+		}
+	}
+
+	entries = append(entries, &accountDecoderEntry{matcher: matcher, decoder: decoder})
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].matcher.specificity() > entries[j].matcher.specificity()
+	})
+	r.entries[owner] = entries
+	return nil
+}
+
+// Unregister removes the decoder registered with exactly `matcher` for
+// `owner`, returning whether one was found.
+func (r *AccountDecoderRegistry) Unregister(owner PublicKey, matcher AccountMatcher) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entries := r.entries[owner]
+	for idx, entry := range entries {
+		if entry.matcher.equals(matcher) {
+			r.entries[owner] = append(entries[:idx:idx], entries[idx+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Clone returns an independent copy of the registry.
+func (r *AccountDecoderRegistry) Clone() *AccountDecoderRegistry {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	out := NewAccountDecoderRegistry()
+	for owner, entries := range r.entries {
+		out.entries[owner] = append([]*accountDecoderEntry(nil), entries...)
+	}
+	return out
+}
+
+// Lookup returns the decoder for an account owned by `owner` holding `data`.
+func (r *AccountDecoderRegistry) Lookup(owner PublicKey, data []byte) (AccountDecoder, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, entry := range r.entries[owner] {
+		if entry.matcher.Matches(data) {
+			return entry.decoder, true
+		}
+	}
+	return nil, false
+}
+
+// DecodeAccount decodes the data of account `key` owned by `owner`, the
+// returned error wraps ErrAccountDecoderNotFound when no decoder matches.
+func (r *AccountDecoderRegistry) DecodeAccount(key PublicKey, owner PublicKey, data []byte) (interface{}, error) {
+	decoder, found := r.Lookup(owner, data)
+	if !found {
+		return nil, fmt.Errorf("account %s owned by %s with %d bytes of data: %w", key, owner, len(data), ErrAccountDecoderNotFound)
+	}
+
+	out, err := decoder(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode account %s owned by %s: %w", key, owner, err)
+	}
+	return out, nil
+}
+
+// DefaultAccountDecoderRegistry is the registry programs register their
+// account decoders in when imported.
+var DefaultAccountDecoderRegistry = NewAccountDecoderRegistry()
+
+// RegisterAccountDecoder registers the decoder in DefaultAccountDecoderRegistry,
+// panicking if the matcher is invalid or already registered for `owner`.
+func RegisterAccountDecoder(owner PublicKey, matcher AccountMatcher, decoder AccountDecoder) {
+	if err := DefaultAccountDecoderRegistry.Register(owner, matcher, decoder); err != nil {
+		panic(fmt.Sprintf("unable to register account decoder: %s", err))
+	}
+}
+
+// DecodeAccount decodes the account's data using DefaultAccountDecoderRegistry.
+func DecodeAccount(key PublicKey, owner PublicKey, data []byte) (interface{}, error) {
+	return DefaultAccountDecoderRegistry.DecodeAccount(key, owner, data)
+}
@@ -0,0 +1,71 @@
+package solana
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func constantAccountDecoder(value string) AccountDecoder {
+	return func(key PublicKey, data []byte) (interface{}, error) {
+		return value, nil
+	}
+}
+
+func TestAccountDecoderRegistry(t *testing.T) {
+	owner := MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn")
+	otherOwner := MustPublicKeyFromBase58("9hFtYBYmBJCVguRYs9pBTWKYAFoKfjYR7zBPpEkVsmD")
+	key := MustPublicKeyFromBase58("Vote111111111111111111111111111111111111111")
+
+	registry := NewAccountDecoderRegistry()
+	require.NoError(t, registry.Register(owner, MatchDataSize(4), constantAccountDecoder("size")))
+	require.NoError(t, registry.Register(owner, MatchDiscriminator([]byte{1}), constantAccountDecoder("short discriminator")))
+	require.NoError(t, registry.Register(owner, MatchDiscriminator([]byte{1, 2}), constantAccountDecoder("long discriminator")))
+	require.NoError(t, registry.Register(owner, AccountMatcher{DataSize: 3, Discriminator: []byte{1, 2}}, constantAccountDecoder("sized discriminator")))
+	require.NoError(t, registry.Register(owner, AccountMatcher{Discriminator: []byte{9}, DiscriminatorOffset: 1}, constantAccountDecoder("offset discriminator")))
+
+	assert.Error(t, registry.Register(owner, MatchDataSize(4), constantAccountDecoder("size")))
+	assert.Error(t, registry.Register(owner, AccountMatcher{}, constantAccountDecoder("empty")))
+
+	tests := []struct {
+		name        string
+		owner       PublicKey
+		data        []byte
+		expected    interface{}
+		expectedErr error
+	}{
+		{"size", owner, []byte{0, 0, 0, 0}, "size", nil},
+		{"discriminator wins over size", owner, []byte{1, 0, 0, 0}, "short discriminator", nil},
+		{"longest discriminator wins", owner, []byte{1, 2, 0, 0}, "long discriminator", nil},
+		{"sized discriminator wins", owner, []byte{1, 2, 0}, "sized discriminator", nil},
+		{"offset discriminator", owner, []byte{0, 9}, "offset discriminator", nil},
+		{"no match", owner, []byte{0, 0}, nil, ErrAccountDecoderNotFound},
+		{"unknown owner", otherOwner, []byte{0, 0, 0, 0}, nil, ErrAccountDecoderNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out, err := registry.DecodeAccount(key, test.owner, test.data)
+			if test.expectedErr != nil {
+				assert.True(t, errors.Is(err, test.expectedErr), "got error %v", err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, out)
+		})
+	}
+
+	clone := registry.Clone()
+	assert.True(t, registry.Unregister(owner, MatchDataSize(4)))
+	assert.False(t, registry.Unregister(owner, MatchDataSize(4)))
+
+	_, err := registry.DecodeAccount(key, owner, []byte{0, 0, 0, 0})
+	assert.True(t, errors.Is(err, ErrAccountDecoderNotFound))
+
+	out, err := clone.DecodeAccount(key, owner, []byte{0, 0, 0, 0})
+	require.NoError(t, err)
+	assert.Equal(t, "size", out)
+}
@@ -0,0 +1,284 @@
+package solana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// LAMPORTS_PER_SOL is the number of lamports in one SOL, SOL having 9 decimals.
+const LAMPORTS_PER_SOL = 1_000_000_000
+
+const SOL_DECIMALS = 9
+
+var (
+	ErrAmountOverflow  = errors.New("amount overflow")
+	ErrAmountUnderflow = errors.New("amount underflow")
+)
+
+// Lamports is an amount of lamports. It's formatted and marshaled to JSON and
+// text as the integer number of lamports, use ParseSOL and SOL to convert from
+// and to decimal SOL.
+type Lamports uint64
+
+// SOLToLamports converts a whole number of SOL to lamports.
+func SOLToLamports(sol uint64) (Lamports, error) {
+	hi, lo := bits.Mul64(sol, LAMPORTS_PER_SOL)
+	if hi != 0 {
+		return 0, ErrAmountOverflow
+	}
+	return Lamports(lo), nil
+}
+
+// ParseSOL parses an exact decimal SOL amount like `1.000000001`, it's an
+// error for the amount to have more than 9 significant decimals.
+func ParseSOL(sol string) (Lamports, error) {
+	raw, err := parseDecimal(sol, SOL_DECIMALS)
+	if err != nil {
+		return 0, err
+	}
+	return Lamports(raw), nil
+}
+
+// String returns the integer number of lamports, like MarshalText.
+func (l Lamports) String() string {
+	return strconv.FormatUint(uint64(l), 10)
+}
+
+// SOL returns the amount as a decimal number of SOL, without trailing zeros,
+// like `1.5` for 1 500 000 000 lamports.
+func (l Lamports) SOL() string {
+	return formatDecimal(uint64(l), SOL_DECIMALS)
+}
+
+func (l Lamports) Add(other Lamports) (Lamports, error) {
+	sum, carry := bits.Add64(uint64(l), uint64(other), 0)
+	if carry != 0 {
+		return 0, ErrAmountOverflow
+	}
+	return Lamports(sum), nil
+}
+
+func (l Lamports) Sub(other Lamports) (Lamports, error) {
+	diff, borrow := bits.Sub64(uint64(l), uint64(other), 0)
+	if borrow != 0 {
+		return 0, ErrAmountUnderflow
+	}
+	return Lamports(diff), nil
+}
+
+func (l Lamports) Mul(factor uint64) (Lamports, error) {
+	hi, lo := bits.Mul64(uint64(l), factor)
+	if hi != 0 {
+		return 0, ErrAmountOverflow
+	}
+	return Lamports(lo), nil
+}
+
+func (l Lamports) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatUint(uint64(l), 10)), nil
+}
+
+// UnmarshalJSON accepts the number of lamports as a JSON number or string.
+func (l *Lamports) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		data = []byte(s)
+	}
+	return l.UnmarshalText(data)
+}
+
+func (l Lamports) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatUint(uint64(l), 10)), nil
+}
+
+func (l *Lamports) UnmarshalText(data []byte) error {
+	value, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return ErrAmountOverflow
+		}
+		return fmt.Errorf("invalid lamports %q: %w", string(data), err)
+	}
+	*l = Lamports(value)
+	return nil
+}
+
+// TokenAmount is an amount of an SPL token, Raw being the amount in the
+// token's base units and Decimals the decimals of its mint.
+type TokenAmount struct {
+	Raw      uint64
+	Decimals uint8
+}
+
+func NewTokenAmount(raw uint64, decimals uint8) TokenAmount {
+	return TokenAmount{Raw: raw, Decimals: decimals}
+}
+
+// ParseTokenAmount parses an exact decimal amount like `12.5` of a token
+// having `decimals` decimals, it's an error for the amount to have more
+// significant decimals than the token.
+func ParseTokenAmount(amount string, decimals uint8) (TokenAmount, error) {
+	raw, err := parseDecimal(amount, decimals)
+	if err != nil {
+		return TokenAmount{}, err
+	}
+	return TokenAmount{Raw: raw, Decimals: decimals}, nil
+}
+
+// String returns the amount as a decimal number, without trailing zeros, the
+// same way the RPC formats `uiAmountString`.
+func (a TokenAmount) String() string {
+	return formatDecimal(a.Raw, a.Decimals)
+}
+
+func (a TokenAmount) Add(other TokenAmount) (TokenAmount, error) {
+	if a.Decimals != other.Decimals {
+		return TokenAmount{}, fmt.Errorf("cannot add amounts with %d and %d decimals", a.Decimals, other.Decimals)
+	}
+
+	sum, carry := bits.Add64(a.Raw, other.Raw, 0)
+	if carry != 0 {
+		return TokenAmount{}, ErrAmountOverflow
+	}
+	return TokenAmount{Raw: sum, Decimals: a.Decimals}, nil
+}
+
+func (a TokenAmount) Sub(other TokenAmount) (TokenAmount, error) {
+	if a.Decimals != other.Decimals {
+		return TokenAmount{}, fmt.Errorf("cannot subtract amounts with %d and %d decimals", a.Decimals, other.Decimals)
+	}
+
+	diff, borrow := bits.Sub64(a.Raw, other.Raw, 0)
+	if borrow != 0 {
+		return TokenAmount{}, ErrAmountUnderflow
+	}
+	return TokenAmount{Raw: diff, Decimals: a.Decimals}, nil
+}
+
+func (a TokenAmount) Mul(factor uint64) (TokenAmount, error) {
+	hi, lo := bits.Mul64(a.Raw, factor)
+	if hi != 0 {
+		return TokenAmount{}, ErrAmountOverflow
+	}
+	return TokenAmount{Raw: lo, Decimals: a.Decimals}, nil
+}
+
+type tokenAmountJSON struct {
+	Amount         string   `json:"amount"`
+	Decimals       uint8    `json:"decimals"`
+	UIAmount       *float64 `json:"uiAmount"`
+	UIAmountString string   `json:"uiAmountString"`
+}
+
+// MarshalJSON uses the RPC token amount representation, as found in
+// `getTokenAccountBalance` and the transaction token balances.
+func (a TokenAmount) MarshalJSON() ([]byte, error) {
+	uiAmount, _ := strconv.ParseFloat(a.String(), 64)
+	return json.Marshal(tokenAmountJSON{
+		Amount:         strconv.FormatUint(a.Raw, 10),
+		Decimals:       a.Decimals,
+		UIAmount:       &uiAmount,
+		UIAmountString: a.String(),
+	})
+}
+
+// UnmarshalJSON reads the RPC token amount representation, only the exact
+// `amount` and `decimals` fields are used.
+func (a *TokenAmount) UnmarshalJSON(data []byte) error {
+	var in tokenAmountJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	raw, err := strconv.ParseUint(in.Amount, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return ErrAmountOverflow
+		}
+		return fmt.Errorf("invalid token amount %q: %w", in.Amount, err)
+	}
+
+	*a = TokenAmount{Raw: raw, Decimals: in.Decimals}
+	return nil
+}
+
+// MarshalText returns the decimal amount, see String.
+func (a TokenAmount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText parses a decimal amount with the amount's Decimals, which
+// must be set beforehand.
+func (a *TokenAmount) UnmarshalText(data []byte) error {
+	raw, err := parseDecimal(string(data), a.Decimals)
+	if err != nil {
+		return err
+	}
+	a.Raw = raw
+	return nil
+}
+
+func formatDecimal(raw uint64, decimals uint8) string {
+	digits := strconv.FormatUint(raw, 10)
+	if decimals == 0 {
+		return digits
+	}
+
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+
+	integer, fraction := digits[:len(digits)-int(decimals)], strings.TrimRight(digits[len(digits)-int(decimals):], "0")
+	if fraction == "" {
+		return integer
+	}
+	return integer + "." + fraction
+}
+
+func parseDecimal(in string, decimals uint8) (uint64, error) {
+	integer, fraction := in, ""
+	if idx := strings.IndexByte(in, '.'); idx >= 0 {
+		integer, fraction = in[:idx], in[idx+1:]
+	}
+
+	if (integer == "" && fraction == "") || !isDigits(integer) || !isDigits(fraction) {
+		return 0, fmt.Errorf("invalid decimal amount %q", in)
+	}
+
+	if len(fraction) > int(decimals) {
+		if strings.TrimRight(fraction[decimals:], "0") != "" {
+			return 0, fmt.Errorf("decimal amount %q has more than %d decimals", in, decimals)
+		}
+		fraction = fraction[:decimals]
+	}
+
+	digits := strings.TrimLeft(integer+fraction+strings.Repeat("0", int(decimals)-len(fraction)), "0")
+	if digits == "" {
+		return 0, nil
+	}
+
+	out, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, ErrAmountOverflow
+		}
+		return 0, fmt.Errorf("invalid decimal amount %q: %w", in, err)
+	}
+	return out, nil
+}
+
+func isDigits(in string) bool {
+	for _, r := range in {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
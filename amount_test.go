@@ -0,0 +1,143 @@
+package solana
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSOL(t *testing.T) {
+	tests := []struct {
+		in          string
+		expected    Lamports
+		expectedErr string
+	}{
+		{"1", 1_000_000_000, ""},
+		{"1.000000001", 1_000_000_001, ""},
+		{"0.5", 500_000_000, ""},
+		{".5", 500_000_000, ""},
+		{"2.", 2_000_000_000, ""},
+		{"0.000000001", 1, ""},
+		{"1.1000000000", 1_100_000_000, ""},
+		{"18446744073.709551615", math.MaxUint64, ""},
+		{"18446744073.709551616", 0, "amount overflow"},
+		{"0.0000000001", 0, `decimal amount "0.0000000001" has more than 9 decimals`},
+		{"-1", 0, `invalid decimal amount "-1"`},
+		{"1e9", 0, `invalid decimal amount "1e9"`},
+		{".", 0, `invalid decimal amount "."`},
+		{"", 0, `invalid decimal amount ""`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			out, err := ParseSOL(test.in)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, out)
+		})
+	}
+}
+
+func TestLamports_String(t *testing.T) {
+	assert.Equal(t, "0", Lamports(0).String())
+	assert.Equal(t, "1500000000", Lamports(1_500_000_000).String())
+	assert.Equal(t, "1500000000", fmt.Sprintf("%v", Lamports(1_500_000_000)))
+	assert.Equal(t, "18446744073709551615", Lamports(math.MaxUint64).String())
+}
+
+func TestLamports_SOL(t *testing.T) {
+	assert.Equal(t, "0", Lamports(0).SOL())
+	assert.Equal(t, "0.000000001", Lamports(1).SOL())
+	assert.Equal(t, "1", Lamports(1_000_000_000).SOL())
+	assert.Equal(t, "1.5", Lamports(1_500_000_000).SOL())
+	assert.Equal(t, "1.000000001", Lamports(1_000_000_001).SOL())
+	assert.Equal(t, "18446744073.709551615", Lamports(math.MaxUint64).SOL())
+}
+
+func TestLamports_Arithmetic(t *testing.T) {
+	sum, err := Lamports(1).Add(2)
+	require.NoError(t, err)
+	assert.Equal(t, Lamports(3), sum)
+
+	_, err = Lamports(math.MaxUint64).Add(1)
+	assert.Equal(t, ErrAmountOverflow, err)
+
+	_, err = Lamports(1).Sub(2)
+	assert.Equal(t, ErrAmountUnderflow, err)
+
+	_, err = Lamports(math.MaxUint64 / 2).Mul(3)
+	assert.Equal(t, ErrAmountOverflow, err)
+
+	lamports, err := SOLToLamports(3)
+	require.NoError(t, err)
+	assert.Equal(t, Lamports(3_000_000_000), lamports)
+
+	_, err = SOLToLamports(math.MaxUint64 / 100)
+	assert.Equal(t, ErrAmountOverflow, err)
+}
+
+func TestLamports_JSON(t *testing.T) {
+	var out struct {
+		Number Lamports `json:"number"`
+		String Lamports `json:"string"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"number":18446744073709551615,"string":"12"}`), &out))
+	assert.Equal(t, Lamports(math.MaxUint64), out.Number)
+	assert.Equal(t, Lamports(12), out.String)
+
+	data, err := json.Marshal(out)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"number":18446744073709551615,"string":12}`, string(data))
+
+	var overflow Lamports
+	assert.Equal(t, ErrAmountOverflow, json.Unmarshal([]byte(`18446744073709551616`), &overflow))
+}
+
+func TestTokenAmount(t *testing.T) {
+	amount, err := ParseTokenAmount("12.5", 6)
+	require.NoError(t, err)
+	assert.Equal(t, TokenAmount{Raw: 12_500_000, Decimals: 6}, amount)
+	assert.Equal(t, "12.5", amount.String())
+
+	assert.Equal(t, "42", NewTokenAmount(42, 0).String())
+
+	_, err = ParseTokenAmount("1.5", 0)
+	assert.EqualError(t, err, `decimal amount "1.5" has more than 0 decimals`)
+
+	sum, err := amount.Add(NewTokenAmount(1, 6))
+	require.NoError(t, err)
+	assert.Equal(t, "12.500001", sum.String())
+
+	_, err = amount.Add(NewTokenAmount(1, 9))
+	assert.EqualError(t, err, "cannot add amounts with 6 and 9 decimals")
+
+	_, err = amount.Sub(NewTokenAmount(12_500_001, 6))
+	assert.Equal(t, ErrAmountUnderflow, err)
+}
+
+func TestTokenAmount_JSON(t *testing.T) {
+	var amount TokenAmount
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"1000000001","decimals":9,"uiAmount":1.000000001,"uiAmountString":"1.000000001"}`), &amount))
+	assert.Equal(t, NewTokenAmount(1_000_000_001, 9), amount)
+
+	data, err := json.Marshal(NewTokenAmount(1_500_000, 6))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"1500000","decimals":6,"uiAmount":1.5,"uiAmountString":"1.5"}`, string(data))
+}
+
+func TestTokenAmount_Text(t *testing.T) {
+	amount := TokenAmount{Decimals: 2}
+	require.NoError(t, amount.UnmarshalText([]byte("3.14")))
+	assert.Equal(t, uint64(314), amount.Raw)
+
+	data, err := amount.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "3.14", string(data))
+}
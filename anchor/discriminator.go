@@ -0,0 +1,79 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+const DISCRIMINATOR_SIZE = 8
+
+// Discriminator are the first 8 bytes Anchor prefixes instructions, accounts
+// and events data with.
+type Discriminator [DISCRIMINATOR_SIZE]byte
+
+func (d Discriminator) String() string {
+	return hex.EncodeToString(d[:])
+}
+
+func discriminator(preimage string) (out Discriminator) {
+	sum := sha256.Sum256([]byte(preimage))
+	copy(out[:], sum[:DISCRIMINATOR_SIZE])
+	return
+}
+
+// InstructionDiscriminator returns the sighash of an instruction, `name` being
+// the IDL (camelCase) or Rust (snake_case) name of the instruction.
+func InstructionDiscriminator(name string) Discriminator {
+	return discriminator("global:" + ToSnakeCase(name))
+}
+
+// AccountDiscriminator returns the discriminator of the account type `name`,
+// as found in the IDL.
+func AccountDiscriminator(name string) Discriminator {
+	return discriminator("account:" + name)
+}
+
+// EventDiscriminator returns the discriminator of the event `name`, as found
+// in the IDL.
+func EventDiscriminator(name string) Discriminator {
+	return discriminator("event:" + name)
+}
+
+// ToSnakeCase converts camelCase IDL names back to the snake_case Rust names
+// discriminators are computed from, following the same word boundaries as the
+// `heck` crate used by Anchor.
+func ToSnakeCase(name string) string {
+	runes := []rune(name)
+	out := strings.Builder{}
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				prev := runes[i-1]
+				nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+					out.WriteRune('_')
+				}
+			}
+			out.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
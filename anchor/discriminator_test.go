@@ -0,0 +1,49 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscriminators(t *testing.T) {
+	assert.Equal(t, "afaf6d1f0d989bed", InstructionDiscriminator("initialize").String())
+	assert.Equal(t, "52ca0f62fe8d3119", InstructionDiscriminator("setDataV2").String())
+	assert.Equal(t, InstructionDiscriminator("set_data_v2"), InstructionDiscriminator("setDataV2"))
+	assert.Equal(t, "ffb004f5bcfd7c19", AccountDiscriminator("Counter").String())
+	assert.Equal(t, "dbb5b7dc583a72c6", EventDiscriminator("CounterIncremented").String())
+}
+
+func TestToSnakeCase(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{"initialize", "initialize"},
+		{"setDataV2", "set_data_v2"},
+		{"already_snake", "already_snake"},
+		{"createATA", "create_ata"},
+		{"HTTPServer", "http_server"},
+		{"mint2Tokens", "mint2_tokens"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			assert.Equal(t, test.expected, ToSnakeCase(test.in))
+		})
+	}
+}
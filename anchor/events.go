@@ -0,0 +1,88 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const (
+	logProgramDataPrefix = "Program data: "
+	logProgramPrefix     = "Program "
+)
+
+// DecodeEvents decodes the events emitted by the program, through `emit!`,
+// from a transaction's log messages. Only `Program data: ` lines logged while
+// the program is the one being executed are considered, the invocation stack
+// being followed to skip events of other programs, including the ones called
+// through CPI. Lines not starting with the discriminator of one of the IDL's
+// events, like raw `sol_log_data` calls, are skipped. Failing to decode an
+// event does not fail the others, the error is kept on the event instead.
+func (p *Program) DecodeEvents(logs []string) []*Event {
+	programID := p.ID.String()
+
+	var stack []string
+	var out []*Event
+	for idx, line := range logs {
+		if strings.HasPrefix(line, logProgramDataPrefix) {
+			if len(stack) == 0 || stack[len(stack)-1] != programID {
+				continue
+			}
+
+			data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(line, logProgramDataPrefix))
+			if err != nil || len(data) < DISCRIMINATOR_SIZE {
+				continue
+			}
+
+			var discriminator Discriminator
+			copy(discriminator[:], data)
+			definition, found := p.events[discriminator]
+			if !found {
+				continue
+			}
+
+			event, err := p.DecodeEvent(data)
+			if err != nil {
+				event = &Event{Name: definition.Name, Err: fmt.Errorf("log %d: %w", idx, err)}
+			}
+			out = append(out, event)
+			continue
+		}
+
+		if !strings.HasPrefix(line, logProgramPrefix) {
+			continue
+		}
+
+		// Runtime lines are `Program <id> invoke [<depth>]`, `Program <id> success`
+		// and `Program <id> failed: <reason>`, as opposed to `Program log: ...`
+		// like lines which are skipped.
+		fields := strings.Fields(strings.TrimPrefix(line, logProgramPrefix))
+		if len(fields) < 2 || strings.HasSuffix(fields[0], ":") {
+			continue
+		}
+
+		switch fields[1] {
+		case "invoke":
+			stack = append(stack, fields[0])
+		case "success", "failed", "failed:":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return out
+}
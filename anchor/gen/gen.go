@@ -0,0 +1,165 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gen generates Go bindings for Anchor programs from their IDL, in
+// the same shape as the hand written `programs/*` packages.
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/streamingfast/solana-go/anchor"
+)
+
+type Options struct {
+	// Package is the name of the generated package.
+	Package string
+
+	// ProgramID is the address the program is deployed at, the address found in
+	// the IDL metadata is used when it's the zero key.
+	ProgramID solana.PublicKey
+}
+
+// knownImports are the packages generated code may refer to, imported only
+// when used.
+var knownImports = []struct {
+	name string
+	path string
+}{
+	{"bytes", "bytes"},
+	{"fmt", "fmt"},
+	{"big", "math/big"},
+	{"borsh", "github.com/near/borsh-go"},
+	{"bin", "github.com/streamingfast/binary"},
+	{"solana", "github.com/streamingfast/solana-go"},
+	{"anchor", "github.com/streamingfast/solana-go/anchor"},
+	{"text", "github.com/streamingfast/solana-go/text"},
+}
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"bytes": func(d anchor.Discriminator) string {
+		out := make([]string, len(d))
+		for idx, b := range d {
+			out[idx] = fmt.Sprintf("0x%02x", b)
+		}
+		return strings.Join(out, ", ")
+	},
+	"docs": func(docs []string) string {
+		out := strings.Builder{}
+		for _, line := range docs {
+			out.WriteString("// " + line + "\n")
+		}
+		return out.String()
+	},
+}).Parse(`
+{{- define "instructions.go" }}` + instructionsTemplate + `{{ end }}
+{{- define "types.go" }}` + typesTemplate + `{{ end }}
+{{- define "errors.go" }}` + errorsTemplate + `{{ end }}
+` + typeDefTemplates))
+
+// Generate renders the bindings of the program described by `idl`, returning
+// the content of each generated file keyed by file name.
+func Generate(idl *anchor.IDL, opts Options) (map[string][]byte, error) {
+	if opts.Package == "" {
+		return nil, fmt.Errorf("package name is required")
+	}
+
+	programID := opts.ProgramID
+	if programID.IsZero() {
+		if idl.Metadata == nil || idl.Metadata.Address == "" {
+			return nil, fmt.Errorf("no program ID given and none found in IDL %q metadata", idl.Name)
+		}
+
+		var err error
+		if programID, err = solana.PublicKeyFromBase58(idl.Metadata.Address); err != nil {
+			return nil, fmt.Errorf("invalid program address in IDL %q metadata: %w", idl.Name, err)
+		}
+	}
+
+	p, err := newProgram(idl, opts.Package, programID.String())
+	if err != nil {
+		return nil, fmt.Errorf("unable to prepare IDL %q: %w", idl.Name, err)
+	}
+
+	files := []string{"instructions.go", "types.go"}
+	if len(p.Errors) > 0 {
+		files = append(files, "errors.go")
+	}
+
+	out := map[string][]byte{}
+	for _, file := range files {
+		body := &bytes.Buffer{}
+		if err := templates.ExecuteTemplate(body, file, p); err != nil {
+			return nil, fmt.Errorf("unable to render %s: %w", file, err)
+		}
+
+		content, err := format.Source(withHeader(p, body.Bytes()))
+		if err != nil {
+			return nil, fmt.Errorf("unable to format %s: %w", file, err)
+		}
+		out[file] = content
+	}
+	return out, nil
+}
+
+var packageUsage = regexp.MustCompile(`\b([a-z]+)\.[A-Za-z]`)
+
+func withHeader(p *program, body []byte) []byte {
+	used := map[string]bool{}
+	for _, match := range packageUsage.FindAllSubmatch(body, -1) {
+		used[string(match[1])] = true
+	}
+
+	var std, others []string
+	for _, imp := range knownImports {
+		if !used[imp.name] {
+			continue
+		}
+
+		spec := fmt.Sprintf("%q", imp.path)
+		if !strings.HasSuffix(imp.path, "/"+imp.name) && imp.path != imp.name {
+			spec = imp.name + " " + spec
+		}
+		if strings.Contains(imp.path, ".") {
+			others = append(others, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	sort.Strings(std)
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by anchor-gen from the %s IDL. DO NOT EDIT.\n\n", p.IDLName)
+	fmt.Fprintf(out, "package %s\n\n", p.Package)
+	out.WriteString("import (\n")
+	for _, spec := range std {
+		out.WriteString("\t" + spec + "\n")
+	}
+	if len(std) > 0 && len(others) > 0 {
+		out.WriteString("\n")
+	}
+	for _, spec := range others {
+		out.WriteString("\t" + spec + "\n")
+	}
+	out.WriteString(")\n")
+	out.Write(body)
+	return out.Bytes()
+}
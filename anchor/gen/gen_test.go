@@ -0,0 +1,84 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/streamingfast/solana-go/anchor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the generated files of internal/counter")
+
+func TestGenerate_Counter(t *testing.T) {
+	idl, err := anchor.LoadIDLFile("../testdata/counter.json")
+	require.NoError(t, err)
+
+	files, err := Generate(idl, Options{Package: "counter"})
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+
+	for name, content := range files {
+		path := filepath.Join("internal", "counter", name)
+		if *update {
+			require.NoError(t, ioutil.WriteFile(path, content, 0644))
+			continue
+		}
+
+		expected, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), string(content), "%s is out of date, run `go test ./anchor/gen -update`", path)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		idl         string
+		expectedErr string
+	}{
+		{
+			name:        "no program ID",
+			idl:         `{"name": "p", "instructions": []}`,
+			expectedErr: `no program ID given and none found in IDL "p" metadata`,
+		},
+		{
+			name:        "unsupported type",
+			idl:         `{"name": "p", "metadata": {"address": "11111111111111111111111111111111"}, "instructions": [{"name": "do", "accounts": [], "args": [{"name": "v", "type": "u256"}]}]}`,
+			expectedErr: `unable to prepare IDL "p": instruction "do": field "v": unsupported type "u256"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			idl, err := anchor.ParseIDL([]byte(test.idl))
+			require.NoError(t, err)
+
+			_, err = Generate(idl, Options{Package: "p"})
+			assert.EqualError(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestGoName(t *testing.T) {
+	assert.Equal(t, "SetDataV2", goName("setDataV2"))
+	assert.Equal(t, "SystemProgram", goName("system_program"))
+	assert.Equal(t, "X0", goName("0"))
+}
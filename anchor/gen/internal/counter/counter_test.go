@@ -0,0 +1,154 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package counter
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/near/borsh-go"
+	"github.com/streamingfast/solana-go"
+	"github.com/streamingfast/solana-go/anchor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	counterKey       = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	authorityKey     = solana.MustPublicKeyFromBase58("SysvarRent111111111111111111111111111111111")
+	systemProgramKey = solana.MustPublicKeyFromBase58("11111111111111111111111111111111")
+)
+
+func newDynamicProgram(t *testing.T) *anchor.Program {
+	idl, err := anchor.LoadIDLFile("../../../testdata/counter.json")
+	require.NoError(t, err)
+
+	program, err := anchor.NewProgram(solana.PublicKey{}, idl)
+	require.NoError(t, err)
+	return program
+}
+
+func TestInitialize_RoundTrip(t *testing.T) {
+	label := "hello"
+	instruction := NewInitializeInstruction(42, &label, counterKey, authorityKey, systemProgramKey)
+
+	data, err := instruction.Data()
+	require.NoError(t, err)
+	assert.Equal(t, InitializeDiscriminator[:], data[:anchor.DISCRIMINATOR_SIZE])
+
+	accounts := instruction.Accounts()
+	require.Len(t, accounts, 3)
+	assert.True(t, accounts[0].IsWritable)
+	assert.True(t, accounts[1].IsSigner)
+
+	decoded, err := DecodeInstruction(accounts, data)
+	require.NoError(t, err)
+	assert.Equal(t, instruction, decoded)
+
+	dynamic, err := newDynamicProgram(t).DecodeInstruction(accounts, data)
+	require.NoError(t, err)
+	assert.Equal(t, "initialize", dynamic.Name)
+	start, found := dynamic.Args.Get("start")
+	require.True(t, found)
+	assert.Equal(t, uint64(42), start)
+	assert.Equal(t, "common.systemProgram", dynamic.Accounts[2].Name)
+}
+
+func TestSetDataV2_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		mode Mode
+		json string
+	}{
+		{"unit variant", Mode{Enum: 0}, `"Off"`},
+		{"struct variant", Mode{Enum: 1, Fixed: ModeFixed{Step: 7}}, `{"Fixed":{"step":7}}`},
+		{"tuple variant", Mode{Enum: 2, Range: ModeRange{Field0: -1, Field1: 5}}, `{"Range":[-1,5]}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			instruction := NewSetDataV2Instruction(test.mode, []int16{-2, 300}, [4]uint8{1, 2, 3, 4}, counterKey)
+
+			data, err := instruction.Data()
+			require.NoError(t, err)
+
+			decoded, err := DecodeInstruction(instruction.Accounts(), data)
+			require.NoError(t, err)
+			assert.Equal(t, instruction, decoded)
+
+			dynamic, err := newDynamicProgram(t).DecodeInstruction(instruction.Accounts(), data)
+			require.NoError(t, err)
+
+			value, found := dynamic.Args.Get("mode")
+			require.True(t, found)
+
+			mode, err := json.Marshal(value)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.json, string(mode))
+		})
+	}
+}
+
+func TestDecodeInstruction_UnknownDiscriminator(t *testing.T) {
+	_, err := DecodeInstruction(nil, make([]byte, 8))
+	require.Error(t, err)
+}
+
+func TestCounter_Decode(t *testing.T) {
+	account := Counter{
+		Authority: authorityKey,
+		Count:     3,
+		Total:     *big.NewInt(1000),
+		Mode:      Mode{Enum: 1, Fixed: ModeFixed{Step: 2}},
+	}
+
+	payload, err := borsh.Serialize(account)
+	require.NoError(t, err)
+	data := append(append(CounterDiscriminator[:], payload...), make([]byte, 16)...)
+
+	decoded, err := solana.DecodeAccount(counterKey, PROGRAM_ID, data)
+	require.NoError(t, err)
+	require.IsType(t, &Counter{}, decoded)
+	assert.Equal(t, uint64(3), decoded.(*Counter).Count)
+	assert.Equal(t, int64(1000), decoded.(*Counter).Total.Int64())
+	assert.Equal(t, ModeFixed{Step: 2}, decoded.(*Counter).Mode.Fixed)
+
+	assert.Error(t, (&Counter{}).Decode(payload))
+}
+
+func TestCounterIncremented_Decode(t *testing.T) {
+	delta := []byte{0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	data := append(append(append(CounterIncrementedDiscriminator[:], counterKey[:]...), 8, 0, 0, 0, 0, 0, 0, 0), delta...)
+
+	event := &CounterIncremented{}
+	require.NoError(t, event.Decode(data))
+	assert.Equal(t, uint64(8), event.Count)
+	assert.Equal(t, "-2", event.Delta.DecimalString())
+
+	payload, err := borsh.Serialize(*event)
+	require.NoError(t, err)
+	assert.Equal(t, data[anchor.DISCRIMINATOR_SIZE:], payload)
+}
+
+func TestErrorCode(t *testing.T) {
+	code, found := LookupError(6000)
+	require.True(t, found)
+	assert.Equal(t, ErrorCodeOverflow, code)
+	assert.Equal(t, "Overflow (6000): Counter overflowed", code.Error())
+
+	_, found = LookupError(6001)
+	assert.False(t, found)
+}
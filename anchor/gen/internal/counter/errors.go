@@ -0,0 +1,39 @@
+// Code generated by anchor-gen from the counter IDL. DO NOT EDIT.
+
+package counter
+
+import (
+	"fmt"
+)
+
+type ErrorCode uint32
+
+const (
+	ErrorCodeOverflow ErrorCode = 6000
+)
+
+var errorNames = map[ErrorCode]string{
+	ErrorCodeOverflow: "Overflow",
+}
+
+var errorMessages = map[ErrorCode]string{
+	ErrorCodeOverflow: "Counter overflowed",
+}
+
+// LookupError returns the program error matching the custom error code of a
+// failed instruction.
+func LookupError(code uint32) (ErrorCode, bool) {
+	_, found := errorNames[ErrorCode(code)]
+	return ErrorCode(code), found
+}
+
+func (e ErrorCode) Error() string {
+	name, found := errorNames[e]
+	if !found {
+		return fmt.Sprintf("unknown counter error code %d", uint32(e))
+	}
+	if msg := errorMessages[e]; msg != "" {
+		return fmt.Sprintf("%s (%d): %s", name, uint32(e), msg)
+	}
+	return fmt.Sprintf("%s (%d)", name, uint32(e))
+}
@@ -0,0 +1,349 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+
+	"github.com/streamingfast/solana-go/anchor"
+)
+
+// program is the IDL reshaped for the templates, with Go names resolved.
+type program struct {
+	Package   string
+	IDLName   string
+	ProgramID string
+
+	Instructions []*instruction
+	Types        []*typeDef
+	Accounts     []*typeDef
+	Events       []*typeDef
+	Errors       []*errorCode
+}
+
+type instruction struct {
+	Name          string
+	IDLName       string
+	Docs          []string
+	Discriminator anchor.Discriminator
+	Args          []*field
+	Accounts      []*account
+}
+
+type account struct {
+	Name      string
+	ParamName string
+	IsMut     bool
+	IsSigner  bool
+}
+
+type field struct {
+	Name      string
+	ParamName string
+	JSONName  string
+	Type      string
+	Docs      []string
+}
+
+type typeDef struct {
+	Name          string
+	Docs          []string
+	Discriminator anchor.Discriminator
+
+	// Struct
+	Fields []*field
+
+	// Enum, IsSimpleEnum when no variant holds fields
+	IsEnum       bool
+	IsSimpleEnum bool
+	Variants     []*variant
+}
+
+type variant struct {
+	Name   string
+	Type   string
+	Fields []*field
+}
+
+type errorCode struct {
+	Name string
+	Code uint32
+	Msg  string
+}
+
+func newProgram(idl *anchor.IDL, packageName string, programID string) (*program, error) {
+	p := &program{
+		Package:   packageName,
+		IDLName:   idl.Name,
+		ProgramID: programID,
+	}
+
+	taken := map[string]bool{}
+	for _, def := range idl.Types {
+		taken[goName(def.Name)] = true
+	}
+	for _, def := range idl.Accounts {
+		taken[goName(def.Name)] = true
+	}
+	for _, def := range idl.Events {
+		taken[goName(def.Name)] = true
+	}
+
+	for _, def := range idl.Types {
+		out, err := p.newTypeDef(def.Name, def.Docs, def.Type)
+		if err != nil {
+			return nil, fmt.Errorf("type %q: %w", def.Name, err)
+		}
+		p.Types = append(p.Types, out)
+	}
+
+	for _, def := range idl.Accounts {
+		out, err := p.newTypeDef(def.Name, def.Docs, def.Type)
+		if err != nil {
+			return nil, fmt.Errorf("account %q: %w", def.Name, err)
+		}
+		if out.IsEnum {
+			return nil, fmt.Errorf("account %q: enum accounts are not supported", def.Name)
+		}
+		out.Discriminator = anchor.AccountDiscriminator(def.Name)
+		p.Accounts = append(p.Accounts, out)
+	}
+
+	for _, def := range idl.Events {
+		fields := make([]anchor.IDLField, len(def.Fields))
+		for idx, f := range def.Fields {
+			fields[idx] = anchor.IDLField{Name: f.Name, Type: f.Type}
+		}
+
+		out, err := p.newTypeDef(def.Name, nil, anchor.IDLTypeDefType{Kind: anchor.IDLTypeDefKindStruct, Fields: fields})
+		if err != nil {
+			return nil, fmt.Errorf("event %q: %w", def.Name, err)
+		}
+		out.Discriminator = anchor.EventDiscriminator(def.Name)
+		p.Events = append(p.Events, out)
+	}
+
+	for _, def := range idl.Instructions {
+		out, err := p.newInstruction(def, taken)
+		if err != nil {
+			return nil, fmt.Errorf("instruction %q: %w", def.Name, err)
+		}
+		p.Instructions = append(p.Instructions, out)
+	}
+
+	for _, def := range idl.Errors {
+		p.Errors = append(p.Errors, &errorCode{Name: goName(def.Name), Code: def.Code, Msg: def.Msg})
+	}
+
+	return p, nil
+}
+
+func (p *program) newInstruction(def anchor.IDLInstruction, taken map[string]bool) (*instruction, error) {
+	name := goName(def.Name)
+	if taken[name] || name == "Instruction" {
+		// Same naming as serum's instructions, used only to avoid clashes
+		name = "Instruction" + name
+	}
+
+	out := &instruction{
+		Name:          name,
+		IDLName:       def.Name,
+		Docs:          def.Docs,
+		Discriminator: anchor.InstructionDiscriminator(def.Name),
+	}
+
+	params := map[string]bool{}
+	for _, arg := range def.Args {
+		f, err := p.newField(arg)
+		if err != nil {
+			return nil, err
+		}
+		if f.Name == "Accounts" {
+			return nil, fmt.Errorf("argument %q clashes with the generated Accounts field", arg.Name)
+		}
+		f.ParamName = uniqueParamName(f.Name, params, "Arg")
+		out.Args = append(out.Args, f)
+	}
+
+	for _, item := range flattenAccounts(def.Accounts, "") {
+		out.Accounts = append(out.Accounts, &account{
+			Name:      item.name,
+			ParamName: uniqueParamName(item.name, params, "Account"),
+			IsMut:     item.IsMut,
+			IsSigner:  item.IsSigner,
+		})
+	}
+	return out, nil
+}
+
+type flatAccount struct {
+	anchor.IDLAccountItem
+	name string
+}
+
+func flattenAccounts(items []anchor.IDLAccountItem, prefix string) (out []flatAccount) {
+	for _, item := range items {
+		if item.IsGroup() {
+			out = append(out, flattenAccounts(item.Accounts, prefix+goName(item.Name))...)
+			continue
+		}
+		out = append(out, flatAccount{IDLAccountItem: item, name: prefix + goName(item.Name)})
+	}
+	return out
+}
+
+func (p *program) newTypeDef(name string, docs []string, def anchor.IDLTypeDefType) (*typeDef, error) {
+	out := &typeDef{Name: goName(name), Docs: docs}
+
+	switch def.Kind {
+	case anchor.IDLTypeDefKindStruct:
+		for _, f := range def.Fields {
+			generated, err := p.newField(f)
+			if err != nil {
+				return nil, err
+			}
+			out.Fields = append(out.Fields, generated)
+		}
+
+	case anchor.IDLTypeDefKindEnum:
+		out.IsEnum = true
+		out.IsSimpleEnum = true
+		for _, v := range def.Variants {
+			generated := &variant{Name: goName(v.Name), Type: out.Name + goName(v.Name)}
+			for _, f := range v.NamedFields {
+				generatedField, err := p.newField(f)
+				if err != nil {
+					return nil, fmt.Errorf("variant %q: %w", v.Name, err)
+				}
+				generated.Fields = append(generated.Fields, generatedField)
+			}
+			for idx, t := range v.TupleFields {
+				generatedField, err := p.newField(anchor.IDLField{Name: fmt.Sprintf("field%d", idx), Type: t})
+				if err != nil {
+					return nil, fmt.Errorf("variant %q: %w", v.Name, err)
+				}
+				generated.Fields = append(generated.Fields, generatedField)
+			}
+			if len(generated.Fields) > 0 {
+				out.IsSimpleEnum = false
+			}
+			out.Variants = append(out.Variants, generated)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported type kind %q", def.Kind)
+	}
+	return out, nil
+}
+
+func (p *program) newField(f anchor.IDLField) (*field, error) {
+	typ, err := p.goType(f.Type)
+	if err != nil {
+		return nil, fmt.Errorf("field %q: %w", f.Name, err)
+	}
+	return &field{Name: goName(f.Name), JSONName: f.Name, Type: typ, Docs: f.Docs}, nil
+}
+
+// goType maps IDL types to the Go types borsh-go (de)serializes the same way.
+func (p *program) goType(t anchor.IDLType) (string, error) {
+	switch {
+	case t.Primitive != "":
+		switch t.Primitive {
+		case anchor.IDLTypeBool:
+			return "bool", nil
+		case anchor.IDLTypeU8, anchor.IDLTypeU16, anchor.IDLTypeU32, anchor.IDLTypeU64:
+			return "uint" + t.Primitive[1:], nil
+		case anchor.IDLTypeI8, anchor.IDLTypeI16, anchor.IDLTypeI32, anchor.IDLTypeI64:
+			return "int" + t.Primitive[1:], nil
+		case anchor.IDLTypeF32, anchor.IDLTypeF64:
+			return "float" + t.Primitive[1:], nil
+		case anchor.IDLTypeU128:
+			return "big.Int", nil
+		case anchor.IDLTypeI128:
+			// borsh-go only handles big.Int as unsigned, the low and high
+			// words of bin.Int128 are laid out as a little-endian i128.
+			return "bin.Int128", nil
+		case anchor.IDLTypeBytes:
+			return "[]byte", nil
+		case anchor.IDLTypeString:
+			return "string", nil
+		case anchor.IDLTypePublicKey:
+			return "solana.PublicKey", nil
+		}
+		return "", fmt.Errorf("unsupported type %q", t.Primitive)
+
+	case t.Vec != nil:
+		elem, err := p.goType(*t.Vec)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+
+	case t.Array != nil:
+		elem, err := p.goType(t.Array.Elem)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("[%d]%s", t.Array.Len, elem), nil
+
+	case t.Option != nil:
+		elem, err := p.goType(*t.Option)
+		if err != nil {
+			return "", err
+		}
+		return "*" + elem, nil
+
+	case t.Defined != "":
+		return goName(t.Defined), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %s", t)
+}
+
+// goName turns IDL camelCase or snake_case names into exported Go names.
+func goName(name string) string {
+	out := strings.Builder{}
+	upperNext := true
+	for _, r := range name {
+		if r == '_' {
+			upperNext = true
+			continue
+		}
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+		out.WriteRune(r)
+	}
+
+	if out.Len() == 0 || !unicode.IsLetter([]rune(out.String())[0]) {
+		return "X" + out.String()
+	}
+	return out.String()
+}
+
+func uniqueParamName(name string, taken map[string]bool, suffix string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	out := string(runes)
+	if token.IsKeyword(out) || out == "solana" || out == "bin" || taken[out] {
+		out += suffix
+	}
+	taken[out] = true
+	return out
+}
@@ -0,0 +1,300 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+const instructionsTemplate = `
+var PROGRAM_ID = solana.MustPublicKeyFromBase58("{{ .ProgramID }}")
+
+func init() {
+	solana.RegisterInstructionDecoder(PROGRAM_ID, registryDecodeInstruction)
+}
+
+func registryDecodeInstruction(accounts []*solana.AccountMeta, data []byte) (interface{}, error) {
+	inst, err := DecodeInstruction(accounts, data)
+	if err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+func DecodeInstruction(accounts []*solana.AccountMeta, data []byte) (*Instruction, error) {
+	if len(data) < anchor.DISCRIMINATOR_SIZE {
+		return nil, fmt.Errorf("unable to decode instruction for {{ .IDLName }} program: expected at least %d bytes, got %d", anchor.DISCRIMINATOR_SIZE, len(data))
+	}
+
+	var discriminator anchor.Discriminator
+	copy(discriminator[:], data)
+
+	var inst Instruction
+	var err error
+	switch discriminator {
+{{- range $idx, $ix := .Instructions }}
+	case {{ $ix.Name }}Discriminator:
+		impl := &{{ $ix.Name }}{}
+		err = borsh.Deserialize(impl, data[anchor.DISCRIMINATOR_SIZE:])
+		inst.BaseVariant = bin.BaseVariant{TypeID: {{ $idx }}, Impl: impl}
+{{- end }}
+	default:
+		return nil, fmt.Errorf("unable to decode instruction for {{ .IDLName }} program: unknown discriminator %s", discriminator)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode instruction for {{ .IDLName }} program: %w", err)
+	}
+
+	if v, ok := inst.Impl.(solana.AccountSettable); ok {
+		err := v.SetAccounts(accounts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set accounts for instruction: %w", err)
+		}
+	}
+
+	return &inst, nil
+}
+
+var (
+{{- range .Instructions }}
+	{{ .Name }}Discriminator = anchor.Discriminator{ {{- bytes .Discriminator -}} }
+{{- end }}
+)
+
+var InstructionDefVariant = bin.NewVariantDefinition(bin.Uint8TypeIDEncoding, []bin.VariantType{
+{{- range .Instructions }}
+	{Name: "{{ .IDLName }}", Type: (*{{ .Name }})(nil)},
+{{- end }}
+})
+
+type Instruction struct {
+	bin.BaseVariant
+}
+
+func (i *Instruction) Accounts() (out []*solana.AccountMeta) {
+	switch i.TypeID {
+{{- range $idx, $ix := .Instructions }}
+	case {{ $idx }}:
+{{- if $ix.Accounts }}
+		accounts := i.Impl.(*{{ $ix.Name }}).Accounts
+		out = []*solana.AccountMeta{ {{- range $aidx, $acct := $ix.Accounts }}{{ if $aidx }}, {{ end }}accounts.{{ $acct.Name }}{{ end -}} }
+{{- end }}
+{{- end }}
+	}
+	return
+}
+
+func (i *Instruction) ProgramID() solana.PublicKey {
+	return PROGRAM_ID
+}
+
+func (i *Instruction) Data() ([]byte, error) {
+	var discriminator anchor.Discriminator
+	var args interface{}
+	switch impl := i.Impl.(type) {
+{{- range .Instructions }}
+	case *{{ .Name }}:
+		discriminator, args = {{ .Name }}Discriminator, *impl
+{{- end }}
+	default:
+		return nil, fmt.Errorf("unable to encode instruction: unknown instruction type %T", i.Impl)
+	}
+
+	data, err := borsh.Serialize(args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode instruction: %w", err)
+	}
+	return append(discriminator[:], data...), nil
+}
+
+func (i *Instruction) TextEncode(encoder *text.Encoder, option *text.Option) error {
+	return encoder.Encode(i.Impl, option)
+}
+{{ range $ix := .Instructions }}
+type {{ $ix.Name }}Accounts struct {
+{{- range $ix.Accounts }}
+	{{ .Name }} *solana.AccountMeta ` + "`" + `text:"linear,notype"` + "`" + `
+{{- end }}
+}
+
+{{ docs $ix.Docs -}}
+type {{ $ix.Name }} struct {
+{{- range $ix.Args }}
+	{{ docs .Docs }}{{ .Name }} {{ .Type }} ` + "`" + `json:"{{ .JSONName }}"` + "`" + `
+{{- end }}
+{{ if $ix.Args }}
+{{ end -}}
+	Accounts *{{ $ix.Name }}Accounts ` + "`" + `json:"-" borsh_skip:"true"` + "`" + `
+}
+
+func (i *{{ $ix.Name }}) SetAccounts(accounts []*solana.AccountMeta) error {
+	if len(accounts) < {{ len $ix.Accounts }} {
+		return fmt.Errorf("insufficient account, {{ $ix.Name }} requires at-least {{ len $ix.Accounts }} accounts not %d", len(accounts))
+	}
+	i.Accounts = &{{ $ix.Name }}Accounts{
+{{- range $aidx, $acct := $ix.Accounts }}
+		{{ $acct.Name }}: accounts[{{ $aidx }}],
+{{- end }}
+	}
+	return nil
+}
+
+func New{{ $ix.Name }}Instruction(
+{{- range $ix.Args }}
+	{{ .ParamName }} {{ .Type }},
+{{- end }}
+{{- range $ix.Accounts }}
+	{{ .ParamName }} solana.PublicKey,
+{{- end }}
+) *Instruction {
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: InstructionDefVariant.TypeID("{{ $ix.IDLName }}"),
+			Impl: &{{ $ix.Name }}{
+{{- range $ix.Args }}
+				{{ .Name }}: {{ .ParamName }},
+{{- end }}
+				Accounts: &{{ $ix.Name }}Accounts{
+{{- range $ix.Accounts }}
+					{{ .Name }}: &solana.AccountMeta{PublicKey: {{ .ParamName }}{{ if .IsSigner }}, IsSigner: true{{ end }}{{ if .IsMut }}, IsWritable: true{{ end }}},
+{{- end }}
+				},
+			},
+		},
+	}
+}
+{{ end -}}
+`
+
+const typesTemplate = `
+{{- if .Accounts }}
+func init() {
+{{- range .Accounts }}
+	solana.RegisterAccountDecoder(PROGRAM_ID, solana.MatchDiscriminator({{ .Name }}Discriminator[:]), func(key solana.PublicKey, data []byte) (interface{}, error) {
+		account := &{{ .Name }}{}
+		return account, account.Decode(data)
+	})
+{{- end }}
+}
+{{ end }}
+{{- range .Types }}
+{{ template "typeDef" . }}
+{{- end }}
+{{- range .Accounts }}
+var {{ .Name }}Discriminator = anchor.Discriminator{ {{- bytes .Discriminator -}} }
+{{ template "typeDef" . }}
+func (a *{{ .Name }}) Decode(in []byte) error {
+	if len(in) < anchor.DISCRIMINATOR_SIZE || !bytes.Equal(in[:anchor.DISCRIMINATOR_SIZE], {{ .Name }}Discriminator[:]) {
+		return fmt.Errorf("unpack: not a {{ .Name }} account")
+	}
+	if err := borsh.Deserialize(a, in[anchor.DISCRIMINATOR_SIZE:]); err != nil {
+		return fmt.Errorf("unpack: %w", err)
+	}
+	return nil
+}
+{{ end }}
+{{- range .Events }}
+var {{ .Name }}Discriminator = anchor.Discriminator{ {{- bytes .Discriminator -}} }
+{{ template "typeDef" . }}
+// Decode decodes the event from the base64 decoded content of a
+// ` + "`" + `Program data: ` + "`" + ` log line.
+func (e *{{ .Name }}) Decode(in []byte) error {
+	if len(in) < anchor.DISCRIMINATOR_SIZE || !bytes.Equal(in[:anchor.DISCRIMINATOR_SIZE], {{ .Name }}Discriminator[:]) {
+		return fmt.Errorf("unpack: not a {{ .Name }} event")
+	}
+	if err := borsh.Deserialize(e, in[anchor.DISCRIMINATOR_SIZE:]); err != nil {
+		return fmt.Errorf("unpack: %w", err)
+	}
+	return nil
+}
+{{ end -}}
+
+`
+
+// typeDefTemplates are shared by the types of the IDL, its accounts and events.
+const typeDefTemplates = `
+{{- define "fields" }}
+{{- range . }}
+	{{ docs .Docs }}{{ .Name }} {{ .Type }} ` + "`" + `json:"{{ .JSONName }}"` + "`" + `
+{{- end }}
+{{- end }}
+
+{{- define "typeDef" }}
+{{- if not .IsEnum }}
+{{ docs .Docs -}}
+type {{ .Name }} struct {
+{{- template "fields" .Fields }}
+}
+{{ else if .IsSimpleEnum }}
+{{ docs .Docs -}}
+type {{ .Name }} borsh.Enum
+
+const (
+{{- range $idx, $variant := .Variants }}
+	{{ $variant.Type }}{{ if not $idx }} {{ $.Name }} = iota{{ end }}
+{{- end }}
+)
+{{ else }}
+{{ docs .Docs -}}
+type {{ .Name }} struct {
+	Enum borsh.Enum ` + "`" + `borsh_enum:"true"` + "`" + `
+{{- range .Variants }}
+	{{ .Name }} {{ .Type }}
+{{- end }}
+}
+{{ range .Variants }}
+type {{ .Type }} struct {
+{{- template "fields" .Fields }}
+}
+{{ end }}
+{{- end }}
+{{- end }}
+`
+
+const errorsTemplate = `
+type ErrorCode uint32
+
+const (
+{{- range .Errors }}
+	ErrorCode{{ .Name }} ErrorCode = {{ .Code }}
+{{- end }}
+)
+
+var errorNames = map[ErrorCode]string{
+{{- range .Errors }}
+	ErrorCode{{ .Name }}: "{{ .Name }}",
+{{- end }}
+}
+
+var errorMessages = map[ErrorCode]string{
+{{- range .Errors }}
+	ErrorCode{{ .Name }}: {{ printf "%q" .Msg }},
+{{- end }}
+}
+
+// LookupError returns the program error matching the custom error code of a
+// failed instruction.
+func LookupError(code uint32) (ErrorCode, bool) {
+	_, found := errorNames[ErrorCode(code)]
+	return ErrorCode(code), found
+}
+
+func (e ErrorCode) Error() string {
+	name, found := errorNames[e]
+	if !found {
+		return fmt.Sprintf("unknown {{ .IDLName }} error code %d", uint32(e))
+	}
+	if msg := errorMessages[e]; msg != "" {
+		return fmt.Sprintf("%s (%d): %s", name, uint32(e), msg)
+	}
+	return fmt.Sprintf("%s (%d)", name, uint32(e))
+}
+`
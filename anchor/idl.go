@@ -0,0 +1,314 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// IDL is the interface description of an Anchor program, as found in the
+// `target/idl/<program>.json` file generated by `anchor build` or published
+// on-chain.
+type IDL struct {
+	Version      string           `json:"version"`
+	Name         string           `json:"name"`
+	Docs         []string         `json:"docs,omitempty"`
+	Instructions []IDLInstruction `json:"instructions"`
+	Accounts     []IDLTypeDef     `json:"accounts,omitempty"`
+	Types        []IDLTypeDef     `json:"types,omitempty"`
+	Events       []IDLEvent       `json:"events,omitempty"`
+	Errors       []IDLErrorCode   `json:"errors,omitempty"`
+	Constants    []IDLConstant    `json:"constants,omitempty"`
+	Metadata     *IDLMetadata     `json:"metadata,omitempty"`
+}
+
+type IDLMetadata struct {
+	Address string `json:"address,omitempty"`
+}
+
+type IDLInstruction struct {
+	Name     string           `json:"name"`
+	Docs     []string         `json:"docs,omitempty"`
+	Accounts []IDLAccountItem `json:"accounts"`
+	Args     []IDLField       `json:"args"`
+	Returns  *IDLType         `json:"returns,omitempty"`
+}
+
+// IDLAccountItem is either a single account of an instruction, or a group of
+// accounts (a nested `Accounts` struct) when Accounts is set.
+type IDLAccountItem struct {
+	Name     string           `json:"name"`
+	Docs     []string         `json:"docs,omitempty"`
+	IsMut    bool             `json:"isMut"`
+	IsSigner bool             `json:"isSigner"`
+	Optional bool             `json:"isOptional,omitempty"`
+	Accounts []IDLAccountItem `json:"accounts,omitempty"`
+}
+
+func (i IDLAccountItem) IsGroup() bool {
+	return i.Accounts != nil
+}
+
+type IDLField struct {
+	Name string   `json:"name"`
+	Docs []string `json:"docs,omitempty"`
+	Type IDLType  `json:"type"`
+}
+
+type IDLEvent struct {
+	Name   string          `json:"name"`
+	Fields []IDLEventField `json:"fields"`
+}
+
+type IDLEventField struct {
+	Name  string  `json:"name"`
+	Type  IDLType `json:"type"`
+	Index bool    `json:"index"`
+}
+
+type IDLErrorCode struct {
+	Code uint32 `json:"code"`
+	Name string `json:"name"`
+	Msg  string `json:"msg,omitempty"`
+}
+
+type IDLConstant struct {
+	Name  string  `json:"name"`
+	Type  IDLType `json:"type"`
+	Value string  `json:"value"`
+}
+
+type IDLTypeDefKind string
+
+const (
+	IDLTypeDefKindStruct IDLTypeDefKind = "struct"
+	IDLTypeDefKindEnum   IDLTypeDefKind = "enum"
+)
+
+// IDLTypeDef is a named struct or enum type, used for both `accounts` and
+// `types` entries of the IDL.
+type IDLTypeDef struct {
+	Name string         `json:"name"`
+	Docs []string       `json:"docs,omitempty"`
+	Type IDLTypeDefType `json:"type"`
+}
+
+type IDLTypeDefType struct {
+	Kind     IDLTypeDefKind   `json:"kind"`
+	Fields   []IDLField       `json:"fields,omitempty"`
+	Variants []IDLEnumVariant `json:"variants,omitempty"`
+}
+
+// IDLEnumVariant has either no fields, named fields (NamedFields) or tuple
+// fields (TupleFields).
+type IDLEnumVariant struct {
+	Name        string
+	NamedFields []IDLField
+	TupleFields []IDLType
+}
+
+func (v IDLEnumVariant) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{"name": v.Name}
+	if v.NamedFields != nil {
+		out["fields"] = v.NamedFields
+	} else if v.TupleFields != nil {
+		out["fields"] = v.TupleFields
+	}
+	return json.Marshal(out)
+}
+
+func (v *IDLEnumVariant) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Name   string            `json:"name"`
+		Fields []json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	v.Name = raw.Name
+	for _, field := range raw.Fields {
+		// Named fields are objects holding a `name`, tuple fields are bare types
+		var named IDLField
+		if err := json.Unmarshal(field, &named); err == nil && named.Name != "" {
+			v.NamedFields = append(v.NamedFields, named)
+			continue
+		}
+
+		var tuple IDLType
+		if err := json.Unmarshal(field, &tuple); err != nil {
+			return fmt.Errorf("invalid field of enum variant %q: %w", raw.Name, err)
+		}
+		v.TupleFields = append(v.TupleFields, tuple)
+	}
+	return nil
+}
+
+// IDLType is the type of a field, either a primitive (Primitive is set) or
+// a composite type (exactly one of the other fields is set).
+type IDLType struct {
+	Primitive string
+	Vec       *IDLType
+	Option    *IDLType
+	COption   *IDLType
+	Array     *IDLArrayType
+	Defined   string
+}
+
+type IDLArrayType struct {
+	Elem IDLType
+	Len  int
+}
+
+// Primitive types names
+const (
+	IDLTypeBool      = "bool"
+	IDLTypeU8        = "u8"
+	IDLTypeI8        = "i8"
+	IDLTypeU16       = "u16"
+	IDLTypeI16       = "i16"
+	IDLTypeU32       = "u32"
+	IDLTypeI32       = "i32"
+	IDLTypeF32       = "f32"
+	IDLTypeU64       = "u64"
+	IDLTypeI64       = "i64"
+	IDLTypeF64       = "f64"
+	IDLTypeU128      = "u128"
+	IDLTypeI128      = "i128"
+	IDLTypeBytes     = "bytes"
+	IDLTypeString    = "string"
+	IDLTypePublicKey = "publicKey"
+)
+
+func (t IDLType) String() string {
+	switch {
+	case t.Primitive != "":
+		return t.Primitive
+	case t.Vec != nil:
+		return fmt.Sprintf("vec<%s>", t.Vec)
+	case t.Option != nil:
+		return fmt.Sprintf("option<%s>", t.Option)
+	case t.COption != nil:
+		return fmt.Sprintf("coption<%s>", t.COption)
+	case t.Array != nil:
+		return fmt.Sprintf("[%s; %d]", t.Array.Elem, t.Array.Len)
+	default:
+		return t.Defined
+	}
+}
+
+func (t IDLType) MarshalJSON() ([]byte, error) {
+	switch {
+	case t.Primitive != "":
+		return json.Marshal(t.Primitive)
+	case t.Vec != nil:
+		return json.Marshal(map[string]interface{}{"vec": t.Vec})
+	case t.Option != nil:
+		return json.Marshal(map[string]interface{}{"option": t.Option})
+	case t.COption != nil:
+		return json.Marshal(map[string]interface{}{"coption": t.COption})
+	case t.Array != nil:
+		return json.Marshal(map[string]interface{}{"array": []interface{}{t.Array.Elem, t.Array.Len}})
+	default:
+		return json.Marshal(map[string]interface{}{"defined": t.Defined})
+	}
+}
+
+func (t *IDLType) UnmarshalJSON(data []byte) error {
+	var primitive string
+	if err := json.Unmarshal(data, &primitive); err == nil {
+		*t = IDLType{Primitive: primitive}
+		return nil
+	}
+
+	var composite struct {
+		Vec     *IDLType          `json:"vec"`
+		Option  *IDLType          `json:"option"`
+		COption *IDLType          `json:"coption"`
+		Array   []json.RawMessage `json:"array"`
+		Defined string            `json:"defined"`
+	}
+	if err := json.Unmarshal(data, &composite); err != nil {
+		return fmt.Errorf("invalid type %s: %w", string(data), err)
+	}
+
+	*t = IDLType{Vec: composite.Vec, Option: composite.Option, COption: composite.COption, Defined: composite.Defined}
+	if composite.Array != nil {
+		if len(composite.Array) != 2 {
+			return fmt.Errorf("invalid array type %s, expected [type, length]", string(data))
+		}
+
+		t.Array = &IDLArrayType{}
+		if err := json.Unmarshal(composite.Array[0], &t.Array.Elem); err != nil {
+			return fmt.Errorf("invalid array element type: %w", err)
+		}
+		if err := json.Unmarshal(composite.Array[1], &t.Array.Len); err != nil {
+			return fmt.Errorf("invalid array length %s: %w", string(composite.Array[1]), err)
+		}
+	}
+
+	if t.Vec == nil && t.Option == nil && t.COption == nil && t.Array == nil && t.Defined == "" {
+		return fmt.Errorf("unknown type %s", string(data))
+	}
+	return nil
+}
+
+// ParseIDL parses an Anchor IDL in its JSON form.
+func ParseIDL(data []byte) (*IDL, error) {
+	var idl *IDL
+	if err := json.Unmarshal(data, &idl); err != nil {
+		return nil, fmt.Errorf("unable to parse IDL: %w", err)
+	}
+	if idl == nil {
+		return nil, fmt.Errorf("unable to parse IDL: empty document")
+	}
+	return idl, nil
+}
+
+func LoadIDLFile(file string) (*IDL, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read IDL file: %w", err)
+	}
+	return ParseIDL(content)
+}
+
+// LookupType returns the type definition named `name`, searching `types` and
+// then `accounts` since both can be referenced by `defined` types.
+func (i *IDL) LookupType(name string) (*IDLTypeDef, bool) {
+	for idx := range i.Types {
+		if i.Types[idx].Name == name {
+			return &i.Types[idx], true
+		}
+	}
+	for idx := range i.Accounts {
+		if i.Accounts[idx].Name == name {
+			return &i.Accounts[idx], true
+		}
+	}
+	return nil, false
+}
+
+// LookupError returns the custom program error with the given code.
+func (i *IDL) LookupError(code uint32) (*IDLErrorCode, bool) {
+	for idx := range i.Errors {
+		if i.Errors[idx].Code == code {
+			return &i.Errors[idx], true
+		}
+	}
+	return nil, false
+}
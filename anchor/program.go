@@ -0,0 +1,257 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"fmt"
+
+	"github.com/streamingfast/solana-go"
+)
+
+// Program decodes the instructions, accounts and events of an Anchor program
+// dynamically, using its IDL.
+type Program struct {
+	ID  solana.PublicKey
+	IDL *IDL
+
+	instructions map[Discriminator]*IDLInstruction
+	accounts     map[Discriminator]*IDLTypeDef
+	events       map[Discriminator]*IDLEvent
+}
+
+// NewProgram prepares the dynamic decoding of the program `programID`
+// described by `idl`. When `programID` is the zero key, the address found in
+// the IDL metadata is used.
+func NewProgram(programID solana.PublicKey, idl *IDL) (*Program, error) {
+	if programID.IsZero() {
+		if idl.Metadata == nil || idl.Metadata.Address == "" {
+			return nil, fmt.Errorf("no program ID given and none found in IDL %q metadata", idl.Name)
+		}
+
+		var err error
+		programID, err = solana.PublicKeyFromBase58(idl.Metadata.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid program address in IDL %q metadata: %w", idl.Name, err)
+		}
+	}
+
+	p := &Program{
+		ID:           programID,
+		IDL:          idl,
+		instructions: map[Discriminator]*IDLInstruction{},
+		accounts:     map[Discriminator]*IDLTypeDef{},
+		events:       map[Discriminator]*IDLEvent{},
+	}
+
+	for idx := range idl.Instructions {
+		instruction := &idl.Instructions[idx]
+		discriminator := InstructionDiscriminator(instruction.Name)
+		if other, found := p.instructions[discriminator]; found {
+			return nil, fmt.Errorf("instructions %q and %q have the same discriminator %s", other.Name, instruction.Name, discriminator)
+		}
+		p.instructions[discriminator] = instruction
+	}
+	for idx := range idl.Accounts {
+		account := &idl.Accounts[idx]
+		discriminator := AccountDiscriminator(account.Name)
+		if other, found := p.accounts[discriminator]; found {
+			return nil, fmt.Errorf("accounts %q and %q have the same discriminator %s", other.Name, account.Name, discriminator)
+		}
+		p.accounts[discriminator] = account
+	}
+	for idx := range idl.Events {
+		event := &idl.Events[idx]
+		discriminator := EventDiscriminator(event.Name)
+		if other, found := p.events[discriminator]; found {
+			return nil, fmt.Errorf("events %q and %q have the same discriminator %s", other.Name, event.Name, discriminator)
+		}
+		p.events[discriminator] = event
+	}
+
+	return p, nil
+}
+
+// Instruction is an instruction decoded from its IDL definition.
+type Instruction struct {
+	ProgramID solana.PublicKey      `json:"programId"`
+	Name      string                `json:"name"`
+	Args      *Struct               `json:"args"`
+	Accounts  []*InstructionAccount `json:"accounts"`
+	// RemainingAccounts are the accounts passed in addition to the ones
+	// declared in the IDL.
+	RemainingAccounts []*solana.AccountMeta `json:"remainingAccounts,omitempty"`
+}
+
+// InstructionAccount is an account of a decoded instruction, named after the
+// IDL. Accounts of nested groups are named `<group>.<account>`.
+type InstructionAccount struct {
+	Name       string           `json:"name"`
+	PublicKey  solana.PublicKey `json:"pubkey"`
+	IsSigner   bool             `json:"isSigner"`
+	IsWritable bool             `json:"isWritable"`
+}
+
+// Account is an account decoded from its IDL definition.
+type Account struct {
+	Key  solana.PublicKey `json:"key"`
+	Name string           `json:"name"`
+	Data interface{}      `json:"data"`
+}
+
+// Event is an event decoded from its IDL definition.
+type Event struct {
+	Name string  `json:"name"`
+	Data *Struct `json:"data"`
+	// Err is set by Program.DecodeEvents when the event's data does not
+	// match its IDL definition, Data being nil.
+	Err error `json:"-"`
+}
+
+func (p *Program) DecodeInstruction(accounts []*solana.AccountMeta, data []byte) (*Instruction, error) {
+	if len(data) < DISCRIMINATOR_SIZE {
+		return nil, fmt.Errorf("instruction data too short, expected at least %d bytes, got %d", DISCRIMINATOR_SIZE, len(data))
+	}
+
+	var discriminator Discriminator
+	copy(discriminator[:], data)
+	definition, found := p.instructions[discriminator]
+	if !found {
+		return nil, fmt.Errorf("unknown instruction discriminator %s for program %q", discriminator, p.IDL.Name)
+	}
+
+	args, err := newValueDecoder(p.IDL, data[DISCRIMINATOR_SIZE:]).decodeFields(definition.Args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode instruction %q args: %w", definition.Name, err)
+	}
+
+	out := &Instruction{
+		ProgramID: p.ID,
+		Name:      definition.Name,
+		Args:      args,
+	}
+
+	names := flattenAccountNames(definition.Accounts, "")
+	if len(accounts) < len(names) {
+		return nil, fmt.Errorf("insufficient account, %s requires at-least %d accounts not %d", definition.Name, len(names), len(accounts))
+	}
+
+	out.Accounts = make([]*InstructionAccount, len(names))
+	for idx, name := range names {
+		out.Accounts[idx] = &InstructionAccount{
+			Name:       name,
+			PublicKey:  accounts[idx].PublicKey,
+			IsSigner:   accounts[idx].IsSigner,
+			IsWritable: accounts[idx].IsWritable,
+		}
+	}
+	if len(accounts) > len(names) {
+		out.RemainingAccounts = accounts[len(names):]
+	}
+
+	return out, nil
+}
+
+func flattenAccountNames(items []IDLAccountItem, prefix string) (out []string) {
+	for _, item := range items {
+		if item.IsGroup() {
+			out = append(out, flattenAccountNames(item.Accounts, prefix+item.Name+".")...)
+			continue
+		}
+		out = append(out, prefix+item.Name)
+	}
+	return out
+}
+
+func (p *Program) DecodeAccount(key solana.PublicKey, data []byte) (*Account, error) {
+	if len(data) < DISCRIMINATOR_SIZE {
+		return nil, fmt.Errorf("account data too short, expected at least %d bytes, got %d", DISCRIMINATOR_SIZE, len(data))
+	}
+
+	var discriminator Discriminator
+	copy(discriminator[:], data)
+	definition, found := p.accounts[discriminator]
+	if !found {
+		return nil, fmt.Errorf("unknown account discriminator %s for program %q", discriminator, p.IDL.Name)
+	}
+
+	// Accounts are usually allocated larger than their content, trailing
+	// bytes are ignored.
+	value, err := newValueDecoder(p.IDL, data[DISCRIMINATOR_SIZE:]).decodeTypeDef(definition)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode account %q: %w", definition.Name, err)
+	}
+
+	return &Account{Key: key, Name: definition.Name, Data: value}, nil
+}
+
+// DecodeEvent decodes the data of an event, that is the base64 decoded content
+// of a `Program data: ` log line.
+func (p *Program) DecodeEvent(data []byte) (*Event, error) {
+	if len(data) < DISCRIMINATOR_SIZE {
+		return nil, fmt.Errorf("event data too short, expected at least %d bytes, got %d", DISCRIMINATOR_SIZE, len(data))
+	}
+
+	var discriminator Discriminator
+	copy(discriminator[:], data)
+	definition, found := p.events[discriminator]
+	if !found {
+		return nil, fmt.Errorf("unknown event discriminator %s for program %q", discriminator, p.IDL.Name)
+	}
+
+	fields := make([]IDLField, len(definition.Fields))
+	for idx, field := range definition.Fields {
+		fields[idx] = IDLField{Name: field.Name, Type: field.Type}
+	}
+
+	value, err := newValueDecoder(p.IDL, data[DISCRIMINATOR_SIZE:]).decodeFields(fields)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode event %q: %w", definition.Name, err)
+	}
+
+	return &Event{Name: definition.Name, Data: value}, nil
+}
+
+// Register registers the program's instruction decoder and account decoders
+// in solana.DefaultRegistry and solana.DefaultAccountDecoderRegistry.
+func (p *Program) Register() error {
+	return p.RegisterIn(solana.DefaultRegistry, solana.DefaultAccountDecoderRegistry)
+}
+
+// RegisterIn registers the program's instruction decoder in `instructions` and
+// a decoder for each of its account types in `accounts`, a nil registry being
+// skipped.
+func (p *Program) RegisterIn(instructions *solana.Registry, accounts *solana.AccountDecoderRegistry) error {
+	if instructions != nil {
+		err := instructions.Register(p.ID, func(accounts []*solana.AccountMeta, data []byte) (interface{}, error) {
+			return p.DecodeInstruction(accounts, data)
+		})
+		if err != nil {
+			return fmt.Errorf("unable to register instruction decoder: %w", err)
+		}
+	}
+
+	if accounts != nil {
+		for discriminator := range p.accounts {
+			discriminator := discriminator
+			err := accounts.Register(p.ID, solana.MatchDiscriminator(discriminator[:]), func(key solana.PublicKey, data []byte) (interface{}, error) {
+				return p.DecodeAccount(key, data)
+			})
+			if err != nil {
+				return fmt.Errorf("unable to register account decoder: %w", err)
+			}
+		}
+	}
+	return nil
+}
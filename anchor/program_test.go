@@ -0,0 +1,254 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	bin "github.com/streamingfast/binary"
+	"github.com/streamingfast/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var counterProgramID = solana.MustPublicKeyFromBase58("CounterProgram11111111111111111111111111111")
+
+func newCounterProgram(t *testing.T) *Program {
+	idl, err := LoadIDLFile("testdata/counter.json")
+	require.NoError(t, err)
+
+	program, err := NewProgram(solana.PublicKey{}, idl)
+	require.NoError(t, err)
+	require.Equal(t, counterProgramID, program.ID)
+	return program
+}
+
+func mustHex(t *testing.T, in string) []byte {
+	out, err := hex.DecodeString(in)
+	require.NoError(t, err)
+	return out
+}
+
+func TestParseIDL_RoundTrip(t *testing.T) {
+	idl, err := LoadIDLFile("testdata/counter.json")
+	require.NoError(t, err)
+
+	assert.Equal(t, "counter", idl.Name)
+	assert.True(t, idl.Instructions[0].Accounts[2].IsGroup())
+	assert.Equal(t, "option<string>", idl.Instructions[0].Args[1].Type.String())
+	assert.Equal(t, "[u8; 4]", idl.Instructions[1].Args[2].Type.String())
+	assert.Len(t, idl.Types[0].Type.Variants[1].NamedFields, 1)
+	assert.Len(t, idl.Types[0].Type.Variants[2].TupleFields, 2)
+
+	errorCode, found := idl.LookupError(6000)
+	require.True(t, found)
+	assert.Equal(t, "Overflow", errorCode.Name)
+
+	content, err := json.Marshal(idl)
+	require.NoError(t, err)
+	reparsed, err := ParseIDL(content)
+	require.NoError(t, err)
+	assert.Equal(t, idl, reparsed)
+}
+
+func TestProgram_DecodeInstruction(t *testing.T) {
+	program := newCounterProgram(t)
+	counter := solana.MustPublicKeyFromBase58("2kPGkTUzGZDTwxamiC99vggZZ52Dj6TKLNTErXmbNVwt")
+	authority := solana.MustPublicKeyFromBase58("Gg1CWowuNc9ytKuX1p7hZ2mgBmWrjT9eNeXf8gvdq1Kd")
+	extra := solana.MustPublicKeyFromBase58("Gr5UanqwiKA54GGnw4b1bB5M8eatQzj6s6FQ9FeTze5C")
+
+	accounts := []*solana.AccountMeta{
+		{PublicKey: counter, IsWritable: true},
+		{PublicKey: authority, IsSigner: true},
+		{PublicKey: solana.MustPublicKeyFromBase58("11111111111111111111111111111111")},
+		{PublicKey: extra},
+	}
+
+	tests := []struct {
+		name              string
+		data              string
+		accounts          []*solana.AccountMeta
+		expectedName      string
+		expectedArgs      string
+		expectedAccounts  []string
+		expectedRemaining int
+		expectedErr       string
+	}{
+		{
+			name:              "initialize with nested accounts",
+			data:              "afaf6d1f0d989bed" + "2a00000000000000" + "01" + "05000000" + hex.EncodeToString([]byte("hello")),
+			accounts:          accounts,
+			expectedName:      "initialize",
+			expectedArgs:      `{"start":42,"label":"hello"}`,
+			expectedAccounts:  []string{"counter", "authority", "common.systemProgram"},
+			expectedRemaining: 1,
+		},
+		{
+			name:             "initialize without label",
+			data:             "afaf6d1f0d989bed" + "2a00000000000000" + "00",
+			accounts:         accounts[:3],
+			expectedName:     "initialize",
+			expectedArgs:     `{"start":42,"label":null}`,
+			expectedAccounts: []string{"counter", "authority", "common.systemProgram"},
+		},
+		{
+			name:             "enum, vec and array",
+			data:             "52ca0f62fe8d3119" + "02ff05" + "02000000" + "feff2c01" + "01020304",
+			accounts:         accounts[:1],
+			expectedName:     "setDataV2",
+			expectedArgs:     `{"mode":{"Range":[-1,5]},"values":[-2,300],"tag":[1,2,3,4]}`,
+			expectedAccounts: []string{"counter"},
+		},
+		{
+			name:        "unknown discriminator",
+			data:        "0000000000000000",
+			accounts:    accounts,
+			expectedErr: "unknown instruction discriminator 0000000000000000",
+		},
+		{
+			name:        "invalid enum variant",
+			data:        "52ca0f62fe8d3119" + "03",
+			accounts:    accounts,
+			expectedErr: `enum "Mode" has no variant 3`,
+		},
+		{
+			name:        "missing accounts",
+			data:        "afaf6d1f0d989bed" + "2a00000000000000" + "00",
+			accounts:    accounts[:2],
+			expectedErr: "insufficient account, initialize requires at-least 3 accounts not 2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decoded, err := program.DecodeInstruction(test.accounts, mustHex(t, test.data))
+			if test.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedName, decoded.Name)
+			assert.Equal(t, counterProgramID, decoded.ProgramID)
+
+			args, err := json.Marshal(decoded.Args)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.expectedArgs, string(args))
+
+			var names []string
+			for idx, account := range decoded.Accounts {
+				names = append(names, account.Name)
+				assert.Equal(t, test.accounts[idx].PublicKey, account.PublicKey)
+				assert.Equal(t, test.accounts[idx].IsSigner, account.IsSigner)
+				assert.Equal(t, test.accounts[idx].IsWritable, account.IsWritable)
+			}
+			assert.Equal(t, test.expectedAccounts, names)
+			assert.Len(t, decoded.RemainingAccounts, test.expectedRemaining)
+		})
+	}
+}
+
+func TestProgram_DecodeAccount(t *testing.T) {
+	program := newCounterProgram(t)
+	key := solana.MustPublicKeyFromBase58("2kPGkTUzGZDTwxamiC99vggZZ52Dj6TKLNTErXmbNVwt")
+	authority := solana.MustPublicKeyFromBase58("Gg1CWowuNc9ytKuX1p7hZ2mgBmWrjT9eNeXf8gvdq1Kd")
+
+	data := mustHex(t, "ffb004f5bcfd7c19"+
+		hex.EncodeToString(authority[:])+
+		"0700000000000000"+
+		"0000000000000000"+"0100000000000000"+
+		"0103000000"+
+		"00000000", // unused allocated space
+	)
+
+	decoded, err := program.DecodeAccount(key, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Counter", decoded.Name)
+	assert.Equal(t, key, decoded.Key)
+
+	counter := decoded.Data.(*Struct)
+	value, _ := counter.Get("authority")
+	assert.Equal(t, authority, value)
+	value, _ = counter.Get("count")
+	assert.Equal(t, uint64(7), value)
+	value, _ = counter.Get("total")
+	assert.Equal(t, bin.Uint128{Lo: 0, Hi: 1}, value)
+	value, _ = counter.Get("mode")
+	assert.Equal(t, &Enum{Variant: "Fixed", Value: &Struct{Fields: []*StructField{{Name: "step", Value: uint32(3)}}}}, value)
+
+	_, err = program.DecodeAccount(key, data[:20])
+	assert.Error(t, err)
+}
+
+func TestProgram_DecodeEvents(t *testing.T) {
+	program := newCounterProgram(t)
+	counter := solana.MustPublicKeyFromBase58("2kPGkTUzGZDTwxamiC99vggZZ52Dj6TKLNTErXmbNVwt")
+
+	eventData := base64.StdEncoding.EncodeToString(mustHex(t, "dbb5b7dc583a72c6"+hex.EncodeToString(counter[:])+"0800000000000000"+"feffffffffffffffffffffffffffffff"))
+	otherProgramData := base64.StdEncoding.EncodeToString([]byte("not an event of the counter program"))
+
+	logs := []string{
+		"Program CounterProgram11111111111111111111111111111 invoke [1]",
+		"Program log: Instruction: Increment",
+		"Program Gr5UanqwiKA54GGnw4b1bB5M8eatQzj6s6FQ9FeTze5C invoke [2]",
+		"Program data: " + otherProgramData,
+		"Program Gr5UanqwiKA54GGnw4b1bB5M8eatQzj6s6FQ9FeTze5C success",
+		"Program data: " + base64.StdEncoding.EncodeToString([]byte("raw sol_log_data")),
+		"Program data: " + eventData,
+		"Program data: " + base64.StdEncoding.EncodeToString(mustHex(t, "dbb5b7dc583a72c6"+"0102")),
+		"Program CounterProgram11111111111111111111111111111 consumed 1234 of 200000 compute units",
+		"Program CounterProgram11111111111111111111111111111 success",
+		"Program data: " + otherProgramData,
+	}
+
+	events := program.DecodeEvents(logs)
+	require.Len(t, events, 2)
+	assert.Equal(t, "CounterIncremented", events[0].Name)
+	require.NoError(t, events[0].Err)
+
+	assert.Equal(t, "CounterIncremented", events[1].Name)
+	assert.Nil(t, events[1].Data)
+	require.Error(t, events[1].Err)
+	assert.Contains(t, events[1].Err.Error(), "log 7: unable to decode event \"CounterIncremented\"")
+
+	content, err := json.Marshal(events[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"CounterIncremented","data":{"counter":"2kPGkTUzGZDTwxamiC99vggZZ52Dj6TKLNTErXmbNVwt","count":8,"delta":"0xfffffffffffffffffffffffffffffffe"}}`, string(content))
+
+	delta, _ := events[0].Data.Get("delta")
+	assert.Equal(t, "-2", delta.(bin.Int128).DecimalString())
+}
+
+func TestProgram_RegisterIn(t *testing.T) {
+	program := newCounterProgram(t)
+	instructions := solana.NewRegistry()
+	accounts := solana.NewAccountDecoderRegistry()
+
+	require.NoError(t, program.RegisterIn(instructions, accounts))
+	assert.Error(t, program.RegisterIn(instructions, nil))
+
+	decoded, err := instructions.DecodeInstruction(counterProgramID, []*solana.AccountMeta{{}}, mustHex(t, "52ca0f62fe8d3119"+"00"+"00000000"+"00000000"))
+	require.NoError(t, err)
+	assert.Equal(t, "setDataV2", decoded.(*Instruction).Name)
+
+	data := append(mustHex(t, "ffb004f5bcfd7c19"), make([]byte, 32+8+16+1)...)
+	account, err := accounts.DecodeAccount(solana.PublicKey{}, counterProgramID, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Counter", account.(*Account).Name)
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/streamingfast/solana-go/rpc"
+)
+
+// IDLAccountSeed is the seed Anchor derives the IDL account address with, from
+// the program's base address.
+const IDLAccountSeed = "anchor:idl"
+
+// IDL_ACCOUNT_HEADER_SIZE is the size of the IDL account content preceding the
+// compressed IDL: discriminator, authority and data length.
+const IDL_ACCOUNT_HEADER_SIZE = DISCRIMINATOR_SIZE + 32 + 4
+
+// MaxIDLSize is the maximum size of a decompressed IDL, larger ones are
+// rejected by IDLAccount.IDL to protect against compression bombs published
+// by unknown programs.
+const MaxIDLSize = 10 * 1024 * 1024
+
+var IDLAccountDiscriminator = AccountDiscriminator("IdlAccount")
+
+// IDLAccount is the account Anchor programs publish their IDL in, through
+// `anchor idl init`.
+type IDLAccount struct {
+	Authority solana.PublicKey
+	// Data is the zlib compressed JSON IDL.
+	Data []byte
+}
+
+// IDLAddress returns the address of the IDL account of `programID`, created
+// with seed `anchor:idl` from the program's base address, itself the program
+// address derived without seeds.
+func IDLAddress(programID solana.PublicKey) (solana.PublicKey, error) {
+	base, _, err := solana.FindProgramAddress(nil, programID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("unable to derive base address: %w", err)
+	}
+
+	address, err := solana.PublicKeyCreateWithSeed(base, IDLAccountSeed, programID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("unable to derive IDL address: %w", err)
+	}
+	return address, nil
+}
+
+func (a *IDLAccount) Decode(in []byte) error {
+	if len(in) < IDL_ACCOUNT_HEADER_SIZE {
+		return fmt.Errorf("unpack: expected at least %d bytes, got %d", IDL_ACCOUNT_HEADER_SIZE, len(in))
+	}
+	if !bytes.Equal(in[:DISCRIMINATOR_SIZE], IDLAccountDiscriminator[:]) {
+		return fmt.Errorf("unpack: not an IDL account")
+	}
+
+	copy(a.Authority[:], in[DISCRIMINATOR_SIZE:])
+
+	length := binary.LittleEndian.Uint32(in[DISCRIMINATOR_SIZE+32:])
+	if uint64(length) > uint64(len(in)-IDL_ACCOUNT_HEADER_SIZE) {
+		return fmt.Errorf("unpack: IDL data length %d exceeds the %d bytes available", length, len(in)-IDL_ACCOUNT_HEADER_SIZE)
+	}
+	a.Data = in[IDL_ACCOUNT_HEADER_SIZE : IDL_ACCOUNT_HEADER_SIZE+int(length)]
+	return nil
+}
+
+// IDL decompresses and parses the account's IDL, failing when it's larger than
+// MaxIDLSize once decompressed.
+func (a *IDLAccount) IDL() (*IDL, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(a.Data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress IDL: %w", err)
+	}
+	defer reader.Close()
+
+	content, err := ioutil.ReadAll(io.LimitReader(reader, MaxIDLSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress IDL: %w", err)
+	}
+	if len(content) > MaxIDLSize {
+		return nil, fmt.Errorf("unable to decompress IDL: larger than the maximum of %d bytes", MaxIDLSize)
+	}
+	return ParseIDL(content)
+}
+
+// FetchIDL fetches the IDL published on chain by the Anchor program
+// `programID`, rpc.ErrNotFound being returned when the program has none.
+func FetchIDL(ctx context.Context, rpcCli *rpc.Client, programID solana.PublicKey) (*IDL, error) {
+	address, err := IDLAddress(programID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := rpcCli.GetAccountInfo(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.Value.Owner.Equals(programID) {
+		return nil, fmt.Errorf("IDL account %q is not owned by program %q, owner is %q", address, programID, resp.Value.Owner)
+	}
+
+	account := &IDLAccount{}
+	if err := account.Decode(resp.Value.Data); err != nil {
+		return nil, fmt.Errorf("unable to decode IDL account %q: %w", address, err)
+	}
+
+	idl, err := account.IDL()
+	if err != nil {
+		return nil, fmt.Errorf("IDL account %q: %w", address, err)
+	}
+	return idl, nil
+}
+
+// FetchProgram fetches the IDL published on chain by the Anchor program
+// `programID` and prepares the dynamic decoding of its instructions, accounts
+// and events, see FetchIDL and NewProgram.
+func FetchProgram(ctx context.Context, rpcCli *rpc.Client, programID solana.PublicKey) (*Program, error) {
+	idl, err := FetchIDL(ctx, rpcCli, programID)
+	if err != nil {
+		return nil, err
+	}
+	return NewProgram(programID, idl)
+}
@@ -0,0 +1,147 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/streamingfast/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func idlAccountData(t *testing.T, authority solana.PublicKey, idl []byte) []byte {
+	compressed := &bytes.Buffer{}
+	writer := zlib.NewWriter(compressed)
+	_, err := writer.Write(idl)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	length := make([]byte, 4)
+	binary.LittleEndian.PutUint32(length, uint32(compressed.Len()))
+
+	data := append([]byte{}, IDLAccountDiscriminator[:]...)
+	data = append(data, authority[:]...)
+	data = append(data, length...)
+	data = append(data, compressed.Bytes()...)
+
+	// IDL accounts are allocated larger than their content
+	return append(data, make([]byte, 64)...)
+}
+
+func mockGetAccountInfo(t *testing.T, owner solana.PublicKey, data []byte) (server *httptest.Server, requested *string) {
+	requested = new(string)
+	server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		var request struct {
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal(body, &request))
+		require.NoError(t, json.Unmarshal(request.Params[0], requested))
+
+		value := map[string]interface{}{
+			"lamports":   1,
+			"data":       []string{base64.StdEncoding.EncodeToString(data), "base64"},
+			"owner":      owner.String(),
+			"executable": false,
+			"rentEpoch":  0,
+		}
+		if data == nil {
+			value = nil
+		}
+
+		response, err := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      0,
+			"result":  map[string]interface{}{"context": map[string]interface{}{"slot": 1}, "value": value},
+		})
+		require.NoError(t, err)
+		rw.Write(response)
+	}))
+	return server, requested
+}
+
+func TestIDLAccount_Decode(t *testing.T) {
+	idlContent, err := ioutil.ReadFile("testdata/counter.json")
+	require.NoError(t, err)
+
+	authority := solana.MustPublicKeyFromBase58("SysvarRent111111111111111111111111111111111")
+
+	account := &IDLAccount{}
+	require.NoError(t, account.Decode(idlAccountData(t, authority, idlContent)))
+	assert.Equal(t, authority, account.Authority)
+
+	idl, err := account.IDL()
+	require.NoError(t, err)
+	assert.Equal(t, "counter", idl.Name)
+
+	assert.EqualError(t, (&IDLAccount{}).Decode(make([]byte, 10)), "unpack: expected at least 44 bytes, got 10")
+	assert.EqualError(t, (&IDLAccount{}).Decode(make([]byte, 64)), "unpack: not an IDL account")
+
+	truncated := idlAccountData(t, authority, idlContent)[:IDL_ACCOUNT_HEADER_SIZE+2]
+	assert.Error(t, (&IDLAccount{}).Decode(truncated))
+
+	bomb := &IDLAccount{}
+	require.NoError(t, bomb.Decode(idlAccountData(t, authority, make([]byte, MaxIDLSize+1))))
+	_, err = bomb.IDL()
+	assert.EqualError(t, err, "unable to decompress IDL: larger than the maximum of 10485760 bytes")
+}
+
+func TestFetchProgram(t *testing.T) {
+	idlContent, err := ioutil.ReadFile("testdata/counter.json")
+	require.NoError(t, err)
+
+	server, requested := mockGetAccountInfo(t, counterProgramID, idlAccountData(t, solana.PublicKey{}, idlContent))
+	defer server.Close()
+
+	program, err := FetchProgram(context.Background(), rpc.NewClient(server.URL), counterProgramID)
+	require.NoError(t, err)
+	assert.Equal(t, counterProgramID, program.ID)
+	assert.Equal(t, "counter", program.IDL.Name)
+
+	expectedAddress, err := IDLAddress(counterProgramID)
+	require.NoError(t, err)
+	assert.Equal(t, expectedAddress.String(), *requested)
+}
+
+func TestFetchIDL_Errors(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		server, _ := mockGetAccountInfo(t, counterProgramID, nil)
+		defer server.Close()
+
+		_, err := FetchIDL(context.Background(), rpc.NewClient(server.URL), counterProgramID)
+		assert.Equal(t, rpc.ErrNotFound, err)
+	})
+
+	t.Run("wrong owner", func(t *testing.T) {
+		server, _ := mockGetAccountInfo(t, solana.PublicKey{}, make([]byte, 64))
+		defer server.Close()
+
+		_, err := FetchIDL(context.Background(), rpc.NewClient(server.URL), counterProgramID)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,310 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	bin "github.com/streamingfast/binary"
+	"github.com/streamingfast/solana-go"
+)
+
+// Values decoded from IDL types are represented as:
+//
+//   bool, u8..u64, i8..i64, f32, f64 -> bool, uint8..uint64, int8..int64, float32, float64
+//   u128, i128                        -> bin.Uint128, bin.Int128
+//   string, bytes, publicKey          -> string, []byte, solana.PublicKey
+//   vec, array                        -> []interface{}
+//   option, coption                   -> nil or the value
+//   defined struct                    -> *Struct
+//   defined enum                      -> *Enum
+
+// Struct is a decoded struct, fields being kept in their definition order.
+type Struct struct {
+	Fields []*StructField
+}
+
+type StructField struct {
+	Name  string
+	Value interface{}
+}
+
+// Get returns the value of the field `name`.
+func (s *Struct) Get(name string) (interface{}, bool) {
+	for _, field := range s.Fields {
+		if field.Name == name {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+// MarshalJSON renders the struct as an object, keeping fields order.
+func (s *Struct) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for idx, field := range s.Fields {
+		if idx > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(field.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", field.Name, err)
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Enum is a decoded enum, Value being nil for variants without fields, a
+// *Struct for variants with named fields and a []interface{} for tuple
+// variants.
+type Enum struct {
+	Variant string
+	Value   interface{}
+}
+
+// MarshalJSON renders the enum like serde does, the variant name for variants
+// without fields, `{"<variant>": <value>}` otherwise.
+func (e *Enum) MarshalJSON() ([]byte, error) {
+	if e.Value == nil {
+		return json.Marshal(e.Variant)
+	}
+	return json.Marshal(map[string]interface{}{e.Variant: e.Value})
+}
+
+// maxTypeDepth bounds the nesting of defined types, protecting against
+// self-referencing types in malformed IDLs.
+const maxTypeDepth = 64
+
+type valueDecoder struct {
+	idl     *IDL
+	data    []byte
+	decoder *bin.Decoder
+	depth   int
+}
+
+func newValueDecoder(idl *IDL, data []byte) *valueDecoder {
+	return &valueDecoder{
+		idl:     idl,
+		data:    data,
+		decoder: bin.NewDecoder(data),
+	}
+}
+
+func (d *valueDecoder) decodeFields(fields []IDLField) (*Struct, error) {
+	out := &Struct{Fields: make([]*StructField, len(fields))}
+	for idx, field := range fields {
+		value, err := d.decodeType(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", field.Name, err)
+		}
+		out.Fields[idx] = &StructField{Name: field.Name, Value: value}
+	}
+	return out, nil
+}
+
+func (d *valueDecoder) decodeTypeDef(def *IDLTypeDef) (interface{}, error) {
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > maxTypeDepth {
+		return nil, fmt.Errorf("type %q nested too deeply", def.Name)
+	}
+
+	switch def.Type.Kind {
+	case IDLTypeDefKindStruct:
+		return d.decodeFields(def.Type.Fields)
+	case IDLTypeDefKindEnum:
+		return d.decodeEnum(def)
+	}
+	return nil, fmt.Errorf("type %q has unsupported kind %q", def.Name, def.Type.Kind)
+}
+
+func (d *valueDecoder) decodeEnum(def *IDLTypeDef) (*Enum, error) {
+	index, err := d.decoder.ReadUint8()
+	if err != nil {
+		return nil, fmt.Errorf("enum %q variant: %w", def.Name, err)
+	}
+	if int(index) >= len(def.Type.Variants) {
+		return nil, fmt.Errorf("enum %q has no variant %d", def.Name, index)
+	}
+
+	variant := def.Type.Variants[index]
+	out := &Enum{Variant: variant.Name}
+	switch {
+	case variant.NamedFields != nil:
+		out.Value, err = d.decodeFields(variant.NamedFields)
+	case variant.TupleFields != nil:
+		out.Value, err = d.decodeList(variant.TupleFields)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("enum %q variant %q: %w", def.Name, variant.Name, err)
+	}
+	return out, nil
+}
+
+func (d *valueDecoder) decodeList(types []IDLType) ([]interface{}, error) {
+	out := make([]interface{}, len(types))
+	for idx, typ := range types {
+		value, err := d.decodeType(typ)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", idx, err)
+		}
+		out[idx] = value
+	}
+	return out, nil
+}
+
+func (d *valueDecoder) decodeType(t IDLType) (interface{}, error) {
+	switch {
+	case t.Primitive != "":
+		return d.decodePrimitive(t.Primitive)
+
+	case t.Vec != nil:
+		length, err := d.decoder.ReadUint32(binary.LittleEndian)
+		if err != nil {
+			return nil, fmt.Errorf("vec length: %w", err)
+		}
+		return d.decodeRepeated(*t.Vec, int(length))
+
+	case t.Array != nil:
+		return d.decodeRepeated(t.Array.Elem, t.Array.Len)
+
+	case t.Option != nil:
+		present, err := d.decoder.ReadUint8()
+		if err != nil {
+			return nil, fmt.Errorf("option: %w", err)
+		}
+		if present == 0 {
+			return nil, nil
+		}
+		return d.decodeType(*t.Option)
+
+	case t.COption != nil:
+		// COption, used by SPL programs, is tagged with a u32 and always holds
+		// the space of its value
+		present, err := d.decoder.ReadUint32(binary.LittleEndian)
+		if err != nil {
+			return nil, fmt.Errorf("coption: %w", err)
+		}
+		value, err := d.decodeType(*t.COption)
+		if err != nil || present == 0 {
+			return nil, err
+		}
+		return value, nil
+
+	case t.Defined != "":
+		def, found := d.idl.LookupType(t.Defined)
+		if !found {
+			return nil, fmt.Errorf("undefined type %q", t.Defined)
+		}
+		return d.decodeTypeDef(def)
+	}
+
+	return nil, fmt.Errorf("invalid empty type")
+}
+
+func (d *valueDecoder) decodeRepeated(elem IDLType, length int) ([]interface{}, error) {
+	// Elements take at least one byte for all but degenerated types, which
+	// avoids allocating for a corrupted length.
+	capacity := length
+	if remaining := d.decoder.Remaining(); capacity > remaining {
+		capacity = remaining
+	}
+
+	out := make([]interface{}, 0, capacity)
+	for i := 0; i < length; i++ {
+		value, err := d.decodeType(elem)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		out = append(out, value)
+	}
+	return out, nil
+}
+
+func (d *valueDecoder) readBytes(count int) ([]byte, error) {
+	if d.decoder.Remaining() < count {
+		return nil, fmt.Errorf("required [%d] bytes, remaining [%d]", count, d.decoder.Remaining())
+	}
+
+	start := int(d.decoder.Position())
+	out := make([]byte, count)
+	copy(out, d.data[start:start+count])
+	return out, d.decoder.SkipBytes(uint(count))
+}
+
+func (d *valueDecoder) decodePrimitive(name string) (interface{}, error) {
+	switch name {
+	case IDLTypeBool:
+		return d.decoder.ReadBool()
+	case IDLTypeU8:
+		return d.decoder.ReadUint8()
+	case IDLTypeI8:
+		return d.decoder.ReadInt8()
+	case IDLTypeU16:
+		return d.decoder.ReadUint16(binary.LittleEndian)
+	case IDLTypeI16:
+		return d.decoder.ReadInt16(binary.LittleEndian)
+	case IDLTypeU32:
+		return d.decoder.ReadUint32(binary.LittleEndian)
+	case IDLTypeI32:
+		return d.decoder.ReadInt32(binary.LittleEndian)
+	case IDLTypeF32:
+		return d.decoder.ReadFloat32(binary.LittleEndian)
+	case IDLTypeU64:
+		return d.decoder.ReadUint64(binary.LittleEndian)
+	case IDLTypeI64:
+		return d.decoder.ReadInt64(binary.LittleEndian)
+	case IDLTypeF64:
+		return d.decoder.ReadFloat64(binary.LittleEndian)
+	case IDLTypeU128:
+		return d.decoder.ReadUint128(binary.LittleEndian)
+	case IDLTypeI128:
+		return d.decoder.ReadInt128(binary.LittleEndian)
+	case IDLTypeBytes, IDLTypeString:
+		length, err := d.decoder.ReadUint32(binary.LittleEndian)
+		if err != nil {
+			return nil, fmt.Errorf("%s length: %w", name, err)
+		}
+		data, err := d.readBytes(int(length))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if name == IDLTypeString {
+			return string(data), nil
+		}
+		return data, nil
+	case IDLTypePublicKey:
+		data, err := d.readBytes(32)
+		if err != nil {
+			return nil, fmt.Errorf("public key: %w", err)
+		}
+		return solana.PublicKeyFromBytes(data), nil
+	}
+	return nil, fmt.Errorf("unsupported type %q", name)
+}
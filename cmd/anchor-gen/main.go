@@ -0,0 +1,94 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command anchor-gen generates a Go package binding an Anchor program from its
+// IDL:
+//
+//	anchor-gen -idl target/idl/my_program.json -package myprogram -output programs/myprogram
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/streamingfast/solana-go/anchor"
+	"github.com/streamingfast/solana-go/anchor/gen"
+)
+
+func main() {
+	idlFile := flag.String("idl", "", "Path to the Anchor IDL JSON file (required)")
+	packageName := flag.String("package", "", "Name of the generated package, defaults to the output directory name")
+	programID := flag.String("program-id", "", "Program address, defaults to the address found in the IDL metadata")
+	output := flag.String("output", ".", "Directory the generated files are written to")
+	flag.Parse()
+
+	if err := run(*idlFile, *packageName, *programID, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "anchor-gen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(idlFile, packageName, programID, output string) error {
+	if idlFile == "" {
+		return fmt.Errorf("the -idl flag is required")
+	}
+
+	idl, err := anchor.LoadIDLFile(idlFile)
+	if err != nil {
+		return err
+	}
+
+	opts := gen.Options{Package: packageName}
+	if opts.Package == "" {
+		absOutput, err := filepath.Abs(output)
+		if err != nil {
+			return fmt.Errorf("invalid output directory: %w", err)
+		}
+		opts.Package = filepath.Base(absOutput)
+	}
+	if programID != "" {
+		if opts.ProgramID, err = solana.PublicKeyFromBase58(programID); err != nil {
+			return fmt.Errorf("invalid program ID %q: %w", programID, err)
+		}
+	}
+
+	files, err := gen.Generate(idl, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return fmt.Errorf("unable to create output directory: %w", err)
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(output, name)
+		if err := ioutil.WriteFile(path, files[name], 0644); err != nil {
+			return fmt.Errorf("unable to write %s: %w", path, err)
+		}
+		fmt.Println("Wrote", path)
+	}
+	return nil
+}
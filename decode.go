@@ -0,0 +1,111 @@
+package solana
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DecodedTransaction is a transaction with each of its instructions resolved
+// against the instruction decoder registry.
+type DecodedTransaction struct {
+	Signatures   []Signature           `json:"signatures"`
+	Version      MessageVersion        `json:"version"`
+	Instructions []*DecodedInstruction `json:"instructions"`
+}
+
+// DecodedInstruction holds a compiled instruction with its program and
+// accounts resolved. When the program is unknown or its data can't be
+// decoded, `Instruction` is nil and `Err` tells why, the raw data being
+// always available.
+type DecodedInstruction struct {
+	ProgramID   PublicKey
+	Accounts    []*AccountMeta
+	Data        Base58
+	Instruction interface{}
+	Err         error
+}
+
+type decodedInstructionAccountJSON struct {
+	PublicKey  PublicKey `json:"pubkey"`
+	IsSigner   bool      `json:"isSigner"`
+	IsWritable bool      `json:"isWritable"`
+}
+
+type decodedInstructionJSON struct {
+	ProgramID   PublicKey                       `json:"programId"`
+	Accounts    []decodedInstructionAccountJSON `json:"accounts"`
+	Data        Base58                          `json:"data"`
+	Instruction interface{}                     `json:"instruction,omitempty"`
+	Error       string                          `json:"error,omitempty"`
+}
+
+func (i *DecodedInstruction) MarshalJSON() ([]byte, error) {
+	out := decodedInstructionJSON{
+		ProgramID:   i.ProgramID,
+		Accounts:    make([]decodedInstructionAccountJSON, len(i.Accounts)),
+		Data:        i.Data,
+		Instruction: i.Instruction,
+	}
+	for idx, account := range i.Accounts {
+		out.Accounts[idx] = decodedInstructionAccountJSON{
+			PublicKey:  account.PublicKey,
+			IsSigner:   account.IsSigner,
+			IsWritable: account.IsWritable,
+		}
+	}
+	if i.Err != nil {
+		out.Error = i.Err.Error()
+	}
+	return json.Marshal(out)
+}
+
+// DecodeTransaction resolves the program and accounts of every instruction of
+// the transaction and decodes them with the registered instruction decoders.
+// Failing to decode an instruction does not fail the whole transaction, the
+// error is kept on the instruction instead. Versioned messages using address
+// table lookups must have their loaded addresses set beforehand, see
+// `Message.SetAddressTables` and `Message.SetLoadedAddresses`.
+func DecodeTransaction(tx *Transaction) (*DecodedTransaction, error) {
+	return decodeTransaction(tx, DecodeInstruction)
+}
+
+// DecodeTransaction is like the package level DecodeTransaction but decodes
+// instructions with the decoders of this registry.
+func (r *Registry) DecodeTransaction(tx *Transaction) (*DecodedTransaction, error) {
+	return decodeTransaction(tx, r.DecodeInstruction)
+}
+
+func decodeTransaction(tx *Transaction, decodeInstruction func(PublicKey, []*AccountMeta, []byte) (interface{}, error)) (*DecodedTransaction, error) {
+	message := &tx.Message
+	if len(message.AddressTableLookups) > 0 && message.loadedAddresses == nil {
+		return nil, fmt.Errorf("message uses address table lookups, loaded addresses must be set before decoding")
+	}
+
+	out := &DecodedTransaction{
+		Signatures:   tx.Signatures,
+		Version:      message.Version,
+		Instructions: make([]*DecodedInstruction, len(message.Instructions)),
+	}
+
+	for idx, compiled := range message.Instructions {
+		decoded := &DecodedInstruction{Data: compiled.Data}
+		out.Instructions[idx] = decoded
+
+		programID, err := message.ResolveProgramIDIndex(compiled.ProgramIDIndex)
+		if err != nil {
+			decoded.Err = err
+			continue
+		}
+		decoded.ProgramID = programID
+
+		decoded.Accounts, err = compiled.ResolveInstructionAccounts(message)
+		if err != nil {
+			decoded.Err = err
+			continue
+		}
+
+		decoded.Instruction, decoded.Err = decodeInstruction(programID, decoded.Accounts, compiled.Data)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,139 @@
+package solana
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var decodeTestProgramID = MustPublicKeyFromBase58("DecodeTest111111111111111111111111111111111")
+
+type decodeTestInstruction struct {
+	Value    byte           `json:"value"`
+	Accounts []*AccountMeta `json:"-"`
+}
+
+func init() {
+	RegisterInstructionDecoder(decodeTestProgramID, func(accounts []*AccountMeta, data []byte) (interface{}, error) {
+		if len(data) != 1 {
+			return nil, fmt.Errorf("expected 1 byte, got %d", len(data))
+		}
+		return &decodeTestInstruction{Value: data[0], Accounts: accounts}, nil
+	})
+}
+
+func TestDecodeTransaction(t *testing.T) {
+	payer := MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn")
+	other := MustPublicKeyFromBase58("9hFtYBYmBJCVguRYs9pBTWKYAFoKfjYR7zBPpEkVsmD")
+	unknownProgram := MustPublicKeyFromBase58("Vote111111111111111111111111111111111111111")
+
+	trx, err := NewTransaction([]Instruction{
+		&testTransactionInstructions{
+			accounts: []*AccountMeta{
+				{PublicKey: payer, IsSigner: true, IsWritable: true},
+				{PublicKey: other},
+			},
+			data:      []byte{0x2a},
+			programID: decodeTestProgramID,
+		},
+		&testTransactionInstructions{
+			accounts:  []*AccountMeta{{PublicKey: other}},
+			data:      []byte{0x01, 0x02},
+			programID: unknownProgram,
+		},
+		&testTransactionInstructions{
+			data:      []byte{0x01, 0x02},
+			programID: decodeTestProgramID,
+		},
+	}, payer)
+	require.NoError(t, err)
+
+	decoded, err := DecodeTransaction(trx)
+	require.NoError(t, err)
+	require.Len(t, decoded.Instructions, 3)
+
+	first := decoded.Instructions[0]
+	assert.Equal(t, decodeTestProgramID, first.ProgramID)
+	assert.NoError(t, first.Err)
+	assert.Equal(t, []*AccountMeta{
+		{PublicKey: payer, IsSigner: true, IsWritable: true},
+		{PublicKey: other},
+	}, first.Accounts)
+	assert.Equal(t, &decodeTestInstruction{Value: 0x2a, Accounts: first.Accounts}, first.Instruction)
+
+	second := decoded.Instructions[1]
+	assert.Equal(t, unknownProgram, second.ProgramID)
+	assert.Error(t, second.Err)
+	assert.Nil(t, second.Instruction)
+	assert.Equal(t, Base58{0x01, 0x02}, second.Data)
+
+	third := decoded.Instructions[2]
+	assert.Error(t, third.Err)
+	assert.Nil(t, third.Instruction)
+
+	cnt, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"signatures": null,
+		"version": "legacy",
+		"instructions": [
+			{
+				"programId": "DecodeTest111111111111111111111111111111111",
+				"accounts": [
+					{"pubkey": "A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn", "isSigner": true, "isWritable": true},
+					{"pubkey": "9hFtYBYmBJCVguRYs9pBTWKYAFoKfjYR7zBPpEkVsmD", "isSigner": false, "isWritable": false}
+				],
+				"data": "j",
+				"instruction": {"value": 42}
+			},
+			{
+				"programId": "Vote111111111111111111111111111111111111111",
+				"accounts": [
+					{"pubkey": "9hFtYBYmBJCVguRYs9pBTWKYAFoKfjYR7zBPpEkVsmD", "isSigner": false, "isWritable": false}
+				],
+				"data": "5T",
+				"error": "unknown programID, cannot find any instruction decoder \"Vote111111111111111111111111111111111111111\""
+			},
+			{
+				"programId": "DecodeTest111111111111111111111111111111111",
+				"accounts": [],
+				"data": "5T",
+				"error": "expected 1 byte, got 2"
+			}
+		]
+	}`, string(cnt))
+}
+
+func TestDecodeTransaction_InvalidIndexes(t *testing.T) {
+	trx := &Transaction{
+		Message: Message{
+			Header:      MessageHeader{NumRequiredSignatures: 1},
+			AccountKeys: []PublicKey{decodeTestProgramID},
+			Instructions: []CompiledInstruction{
+				{ProgramIDIndex: 5},
+				{ProgramIDIndex: 0, Accounts: []uint8{3}, Data: Base58{0x01}},
+			},
+		},
+	}
+
+	decoded, err := DecodeTransaction(trx)
+	require.NoError(t, err)
+	assert.Error(t, decoded.Instructions[0].Err)
+	assert.Error(t, decoded.Instructions[1].Err)
+	assert.Equal(t, decodeTestProgramID, decoded.Instructions[1].ProgramID)
+}
+
+func TestDecodeTransaction_UnresolvedLookups(t *testing.T) {
+	trx := &Transaction{
+		Message: Message{
+			Version:             MessageVersionV0,
+			AddressTableLookups: []MessageAddressTableLookup{{AccountKey: decodeTestProgramID, ReadonlyIndexes: []uint8{0}}},
+		},
+	}
+
+	_, err := DecodeTransaction(trx)
+	assert.Error(t, err)
+}
@@ -1,11 +1,13 @@
 package solana
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/ed25519"
 	crypto_rand "crypto/rand"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 
@@ -15,6 +17,12 @@ import (
 
 const MAX_SEED_LENGTH = 32
 
+// MAX_SEEDS is the maximum number of seeds, bump seed included, of a program
+// derived address
+const MAX_SEEDS = 16
+
+const pdaMarker = "ProgramDerivedAddress"
+
 type PrivateKey []byte
 
 func MustPrivateKeyFromBase58(in string) PrivateKey {
@@ -127,23 +135,52 @@ func PublicKeyFromBase58(in string) (out PublicKey, err error) {
 	return
 }
 
+// PublicKeyFindProgramAddress finds a valid program derived address for the
+// seeds, alongside its bump seed.
+//
+// Deprecated: Use FindProgramAddress instead
 func PublicKeyFindProgramAddress(path [][]byte, programId PublicKey) (PublicKey, byte, error) {
-	nonce := byte(255)
-	for {
-		seedsWithNonce := append(path, []byte{nonce})
-		key, err := createProgramAddress(seedsWithNonce, programId)
+	return FindProgramAddress(path, programId)
+}
+
+// FindProgramAddress searches, from 255 down to 0, the first bump seed that
+// makes `seeds` plus the bump a valid program derived address, which is
+// returned alongside the bump. The caller's seeds are never modified.
+func FindProgramAddress(seeds [][]byte, programID PublicKey) (PublicKey, uint8, error) {
+	// The bump is an extra seed
+	if len(seeds) >= MAX_SEEDS {
+		return PublicKey{}, 0, fmt.Errorf("too many seeds, got %d, at most %d allowed to leave room for the bump seed", len(seeds), MAX_SEEDS-1)
+	}
+
+	seedsWithBump := make([][]byte, len(seeds)+1)
+	copy(seedsWithBump, seeds)
+
+	bump := []byte{0}
+	for nonce := 255; nonce >= 0; nonce-- {
+		bump[0] = uint8(nonce)
+		seedsWithBump[len(seeds)] = bump
+
+		key, err := CreateProgramAddress(seedsWithBump, programID)
 		if err == nil {
-			return key, nonce, nil
+			return key, uint8(nonce), nil
 		}
-
-		nonce -= 1
-		if nonce == 0 {
-			return PublicKey{}, 0x00, fmt.Errorf("unable to find a viable program address nonce")
+		if err != ErrInvalidSeeds {
+			return PublicKey{}, 0, err
 		}
 	}
+	return PublicKey{}, 0, fmt.Errorf("unable to find a viable program address nonce")
 }
 
-func createProgramAddress(seeds [][]byte, programId PublicKey) (PublicKey, error) {
+var ErrInvalidSeeds = errors.New("invalid seeds, address must fall off the curve")
+
+// CreateProgramAddress returns the program derived address for the given seeds,
+// failing with ErrInvalidSeeds when the resulting address is on the ed25519
+// curve.
+func CreateProgramAddress(seeds [][]byte, programID PublicKey) (PublicKey, error) {
+	if len(seeds) > MAX_SEEDS {
+		return PublicKey{}, fmt.Errorf("too many seeds, got %d, at most %d allowed", len(seeds), MAX_SEEDS)
+	}
+
 	buf := []byte{}
 	for _, seed := range seeds {
 		if len(seed) > MAX_SEED_LENGTH {
@@ -151,14 +188,39 @@ func createProgramAddress(seeds [][]byte, programId PublicKey) (PublicKey, error
 		}
 		buf = append(buf, seed...)
 	}
-	buf = append(buf, programId[:]...)
-	buf = append(buf, []byte("ProgramDerivedAddress")...)
-	pkey := sha256.Sum256(buf)
-	if _, err := new(edwards25519.Point).SetBytes(pkey[:]); err == nil {
-		return PublicKey{}, fmt.Errorf("invalid seeds, address must fall off the curve")
+	buf = append(buf, programID[:]...)
+	buf = append(buf, []byte(pdaMarker)...)
+	pkey := PublicKey(sha256.Sum256(buf))
+	if pkey.IsOnCurve() {
+		return PublicKey{}, ErrInvalidSeeds
 	}
 	return pkey, nil
 }
+
+// PublicKeyCreateWithSeed derives an address from a base key, a seed and the
+// owner program, as done by the system program `*WithSeed` instructions.
+func PublicKeyCreateWithSeed(base PublicKey, seed string, owner PublicKey) (PublicKey, error) {
+	if len(seed) > MAX_SEED_LENGTH {
+		return PublicKey{}, fmt.Errorf("max seed length exceeded")
+	}
+	if bytes.HasSuffix(owner[:], []byte(pdaMarker)) {
+		return PublicKey{}, fmt.Errorf("illegal owner %q, it can't be a program derived address", owner)
+	}
+
+	buf := make([]byte, 0, 64+len(seed))
+	buf = append(buf, base[:]...)
+	buf = append(buf, seed...)
+	buf = append(buf, owner[:]...)
+	return PublicKey(sha256.Sum256(buf)), nil
+}
+
+// IsOnCurve returns whether the key is a valid ed25519 point, program derived
+// addresses never are which guarantees no private key exists for them.
+func (p PublicKey) IsOnCurve() bool {
+	_, err := new(edwards25519.Point).SetBytes(p[:])
+	return err == nil
+}
+
 func (p PublicKey) MarshalJSON() ([]byte, error) {
 	return json.Marshal(base58.Encode(p[:]))
 }
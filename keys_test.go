@@ -123,3 +123,76 @@ func TestPrivateKeyFromSolanaKeygenFile(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateProgramAddress(t *testing.T) {
+	// Vectors from the Solana SDK `test_create_program_address`
+	programID := MustPublicKeyFromBase58("BPFLoaderUpgradeab1e11111111111111111111111")
+	publicKey := MustPublicKeyFromBase58("SeedPubey1111111111111111111111111111111111")
+
+	tests := []struct {
+		seeds    [][]byte
+		expected string
+	}{
+		{[][]byte{{}, {1}}, "BwqrghZA2htAcqq8dzP1WDAhTXYTYWj7CHxF5j7TDBAe"},
+		{[][]byte{[]byte("☉"), {0}}, "13yWmRpaTR4r5nAktwLqMpRNr28tnVUZw26rTvPSSB19"},
+		{[][]byte{[]byte("Talking"), []byte("Squirrels")}, "2fnQrngrQT4SeLcdToJAD96phoEjNL2man2kfRLCASVk"},
+		{[][]byte{publicKey[:], {1}}, "976ymqVnfE32QFe6NfGDctSvVa36LWnvYxhU6G2232YL"},
+	}
+
+	for _, test := range tests {
+		address, err := CreateProgramAddress(test.seeds, programID)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, address.String())
+		assert.False(t, address.IsOnCurve())
+	}
+
+	_, err := CreateProgramAddress([][]byte{make([]byte, MAX_SEED_LENGTH+1)}, programID)
+	assert.Error(t, err)
+
+	_, err = CreateProgramAddress(make([][]byte, MAX_SEEDS+1), programID)
+	assert.Error(t, err)
+}
+
+func TestFindProgramAddress(t *testing.T) {
+	programID := MustPublicKeyFromBase58("MUG944SDz65o8te2Nz2tqv2e3KuN3QR1ZhyphufAybm")
+
+	// Extra capacity would let a naive append write the bump into the caller's backing array
+	backing := make([][]byte, 2, 3)
+	backing[0] = []byte("globalstate")
+	backing[1] = programID[:]
+	sentinel := []byte("untouched")
+	backing = append(backing, sentinel)[:2]
+
+	address, bump, err := FindProgramAddress(backing, programID)
+	require.NoError(t, err)
+	assert.Equal(t, MustPublicKeyFromBase58("FNPA5NeQ2M491CAFgSKZ8H21zYuVNzpGqvmu3RUEyYkE"), address)
+	assert.Equal(t, uint8(0xff), bump)
+	assert.Equal(t, []byte("untouched"), backing[:3][2])
+
+	expected, err := CreateProgramAddress([][]byte{[]byte("globalstate"), programID[:], {bump}}, programID)
+	require.NoError(t, err)
+	assert.Equal(t, expected, address)
+
+	_, _, err = FindProgramAddress(make([][]byte, MAX_SEEDS), programID)
+	assert.Error(t, err)
+}
+
+func TestPublicKeyCreateWithSeed(t *testing.T) {
+	address, err := PublicKeyCreateWithSeed(PublicKey{}, "limber chicken: 4/45", PublicKey{})
+	require.NoError(t, err)
+	assert.Equal(t, MustPublicKeyFromBase58("9h1HyLCW5dZnBVap8C5egQ9Z6pHyjsh5MNy83iPqqRuq"), address)
+
+	_, err = PublicKeyCreateWithSeed(PublicKey{}, string(make([]byte, MAX_SEED_LENGTH+1)), PublicKey{})
+	assert.Error(t, err)
+
+	var pdaOwner PublicKey
+	copy(pdaOwner[32-len(pdaMarker):], pdaMarker)
+	_, err = PublicKeyCreateWithSeed(PublicKey{}, "seed", pdaOwner)
+	assert.Error(t, err)
+}
+
+func TestPublicKey_IsOnCurve(t *testing.T) {
+	_, privateKey, err := NewRandomPrivateKey()
+	require.NoError(t, err)
+	assert.True(t, privateKey.PublicKey().IsOnCurve())
+}
@@ -0,0 +1,215 @@
+package solana
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	crypto_rand "crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NewMnemonic generates a random BIP39 English mnemonic, `bitSize` being the
+// entropy size which must be a multiple of 32 between 128 (12 words) and 256
+// (24 words).
+func NewMnemonic(bitSize int) (string, error) {
+	if bitSize < 128 || bitSize > 256 || bitSize%32 != 0 {
+		return "", fmt.Errorf("invalid entropy bit size %d, must be a multiple of 32 between 128 and 256", bitSize)
+	}
+
+	entropy := make([]byte, bitSize/8)
+	if _, err := crypto_rand.Read(entropy); err != nil {
+		return "", fmt.Errorf("unable to generate entropy: %w", err)
+	}
+	return MnemonicFromEntropy(entropy)
+}
+
+// MnemonicFromEntropy encodes the given entropy as a BIP39 English mnemonic.
+func MnemonicFromEntropy(entropy []byte) (string, error) {
+	bitSize := len(entropy) * 8
+	if bitSize < 128 || bitSize > 256 || bitSize%32 != 0 {
+		return "", fmt.Errorf("invalid entropy length %d bytes, must be a multiple of 4 between 16 and 32", len(entropy))
+	}
+
+	checksum := sha256.Sum256(entropy)
+	data := append(append([]byte{}, entropy...), checksum[0])
+
+	// Each word encodes 11 bits of the entropy followed by its checksum bits
+	wordCount := (bitSize + bitSize/32) / 11
+	words := make([]string, wordCount)
+	for i := range words {
+		words[i] = bip39EnglishWordList[readBits(data, i*11, 11)]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// ValidateMnemonic checks that the mnemonic is made of a valid number of known
+// BIP39 English words and that its checksum matches.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := mnemonicToEntropy(mnemonic)
+	return err
+}
+
+func mnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, fmt.Errorf("invalid mnemonic word count %d, expected 12, 15, 18, 21 or 24", len(words))
+	}
+
+	totalBits := len(words) * 11
+	checksumBits := totalBits / 33
+	entropyBits := totalBits - checksumBits
+
+	data := make([]byte, (totalBits+7)/8)
+	for i, word := range words {
+		index, found := bip39WordIndex[word]
+		if !found {
+			return nil, fmt.Errorf("invalid mnemonic word %q at position %d", word, i+1)
+		}
+		writeBits(data, i*11, 11, index)
+	}
+
+	entropy := data[:entropyBits/8]
+	checksum := sha256.Sum256(entropy)
+	if readBits(data, entropyBits, checksumBits) != readBits(checksum[:], 0, checksumBits) {
+		return nil, fmt.Errorf("invalid mnemonic checksum")
+	}
+	return entropy, nil
+}
+
+// MnemonicToSeed validates the mnemonic and derives the 64 bytes BIP39 seed
+// from it and the optional passphrase.
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+
+	password := []byte(strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " "))
+	salt := []byte("mnemonic" + norm.NFKD.String(passphrase))
+	return pbkdf2.Key(password, salt, 2048, 64, sha512.New), nil
+}
+
+// PrivateKeyFromMnemonic returns the key `solana-keygen` produces for a
+// mnemonic when no derivation path is given: the first 32 bytes of the BIP39
+// seed are used as the ed25519 seed.
+func PrivateKeyFromMnemonic(mnemonic, passphrase string) (PrivateKey, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return PrivateKey(ed25519.NewKeyFromSeed(seed[:ed25519.SeedSize])), nil
+}
+
+// PrivateKeyFromMnemonicWithPath derives the key at the given SLIP-0010 path,
+// Phantom and `solana-keygen` (with `?key=<account>/0`) use
+// `DerivationPath(account)`.
+func PrivateKeyFromMnemonicWithPath(mnemonic, passphrase, path string) (PrivateKey, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return PrivateKeyFromSeedWithPath(seed, path)
+}
+
+// DerivationPath returns the standard Solana BIP44 path for an account index,
+// `m/44'/501'/<account>'/0'`.
+func DerivationPath(account uint32) string {
+	return fmt.Sprintf("m/44'/501'/%d'/0'", account)
+}
+
+// PrivateKeyFromSeedWithPath derives the ed25519 key at `path` from a BIP39
+// seed following SLIP-0010. Only hardened indexes are supported by ed25519,
+// they can be written with a `'`, `h` or `H` suffix.
+func PrivateKeyFromSeedWithPath(seed []byte, path string) (PrivateKey, error) {
+	indexes, err := parseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, chainCode := slip10MasterKey(seed)
+	for _, index := range indexes {
+		key, chainCode = slip10ChildKey(key, chainCode, index)
+	}
+	return PrivateKey(ed25519.NewKeyFromSeed(key)), nil
+}
+
+const hardenedKeyOffset = 0x80000000
+
+func parseDerivationPath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if len(segments) == 0 || segments[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q, must start with \"m\"", path)
+	}
+
+	indexes := make([]uint32, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		hardened := strings.HasSuffix(segment, "'") || strings.HasSuffix(segment, "h") || strings.HasSuffix(segment, "H")
+		if !hardened {
+			return nil, fmt.Errorf("invalid derivation path %q, segment %q is not hardened which ed25519 does not support", path, segment)
+		}
+
+		index, err := strconv.ParseUint(segment[:len(segment)-1], 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path %q, segment %q: %w", path, segment, err)
+		}
+		indexes = append(indexes, uint32(index)+hardenedKeyOffset)
+	}
+	return indexes, nil
+}
+
+func slip10MasterKey(seed []byte) (key, chainCode []byte) {
+	mac := hmac.New(sha512.New, []byte("ed25519 seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
+
+func slip10ChildKey(key, chainCode []byte, index uint32) ([]byte, []byte) {
+	data := make([]byte, 1+32+4)
+	copy(data[1:], key)
+	binary.BigEndian.PutUint32(data[33:], index)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
+
+var bip39WordIndex = func() map[string]int {
+	index := make(map[string]int, len(bip39EnglishWordList))
+	for i, word := range bip39EnglishWordList {
+		index[word] = i
+	}
+	return index
+}()
+
+// readBits returns `count` bits (at most 32) of `data` starting at bit `offset`,
+// most significant bit first.
+func readBits(data []byte, offset, count int) int {
+	value := 0
+	for i := 0; i < count; i++ {
+		bit := offset + i
+		value <<= 1
+		if data[bit/8]&(0x80>>uint(bit%8)) != 0 {
+			value |= 1
+		}
+	}
+	return value
+}
+
+func writeBits(data []byte, offset, count, value int) {
+	for i := 0; i < count; i++ {
+		bit := offset + i
+		if value&(1<<uint(count-1-i)) != 0 {
+			data[bit/8] |= 0x80 >> uint(bit%8)
+		}
+	}
+}
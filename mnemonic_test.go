@@ -0,0 +1,125 @@
+package solana
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMnemonicFromEntropy(t *testing.T) {
+	// Test vectors from https://github.com/trezor/python-mnemonic/blob/master/vectors.json
+	tests := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{
+			"00000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+			"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+			"dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.entropy, func(t *testing.T) {
+			entropy, err := hex.DecodeString(test.entropy)
+			require.NoError(t, err)
+
+			mnemonic, err := MnemonicFromEntropy(entropy)
+			require.NoError(t, err)
+			assert.Equal(t, test.mnemonic, mnemonic)
+
+			decoded, err := mnemonicToEntropy(mnemonic)
+			require.NoError(t, err)
+			assert.Equal(t, entropy, decoded)
+
+			seed, err := MnemonicToSeed(mnemonic, "TREZOR")
+			require.NoError(t, err)
+			assert.Equal(t, test.seed, hex.EncodeToString(seed))
+		})
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	for _, bitSize := range []int{128, 160, 192, 224, 256} {
+		mnemonic, err := NewMnemonic(bitSize)
+		require.NoError(t, err)
+		assert.NoError(t, ValidateMnemonic(mnemonic))
+	}
+
+	_, err := NewMnemonic(100)
+	assert.Error(t, err)
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	assert.NoError(t, ValidateMnemonic("  abandon abandon abandon abandon abandon abandon\tabandon abandon abandon abandon abandon about "))
+	assert.Error(t, ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon"))
+	assert.Error(t, ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"))
+	assert.Error(t, ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon solana"))
+}
+
+func TestPrivateKeyFromSeedWithPath_SLIP10(t *testing.T) {
+	// Test vector 1 for ed25519 from https://github.com/satoshilabs/slips/blob/master/slip-0010.md
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+
+	tests := []struct {
+		path       string
+		privateKey string
+	}{
+		{"m", "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7"},
+		{"m/0'", "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3"},
+		{"m/0H/1H", "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			key, err := PrivateKeyFromSeedWithPath(seed, test.path)
+			require.NoError(t, err)
+			assert.Equal(t, test.privateKey, hex.EncodeToString(key[:32]))
+		})
+	}
+}
+
+func TestPrivateKeyFromMnemonic_Solana(t *testing.T) {
+	mnemonic := "neither lonely flavor argue grass remind eye tag avocado spot unusual intact"
+
+	expected := []string{
+		"5vftMkHL72JaJG6ExQfGAsT2uGVHpRR7oTNUPMs68Y2N",
+		"GcXbfQ5yY3uxCyBNDPBbR5FjumHf89E7YHXuULfGDBBv",
+		"7QPgyQwNLqnoSwHEuK8wKy2Y3Ani6EHoZRihTuWkwxbc",
+		"5aE8UprEEWtpVskhxo3f8ETco2kVKiZT9SS3D5Lcg8s2",
+	}
+	for account, address := range expected {
+		key, err := PrivateKeyFromMnemonicWithPath(mnemonic, "", DerivationPath(uint32(account)))
+		require.NoError(t, err)
+		assert.Equal(t, address, key.PublicKey().String())
+	}
+
+	key, err := PrivateKeyFromMnemonic("pill tomorrow foster begin walnut borrow virtual kick shift mutual shoe scatter", "")
+	require.NoError(t, err)
+	assert.Equal(t, "5ZWj7a1f8tWkjBESHKgrLmXshuXxqeY9SYcfbshpAqPG", key.PublicKey().String())
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	indexes, err := parseDerivationPath("m/44'/501'/0'/0'")
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{0x8000002c, 0x800001f5, 0x80000000, 0x80000000}, indexes)
+
+	for _, path := range []string{"", "44'/501'", "m/44'/501", "m/abc'", "m/2147483648'"} {
+		_, err := parseDerivationPath(path)
+		assert.Error(t, err, path)
+	}
+}
@@ -1,6 +1,7 @@
 package solana
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -60,3 +61,47 @@ func TestSignature_Verify(t *testing.T) {
 		})
 	}
 }
+
+func TestSignature_JSON(t *testing.T) {
+	signature, err := NewSignatureFromBase58("5yUSwqQqeZLEEYKxnG4JC4XhaaBpV3RS4nQbK8bQTyyLX4ij7a7f2AfNxvJ4DhR6EiHbyQc9hjqo5JB4fSwvUhYE")
+	require.NoError(t, err)
+
+	cnt, err := json.Marshal(signature)
+	require.NoError(t, err)
+
+	var decoded Signature
+	require.NoError(t, json.Unmarshal(cnt, &decoded))
+	require.Equal(t, signature, decoded)
+	require.False(t, decoded.IsZero())
+	require.True(t, Signature{}.IsZero())
+}
+
+func TestData_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          string
+		expected    Data
+		expectedErr string
+	}{
+		{"base64", `["AQID","base64"]`, Data{1, 2, 3}, ""},
+		{"base58", `["Ldp","base58"]`, Data{1, 2, 3}, ""},
+		{"base64+zstd", `["KLUv/QBYGQAAAQID","base64+zstd"]`, Data{1, 2, 3}, ""},
+		{"empty", `["","base64"]`, Data{}, ""},
+		{"unsupported", `["AQID","jsonParsed"]`, nil, "unsupported encoding jsonParsed"},
+		{"invalid length", `["AQID"]`, nil, "invalid length for solana.Data, expected 2, found 1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var data Data
+			err := json.Unmarshal([]byte(test.in), &data)
+			if test.expectedErr != "" {
+				require.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, data)
+		})
+	}
+}
@@ -23,9 +23,14 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/mr-tron/base58"
 )
 
+// zstdDecoder decodes `base64+zstd` account data, it's safe for concurrent use
+// through DecodeAll.
+var zstdDecoder, _ = zstd.NewReader(nil)
+
 type Padding []byte
 
 type Hash PublicKey
@@ -81,7 +86,7 @@ func (s Signature) MarshalJSON() ([]byte, error) {
 
 func (s *Signature) UnmarshalJSON(data []byte) (err error) {
 	var str string
-	err = json.Unmarshal(data, &s)
+	err = json.Unmarshal(data, &str)
 	if err != nil {
 		return
 	}
@@ -92,7 +97,7 @@ func (s *Signature) UnmarshalJSON(data []byte) (err error) {
 	}
 
 	if len(dat) != 64 {
-		return errors.New("invalid data length for public key")
+		return errors.New("invalid data length for signature")
 	}
 
 	target := Signature{}
@@ -105,6 +110,10 @@ func (s Signature) Verify(publicKey PublicKey, message []byte) bool {
 	return ed25519.Verify(ed25519.PublicKey(publicKey[:]), message, s[:])
 }
 
+func (s Signature) IsZero() bool {
+	return s == Signature{}
+}
+
 func (s Signature) String() string {
 	return base58.Encode(s[:])
 }
@@ -150,6 +159,15 @@ func (t *Data) UnmarshalJSON(data []byte) (err error) {
 	switch in[1] {
 	case "base64":
 		*t, err = base64.StdEncoding.DecodeString(in[0])
+	case "base58":
+		*t, err = base58.Decode(in[0])
+	case "base64+zstd":
+		compressed, err := base64.StdEncoding.DecodeString(in[0])
+		if err != nil {
+			return err
+		}
+		*t, err = zstdDecoder.DecodeAll(compressed, nil)
+		return err
 	default:
 		return fmt.Errorf("unsupported encoding %s", in[1])
 	}
@@ -0,0 +1,351 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addresslookuptable
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	bin "github.com/streamingfast/binary"
+	"github.com/streamingfast/solana-go"
+	"github.com/streamingfast/solana-go/programs/system"
+	"github.com/streamingfast/solana-go/text"
+)
+
+var PROGRAM_ID = solana.MustPublicKeyFromBase58("AddressLookupTab1e1111111111111111111111111")
+
+func init() {
+	solana.RegisterInstructionDecoder(PROGRAM_ID, registryDecodeInstruction)
+}
+
+func registryDecodeInstruction(accounts []*solana.AccountMeta, data []byte) (interface{}, error) {
+	inst, err := DecodeInstruction(accounts, data)
+	if err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+func DecodeInstruction(accounts []*solana.AccountMeta, data []byte) (*Instruction, error) {
+	var inst Instruction
+	if err := bin.NewDecoder(data).Decode(&inst); err != nil {
+		return nil, fmt.Errorf("unable to decode instruction for address lookup table program: %w", err)
+	}
+
+	if v, ok := inst.Impl.(solana.AccountSettable); ok {
+		err := v.SetAccounts(accounts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set accounts for instruction: %w", err)
+		}
+	}
+
+	return &inst, nil
+}
+
+// DeriveLookupTableAddress returns the address of the lookup table created by
+// `authority` with the given recent slot, alongside the bump seed that must be
+// passed to the CreateLookupTable instruction.
+func DeriveLookupTableAddress(authority solana.PublicKey, recentSlot uint64) (solana.PublicKey, uint8, error) {
+	slot := make([]byte, 8)
+	binary.LittleEndian.PutUint64(slot, recentSlot)
+
+	address, bump, err := solana.PublicKeyFindProgramAddress([][]byte{authority[:], slot}, PROGRAM_ID)
+	if err != nil {
+		return solana.PublicKey{}, 0, fmt.Errorf("unable to derive lookup table address: %w", err)
+	}
+	return address, bump, nil
+}
+
+var InstructionDefVariant = bin.NewVariantDefinition(bin.Uint32TypeIDEncoding, []bin.VariantType{
+	{Name: "create_lookup_table", Type: (*CreateLookupTable)(nil)},
+	{Name: "freeze_lookup_table", Type: (*FreezeLookupTable)(nil)},
+	{Name: "extend_lookup_table", Type: (*ExtendLookupTable)(nil)},
+	{Name: "deactivate_lookup_table", Type: (*DeactivateLookupTable)(nil)},
+	{Name: "close_lookup_table", Type: (*CloseLookupTable)(nil)},
+})
+
+type Instruction struct {
+	bin.BaseVariant
+}
+
+func (i *Instruction) Accounts() (out []*solana.AccountMeta) {
+	switch i.TypeID {
+	case 0:
+		accounts := i.Impl.(*CreateLookupTable).Accounts
+		out = []*solana.AccountMeta{accounts.LookupTable, accounts.Authority, accounts.Payer, accounts.SystemProgram}
+	case 1:
+		accounts := i.Impl.(*FreezeLookupTable).Accounts
+		out = []*solana.AccountMeta{accounts.LookupTable, accounts.Authority}
+	case 2:
+		accounts := i.Impl.(*ExtendLookupTable).Accounts
+		out = []*solana.AccountMeta{accounts.LookupTable, accounts.Authority}
+		if accounts.Payer != nil {
+			out = append(out, accounts.Payer, accounts.SystemProgram)
+		}
+	case 3:
+		accounts := i.Impl.(*DeactivateLookupTable).Accounts
+		out = []*solana.AccountMeta{accounts.LookupTable, accounts.Authority}
+	case 4:
+		accounts := i.Impl.(*CloseLookupTable).Accounts
+		out = []*solana.AccountMeta{accounts.LookupTable, accounts.Authority, accounts.Recipient}
+	}
+	return
+}
+
+func (i *Instruction) ProgramID() solana.PublicKey {
+	return PROGRAM_ID
+}
+
+func (i *Instruction) Data() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := bin.NewEncoder(buf).Encode(i); err != nil {
+		return nil, fmt.Errorf("unable to encode instruction: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (i *Instruction) TextEncode(encoder *text.Encoder, option *text.Option) error {
+	return encoder.Encode(i.Impl, option)
+}
+
+func (i *Instruction) UnmarshalBinary(decoder *bin.Decoder) error {
+	return i.BaseVariant.UnmarshalBinaryVariant(decoder, InstructionDefVariant)
+}
+
+func (i *Instruction) MarshalBinary(encoder *bin.Encoder) error {
+	err := encoder.WriteUint32(i.TypeID, binary.LittleEndian)
+	if err != nil {
+		return fmt.Errorf("unable to write variant type: %w", err)
+	}
+	return encoder.Encode(i.Impl)
+}
+
+type CreateLookupTableAccounts struct {
+	LookupTable   *solana.AccountMeta `text:"linear,notype"`
+	Authority     *solana.AccountMeta `text:"linear,notype"`
+	Payer         *solana.AccountMeta `text:"linear,notype"`
+	SystemProgram *solana.AccountMeta `text:"linear,notype"`
+}
+
+type CreateLookupTable struct {
+	RecentSlot uint64
+	BumpSeed   uint8
+
+	Accounts *CreateLookupTableAccounts `bin:"-"`
+}
+
+func (i *CreateLookupTable) SetAccounts(accounts []*solana.AccountMeta) error {
+	if len(accounts) < 4 {
+		return fmt.Errorf("insufficient account, CreateLookupTable requires at-least 4 accounts not %d", len(accounts))
+	}
+	i.Accounts = &CreateLookupTableAccounts{
+		LookupTable:   accounts[0],
+		Authority:     accounts[1],
+		Payer:         accounts[2],
+		SystemProgram: accounts[3],
+	}
+	return nil
+}
+
+// NewCreateLookupTableInstruction creates a lookup table controlled by `authority`
+// at the address derived from it and `recentSlot`, which is returned alongside
+// the instruction. The slot must be recent enough for the program to find it in
+// the SlotHashes sysvar.
+func NewCreateLookupTableInstruction(authority, payer solana.PublicKey, recentSlot uint64) (*Instruction, solana.PublicKey, error) {
+	lookupTable, bump, err := DeriveLookupTableAddress(authority, recentSlot)
+	if err != nil {
+		return nil, solana.PublicKey{}, err
+	}
+
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 0,
+			Impl: &CreateLookupTable{
+				RecentSlot: recentSlot,
+				BumpSeed:   bump,
+				Accounts: &CreateLookupTableAccounts{
+					LookupTable:   &solana.AccountMeta{PublicKey: lookupTable, IsWritable: true},
+					Authority:     &solana.AccountMeta{PublicKey: authority, IsSigner: true},
+					Payer:         &solana.AccountMeta{PublicKey: payer, IsSigner: true, IsWritable: true},
+					SystemProgram: &solana.AccountMeta{PublicKey: system.PROGRAM_ID},
+				},
+			},
+		},
+	}, lookupTable, nil
+}
+
+type FreezeLookupTableAccounts struct {
+	LookupTable *solana.AccountMeta `text:"linear,notype"`
+	Authority   *solana.AccountMeta `text:"linear,notype"`
+}
+
+type FreezeLookupTable struct {
+	Accounts *FreezeLookupTableAccounts `bin:"-"`
+}
+
+func (i *FreezeLookupTable) SetAccounts(accounts []*solana.AccountMeta) error {
+	if len(accounts) < 2 {
+		return fmt.Errorf("insufficient account, FreezeLookupTable requires at-least 2 accounts not %d", len(accounts))
+	}
+	i.Accounts = &FreezeLookupTableAccounts{
+		LookupTable: accounts[0],
+		Authority:   accounts[1],
+	}
+	return nil
+}
+
+func NewFreezeLookupTableInstruction(lookupTable, authority solana.PublicKey) *Instruction {
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 1,
+			Impl: &FreezeLookupTable{
+				Accounts: &FreezeLookupTableAccounts{
+					LookupTable: &solana.AccountMeta{PublicKey: lookupTable, IsWritable: true},
+					Authority:   &solana.AccountMeta{PublicKey: authority, IsSigner: true},
+				},
+			},
+		},
+	}
+}
+
+type ExtendLookupTableAccounts struct {
+	LookupTable   *solana.AccountMeta `text:"linear,notype"`
+	Authority     *solana.AccountMeta `text:"linear,notype"`
+	Payer         *solana.AccountMeta `text:"linear,notype"`
+	SystemProgram *solana.AccountMeta `text:"linear,notype"`
+}
+
+type ExtendLookupTable struct {
+	// Bincode encodes vector length as a little endian u64
+	AddressCount uint64 `bin:"sizeof=Addresses"`
+	Addresses    []solana.PublicKey
+
+	Accounts *ExtendLookupTableAccounts `bin:"-"`
+}
+
+func (i *ExtendLookupTable) SetAccounts(accounts []*solana.AccountMeta) error {
+	if len(accounts) < 2 {
+		return fmt.Errorf("insufficient account, ExtendLookupTable requires at-least 2 accounts not %d", len(accounts))
+	}
+	i.Accounts = &ExtendLookupTableAccounts{
+		LookupTable: accounts[0],
+		Authority:   accounts[1],
+	}
+	if len(accounts) >= 4 {
+		i.Accounts.Payer = accounts[2]
+		i.Accounts.SystemProgram = accounts[3]
+	}
+	return nil
+}
+
+// NewExtendLookupTableInstruction appends `addresses` to the lookup table. The
+// payer funds the rent required by the extra space, it can be left zero if the
+// table already holds enough lamports.
+func NewExtendLookupTableInstruction(lookupTable, authority, payer solana.PublicKey, addresses []solana.PublicKey) *Instruction {
+	accounts := &ExtendLookupTableAccounts{
+		LookupTable: &solana.AccountMeta{PublicKey: lookupTable, IsWritable: true},
+		Authority:   &solana.AccountMeta{PublicKey: authority, IsSigner: true},
+	}
+	if !payer.IsZero() {
+		accounts.Payer = &solana.AccountMeta{PublicKey: payer, IsSigner: true, IsWritable: true}
+		accounts.SystemProgram = &solana.AccountMeta{PublicKey: system.PROGRAM_ID}
+	}
+
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 2,
+			Impl: &ExtendLookupTable{
+				AddressCount: uint64(len(addresses)),
+				Addresses:    addresses,
+				Accounts:     accounts,
+			},
+		},
+	}
+}
+
+type DeactivateLookupTableAccounts struct {
+	LookupTable *solana.AccountMeta `text:"linear,notype"`
+	Authority   *solana.AccountMeta `text:"linear,notype"`
+}
+
+type DeactivateLookupTable struct {
+	Accounts *DeactivateLookupTableAccounts `bin:"-"`
+}
+
+func (i *DeactivateLookupTable) SetAccounts(accounts []*solana.AccountMeta) error {
+	if len(accounts) < 2 {
+		return fmt.Errorf("insufficient account, DeactivateLookupTable requires at-least 2 accounts not %d", len(accounts))
+	}
+	i.Accounts = &DeactivateLookupTableAccounts{
+		LookupTable: accounts[0],
+		Authority:   accounts[1],
+	}
+	return nil
+}
+
+func NewDeactivateLookupTableInstruction(lookupTable, authority solana.PublicKey) *Instruction {
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 3,
+			Impl: &DeactivateLookupTable{
+				Accounts: &DeactivateLookupTableAccounts{
+					LookupTable: &solana.AccountMeta{PublicKey: lookupTable, IsWritable: true},
+					Authority:   &solana.AccountMeta{PublicKey: authority, IsSigner: true},
+				},
+			},
+		},
+	}
+}
+
+type CloseLookupTableAccounts struct {
+	LookupTable *solana.AccountMeta `text:"linear,notype"`
+	Authority   *solana.AccountMeta `text:"linear,notype"`
+	Recipient   *solana.AccountMeta `text:"linear,notype"`
+}
+
+type CloseLookupTable struct {
+	Accounts *CloseLookupTableAccounts `bin:"-"`
+}
+
+func (i *CloseLookupTable) SetAccounts(accounts []*solana.AccountMeta) error {
+	if len(accounts) < 3 {
+		return fmt.Errorf("insufficient account, CloseLookupTable requires at-least 3 accounts not %d", len(accounts))
+	}
+	i.Accounts = &CloseLookupTableAccounts{
+		LookupTable: accounts[0],
+		Authority:   accounts[1],
+		Recipient:   accounts[2],
+	}
+	return nil
+}
+
+// NewCloseLookupTableInstruction closes a deactivated lookup table, sending its
+// lamports to `recipient`. The table must have been deactivated long enough for
+// its deactivation slot to be out of the SlotHashes sysvar.
+func NewCloseLookupTableInstruction(lookupTable, authority, recipient solana.PublicKey) *Instruction {
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 4,
+			Impl: &CloseLookupTable{
+				Accounts: &CloseLookupTableAccounts{
+					LookupTable: &solana.AccountMeta{PublicKey: lookupTable, IsWritable: true},
+					Authority:   &solana.AccountMeta{PublicKey: authority, IsSigner: true},
+					Recipient:   &solana.AccountMeta{PublicKey: recipient, IsWritable: true},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package addresslookuptable
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/streamingfast/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCreateLookupTableInstruction(t *testing.T) {
+	authority := solana.MustPublicKeyFromBase58("Gg1CWowuNc9ytKuX1p7hZ2mgBmWrjT9eNeXf8gvdq1Kd")
+	payer := solana.MustPublicKeyFromBase58("2kPGkTUzGZDTwxamiC99vggZZ52Dj6TKLNTErXmbNVwt")
+
+	inst, tableAddr, err := NewCreateLookupTableInstruction(authority, payer, 123456)
+	require.NoError(t, err)
+
+	expectedAddr, bump, err := DeriveLookupTableAddress(authority, 123456)
+	require.NoError(t, err)
+	assert.Equal(t, expectedAddr, tableAddr)
+
+	data, err := inst.Data()
+	require.NoError(t, err)
+	assert.Equal(t, "0000000040e2010000000000"+hex.EncodeToString([]byte{bump}), hex.EncodeToString(data))
+
+	accounts := inst.Accounts()
+	require.Len(t, accounts, 4)
+	assert.Equal(t, &solana.AccountMeta{PublicKey: tableAddr, IsWritable: true}, accounts[0])
+	assert.Equal(t, &solana.AccountMeta{PublicKey: authority, IsSigner: true}, accounts[1])
+	assert.Equal(t, &solana.AccountMeta{PublicKey: payer, IsSigner: true, IsWritable: true}, accounts[2])
+	assert.Equal(t, &solana.AccountMeta{PublicKey: system.PROGRAM_ID}, accounts[3])
+}
+
+func TestNewExtendLookupTableInstruction(t *testing.T) {
+	table := solana.MustPublicKeyFromBase58("2kPGkTUzGZDTwxamiC99vggZZ52Dj6TKLNTErXmbNVwt")
+	authority := solana.MustPublicKeyFromBase58("Gg1CWowuNc9ytKuX1p7hZ2mgBmWrjT9eNeXf8gvdq1Kd")
+	address := solana.MustPublicKeyFromBase58("Gr5UanqwiKA54GGnw4b1bB5M8eatQzj6s6FQ9FeTze5C")
+
+	inst := NewExtendLookupTableInstruction(table, authority, solana.PublicKey{}, []solana.PublicKey{address})
+
+	data, err := inst.Data()
+	require.NoError(t, err)
+	assert.Equal(t, "020000000100000000000000eb71d2f68370da513ad471ed6bb975a331931cb4e221aead8247ab8e4618d04f", hex.EncodeToString(data))
+	assert.Len(t, inst.Accounts(), 2)
+
+	decoded, err := DecodeInstruction(inst.Accounts(), data)
+	require.NoError(t, err)
+	extend := decoded.Impl.(*ExtendLookupTable)
+	assert.Equal(t, []solana.PublicKey{address}, extend.Addresses)
+	assert.Equal(t, table, extend.Accounts.LookupTable.PublicKey)
+	assert.Nil(t, extend.Accounts.Payer)
+}
+
+func TestDecodeInstruction_Simple(t *testing.T) {
+	table := solana.MustPublicKeyFromBase58("2kPGkTUzGZDTwxamiC99vggZZ52Dj6TKLNTErXmbNVwt")
+	authority := solana.MustPublicKeyFromBase58("Gg1CWowuNc9ytKuX1p7hZ2mgBmWrjT9eNeXf8gvdq1Kd")
+	recipient := solana.MustPublicKeyFromBase58("Gr5UanqwiKA54GGnw4b1bB5M8eatQzj6s6FQ9FeTze5C")
+
+	tests := []struct {
+		name         string
+		inst         *Instruction
+		expectedData string
+		expectedType interface{}
+	}{
+		{"freeze", NewFreezeLookupTableInstruction(table, authority), "01000000", &FreezeLookupTable{}},
+		{"deactivate", NewDeactivateLookupTableInstruction(table, authority), "03000000", &DeactivateLookupTable{}},
+		{"close", NewCloseLookupTableInstruction(table, authority, recipient), "04000000", &CloseLookupTable{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := test.inst.Data()
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedData, hex.EncodeToString(data))
+
+			decoded, err := DecodeInstruction(test.inst.Accounts(), data)
+			require.NoError(t, err)
+			assert.IsType(t, test.expectedType, decoded.Impl)
+			assert.Equal(t, test.inst.Accounts(), decoded.Accounts())
+		})
+	}
+}
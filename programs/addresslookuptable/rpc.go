@@ -0,0 +1,59 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addresslookuptable
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/streamingfast/solana-go/rpc"
+)
+
+func FetchLookupTable(ctx context.Context, rpcCli *rpc.Client, tableAddr solana.PublicKey) (*LookupTable, error) {
+	resp, err := rpcCli.GetAccountInfo(ctx, tableAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.Value.Owner.Equals(PROGRAM_ID) {
+		return nil, fmt.Errorf("account %q is not owned by the address lookup table program, owner is %q", tableAddr, resp.Value.Owner)
+	}
+
+	t := &LookupTable{}
+	if err := t.Decode(tableAddr, resp.Value.Data); err != nil {
+		return nil, fmt.Errorf("unable to decode lookup table %q: %w", tableAddr.String(), err)
+	}
+	return t, nil
+}
+
+// FetchMessageAddressTables fetches every lookup table referenced by a
+// versioned message and returns them in the form expected by
+// `solana.Message.SetAddressTables`.
+func FetchMessageAddressTables(ctx context.Context, rpcCli *rpc.Client, message *solana.Message) (map[solana.PublicKey][]solana.PublicKey, error) {
+	out := make(map[solana.PublicKey][]solana.PublicKey, len(message.AddressTableLookups))
+	for _, lookup := range message.AddressTableLookups {
+		if _, found := out[lookup.AccountKey]; found {
+			continue
+		}
+
+		table, err := FetchLookupTable(ctx, rpcCli, lookup.AccountKey)
+		if err != nil {
+			return nil, err
+		}
+		out[lookup.AccountKey] = table.Addresses
+	}
+	return out, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addresslookuptable
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/streamingfast/solana-go"
+)
+
+// LOOKUP_TABLE_META_SIZE is the fixed size of the serialized lookup table state,
+// addresses are stored right after it.
+const LOOKUP_TABLE_META_SIZE = 56
+
+const LOOKUP_TABLE_MAX_ADDRESSES = 256
+
+func init() {
+	// Initialized lookup tables start with the u32 `ProgramState::LookupTable` discriminator
+	solana.RegisterAccountDecoder(PROGRAM_ID, solana.MatchDiscriminator([]byte{1, 0, 0, 0}), func(key solana.PublicKey, data []byte) (interface{}, error) {
+		table := &LookupTable{}
+		return table, table.Decode(key, data)
+	})
+}
+
+type LookupTableMeta struct {
+	// DeactivationSlot is math.MaxUint64 while the table is active
+	DeactivationSlot           uint64
+	LastExtendedSlot           uint64
+	LastExtendedSlotStartIndex uint8
+	// Authority is nil once the table has been frozen
+	Authority *solana.PublicKey
+}
+
+type LookupTable struct {
+	Key       solana.PublicKey `bin:"-"`
+	Meta      LookupTableMeta
+	Addresses []solana.PublicKey
+}
+
+// Decode reads the on-chain account layout: a u32 state discriminator, the
+// meta fields (authority being a bincode `Option<Pubkey>`), two bytes of
+// padding and then the addresses, starting at LOOKUP_TABLE_META_SIZE.
+func (t *LookupTable) Decode(key solana.PublicKey, in []byte) error {
+	if len(in) < LOOKUP_TABLE_META_SIZE {
+		return fmt.Errorf("unpack: expected at least %d bytes, got %d", LOOKUP_TABLE_META_SIZE, len(in))
+	}
+
+	state := binary.LittleEndian.Uint32(in)
+	if state != 1 {
+		return fmt.Errorf("unpack: lookup table is not initialized (state %d)", state)
+	}
+
+	t.Meta = LookupTableMeta{
+		DeactivationSlot:           binary.LittleEndian.Uint64(in[4:]),
+		LastExtendedSlot:           binary.LittleEndian.Uint64(in[12:]),
+		LastExtendedSlotStartIndex: in[20],
+	}
+	if in[21] == 1 {
+		authority := solana.PublicKeyFromBytes(in[22:54])
+		t.Meta.Authority = &authority
+	}
+
+	raw := in[LOOKUP_TABLE_META_SIZE:]
+	if len(raw)%32 != 0 {
+		return fmt.Errorf("unpack: addresses data length %d is not a multiple of 32", len(raw))
+	}
+
+	t.Key = key
+	t.Addresses = make([]solana.PublicKey, len(raw)/32)
+	for i := range t.Addresses {
+		t.Addresses[i] = solana.PublicKeyFromBytes(raw[i*32 : (i+1)*32])
+	}
+	return nil
+}
+
+func (t *LookupTable) IsActive() bool {
+	return t.Meta.DeactivationSlot == math.MaxUint64
+}
+
+func (t *LookupTable) IsFrozen() bool {
+	return t.Meta.Authority == nil
+}
@@ -0,0 +1,63 @@
+package addresslookuptable
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupTable_Decode(t *testing.T) {
+	key := solana.MustPublicKeyFromBase58("2kPGkTUzGZDTwxamiC99vggZZ52Dj6TKLNTErXmbNVwt")
+	authority := solana.MustPublicKeyFromBase58("Gg1CWowuNc9ytKuX1p7hZ2mgBmWrjT9eNeXf8gvdq1Kd")
+	address1 := solana.MustPublicKeyFromBase58("Gr5UanqwiKA54GGnw4b1bB5M8eatQzj6s6FQ9FeTze5C")
+	address2 := solana.MustPublicKeyFromBase58("11111111111111111111111111111111")
+
+	meta, err := hex.DecodeString(
+		"01000000" + // state
+			"ffffffffffffffff" + // deactivation slot
+			"40e2010000000000" + // last extended slot
+			"02" + // last extended slot start index
+			"01" + hex.EncodeToString(authority[:]) + // authority
+			"0000", // padding
+	)
+	require.NoError(t, err)
+	data := append(meta, append(address1[:], address2[:]...)...)
+
+	table := &LookupTable{}
+	require.NoError(t, table.Decode(key, data))
+
+	assert.Equal(t, key, table.Key)
+	assert.True(t, table.IsActive())
+	assert.False(t, table.IsFrozen())
+	assert.Equal(t, uint64(123456), table.Meta.LastExtendedSlot)
+	assert.Equal(t, uint8(2), table.Meta.LastExtendedSlotStartIndex)
+	assert.Equal(t, authority, *table.Meta.Authority)
+	assert.Equal(t, []solana.PublicKey{address1, address2}, table.Addresses)
+}
+
+func TestLookupTable_Decode_Frozen(t *testing.T) {
+	data := make([]byte, LOOKUP_TABLE_META_SIZE)
+	data[0] = 1
+	copy(data[4:], bytes.Repeat([]byte{0x01}, 8))
+
+	table := &LookupTable{}
+	require.NoError(t, table.Decode(solana.PublicKey{}, data))
+
+	assert.False(t, table.IsActive())
+	assert.True(t, table.IsFrozen())
+	assert.Len(t, table.Addresses, 0)
+}
+
+func TestLookupTable_Decode_Invalid(t *testing.T) {
+	table := &LookupTable{}
+	assert.Error(t, table.Decode(solana.PublicKey{}, make([]byte, 10)))
+	assert.Error(t, table.Decode(solana.PublicKey{}, make([]byte, LOOKUP_TABLE_META_SIZE)))
+
+	data := make([]byte, LOOKUP_TABLE_META_SIZE+10)
+	data[0] = 1
+	assert.Error(t, table.Decode(solana.PublicKey{}, data))
+}
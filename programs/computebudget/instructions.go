@@ -0,0 +1,153 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package computebudget
+
+import (
+	"bytes"
+	"fmt"
+
+	bin "github.com/streamingfast/binary"
+	"github.com/streamingfast/solana-go"
+	"github.com/streamingfast/solana-go/text"
+)
+
+var PROGRAM_ID = solana.MustPublicKeyFromBase58("ComputeBudget111111111111111111111111111111")
+
+func init() {
+	solana.RegisterInstructionDecoder(PROGRAM_ID, registryDecodeInstruction)
+}
+
+func registryDecodeInstruction(accounts []*solana.AccountMeta, data []byte) (interface{}, error) {
+	inst, err := DecodeInstruction(accounts, data)
+	if err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+func DecodeInstruction(accounts []*solana.AccountMeta, data []byte) (*Instruction, error) {
+	var inst Instruction
+	if err := bin.NewDecoder(data).Decode(&inst); err != nil {
+		return nil, fmt.Errorf("unable to decode instruction for compute budget program: %w", err)
+	}
+	return &inst, nil
+}
+
+var InstructionDefVariant = bin.NewVariantDefinition(bin.Uint8TypeIDEncoding, []bin.VariantType{
+	{Name: "request_units_deprecated", Type: (*RequestUnitsDeprecated)(nil)},
+	{Name: "request_heap_frame", Type: (*RequestHeapFrame)(nil)},
+	{Name: "set_compute_unit_limit", Type: (*SetComputeUnitLimit)(nil)},
+	{Name: "set_compute_unit_price", Type: (*SetComputeUnitPrice)(nil)},
+	{Name: "set_loaded_accounts_data_size_limit", Type: (*SetLoadedAccountsDataSizeLimit)(nil)},
+})
+
+type Instruction struct {
+	bin.BaseVariant
+}
+
+// Accounts is always empty, compute budget instructions do not reference any account.
+func (i *Instruction) Accounts() (out []*solana.AccountMeta) {
+	return []*solana.AccountMeta{}
+}
+
+func (i *Instruction) ProgramID() solana.PublicKey {
+	return PROGRAM_ID
+}
+
+func (i *Instruction) Data() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := bin.NewEncoder(buf).Encode(i); err != nil {
+		return nil, fmt.Errorf("unable to encode instruction: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (i *Instruction) TextEncode(encoder *text.Encoder, option *text.Option) error {
+	return encoder.Encode(i.Impl, option)
+}
+
+func (i *Instruction) UnmarshalBinary(decoder *bin.Decoder) error {
+	return i.BaseVariant.UnmarshalBinaryVariant(decoder, InstructionDefVariant)
+}
+
+func (i *Instruction) MarshalBinary(encoder *bin.Encoder) error {
+	err := encoder.WriteUint8(uint8(i.TypeID))
+	if err != nil {
+		return fmt.Errorf("unable to write variant type: %w", err)
+	}
+	return encoder.Encode(i.Impl)
+}
+
+// RequestUnitsDeprecated has been replaced by SetComputeUnitLimit and
+// SetComputeUnitPrice, it is only kept to decode old transactions.
+type RequestUnitsDeprecated struct {
+	Units         uint32
+	AdditionalFee uint32
+}
+
+type RequestHeapFrame struct {
+	// Bytes must be a multiple of 1024
+	Bytes uint32
+}
+
+func NewRequestHeapFrameInstruction(bytes uint32) *Instruction {
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 1,
+			Impl:   &RequestHeapFrame{Bytes: bytes},
+		},
+	}
+}
+
+type SetComputeUnitLimit struct {
+	Units uint32
+}
+
+func NewSetComputeUnitLimitInstruction(units uint32) *Instruction {
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 2,
+			Impl:   &SetComputeUnitLimit{Units: units},
+		},
+	}
+}
+
+type SetComputeUnitPrice struct {
+	MicroLamports uint64
+}
+
+// NewSetComputeUnitPriceInstruction sets the priority fee paid per compute
+// unit, expressed in micro-lamports.
+func NewSetComputeUnitPriceInstruction(microLamports uint64) *Instruction {
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 3,
+			Impl:   &SetComputeUnitPrice{MicroLamports: microLamports},
+		},
+	}
+}
+
+type SetLoadedAccountsDataSizeLimit struct {
+	Bytes uint32
+}
+
+func NewSetLoadedAccountsDataSizeLimitInstruction(bytes uint32) *Instruction {
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 4,
+			Impl:   &SetLoadedAccountsDataSizeLimit{Bytes: bytes},
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package computebudget
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstructions(t *testing.T) {
+	tests := []struct {
+		name         string
+		inst         *Instruction
+		expectedData string
+		expectedImpl interface{}
+	}{
+		{"request heap frame", NewRequestHeapFrameInstruction(256 * 1024), "0100000400", &RequestHeapFrame{Bytes: 262144}},
+		{"set compute unit limit", NewSetComputeUnitLimitInstruction(200000), "02400d0300", &SetComputeUnitLimit{Units: 200000}},
+		{"set compute unit price", NewSetComputeUnitPriceInstruction(10000), "031027000000000000", &SetComputeUnitPrice{MicroLamports: 10000}},
+		{"set loaded accounts data size limit", NewSetLoadedAccountsDataSizeLimitInstruction(65536), "0400000100", &SetLoadedAccountsDataSizeLimit{Bytes: 65536}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := test.inst.Data()
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedData, hex.EncodeToString(data))
+
+			decoded, err := DecodeInstruction(nil, data)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedImpl, decoded.Impl)
+		})
+	}
+}
+
+func TestDecodeInstruction_RequestUnitsDeprecated(t *testing.T) {
+	data, err := hex.DecodeString("00400d030010270000")
+	require.NoError(t, err)
+
+	decoded, err := DecodeInstruction(nil, data)
+	require.NoError(t, err)
+	assert.Equal(t, &RequestUnitsDeprecated{Units: 200000, AdditionalFee: 10000}, decoded.Impl)
+}
@@ -8,6 +8,13 @@ import (
 	"github.com/streamingfast/solana-go"
 )
 
+func init() {
+	solana.RegisterAccountDecoder(PROGRAM_ID, solana.MatchDiscriminator([]byte{MetadataV1}), func(key solana.PublicKey, data []byte) (interface{}, error) {
+		metadata := &Metadata{}
+		return metadata, metadata.Decode(data)
+	})
+}
+
 type Key borsh.Enum
 
 const (
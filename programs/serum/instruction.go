@@ -11,6 +11,11 @@ import (
 
 func init() {
 	solana.RegisterInstructionDecoder(DEXProgramIDV2, registryDecodeInstruction)
+	for _, programID := range DEXProgramIDs {
+		if programID != DEXProgramIDV2 {
+			solana.RegisterInstructionDecoderAlias(programID, DEXProgramIDV2)
+		}
+	}
 }
 
 func registryDecodeInstruction(accounts []*solana.AccountMeta, data []byte) (interface{}, error) {
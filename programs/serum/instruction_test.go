@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	bin "github.com/streamingfast/binary"
+	"github.com/streamingfast/solana-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -69,3 +70,19 @@ func TestDecodeInstruction(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeInstruction_AllDEXVersions(t *testing.T) {
+	data, err := hex.DecodeString("000900000001000000b80600000000000010eb09000000000000000000168106e091da511601000000")
+	require.NoError(t, err)
+
+	accounts := make([]*solana.AccountMeta, 12)
+	for i := range accounts {
+		accounts[i] = &solana.AccountMeta{}
+	}
+
+	for _, programID := range DEXProgramIDs {
+		decoded, err := solana.DecodeInstruction(programID, accounts, data)
+		require.NoError(t, err, "program %s", programID)
+		assert.Equal(t, uint32(9), decoded.(*Instruction).TypeID)
+	}
+}
@@ -15,6 +15,7 @@
 package serum
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/streamingfast/solana-go"
@@ -39,6 +40,7 @@ func (m *MarketMeta) quoteSplTokenMultiplier() *big.Int {
 	return solana.DecimalsInBigInt(uint32(m.QuoteMint.Decimals))
 }
 
+// Deprecated: big.Float loses precision, use PriceLotsToAmount instead.
 func (m *MarketMeta) PriceLotsToNumber(price *big.Int) *big.Float {
 	ratio := I().Mul(I().SetInt64(int64(m.Market.GetQuoteLotSize())), m.baseSplTokenMultiplier())
 	numerator := F().Mul(F().SetInt(price), F().SetInt(ratio))
@@ -47,6 +49,7 @@ func (m *MarketMeta) PriceLotsToNumber(price *big.Int) *big.Float {
 	return v
 }
 
+// Deprecated: big.Float loses precision, use BaseSizeLotsToAmount instead.
 func (m *MarketMeta) BaseSizeLotsToNumber(size *big.Int) *big.Float {
 	numerator := I().Mul(size, I().SetInt64(int64(m.Market.GetBaseLotSize())))
 	denomiator := m.baseSplTokenMultiplier()
@@ -60,6 +63,66 @@ func (m *MarketMeta) PriceNumberToLots(price *big.Int) *big.Float {
 	return F().Quo(F().SetInt(numerator), F().SetInt(denomiator))
 }
 
+// BaseSizeLotsToAmount converts a size in base lots to the exact amount of
+// base token.
+func (m *MarketMeta) BaseSizeLotsToAmount(size uint64) (solana.TokenAmount, error) {
+	return solana.NewTokenAmount(m.Market.GetBaseLotSize(), m.BaseMint.Decimals).Mul(size)
+}
+
+// QuoteSizeLotsToAmount converts a size in quote lots to the exact amount of
+// quote token.
+func (m *MarketMeta) QuoteSizeLotsToAmount(size uint64) (solana.TokenAmount, error) {
+	return solana.NewTokenAmount(m.Market.GetQuoteLotSize(), m.QuoteMint.Decimals).Mul(size)
+}
+
+// PriceLotsToAmount converts a price in quote lots per base lot to the exact
+// amount of quote token paid for one base token. It's an error for the price
+// not to be representable with the quote token decimals.
+func (m *MarketMeta) PriceLotsToAmount(price uint64) (solana.TokenAmount, error) {
+	baseLotSize := m.Market.GetBaseLotSize()
+	if baseLotSize == 0 {
+		return solana.TokenAmount{}, fmt.Errorf("market %q has a zero base lot size", m.Address)
+	}
+
+	numerator := I().Mul(I().SetUint64(price), I().SetUint64(m.Market.GetQuoteLotSize()))
+	numerator.Mul(numerator, m.baseSplTokenMultiplier())
+
+	raw, remainder := I().QuoRem(numerator, I().SetUint64(baseLotSize), I())
+	if remainder.Sign() != 0 {
+		return solana.TokenAmount{}, fmt.Errorf("price %d lots is not representable with %d quote decimals", price, m.QuoteMint.Decimals)
+	}
+	if !raw.IsUint64() {
+		return solana.TokenAmount{}, solana.ErrAmountOverflow
+	}
+	return solana.NewTokenAmount(raw.Uint64(), m.QuoteMint.Decimals), nil
+}
+
+// PriceAmountToLots converts an amount of quote token paid for one base token
+// to a price in quote lots per base lot, the inverse of PriceLotsToAmount.
+// It's an error for the price not to be a whole number of lots.
+func (m *MarketMeta) PriceAmountToLots(price solana.TokenAmount) (uint64, error) {
+	if price.Decimals != m.QuoteMint.Decimals {
+		return 0, fmt.Errorf("price has %d decimals, quote token has %d", price.Decimals, m.QuoteMint.Decimals)
+	}
+
+	quoteLotSize := m.Market.GetQuoteLotSize()
+	if quoteLotSize == 0 {
+		return 0, fmt.Errorf("market %q has a zero quote lot size", m.Address)
+	}
+
+	numerator := I().Mul(I().SetUint64(price.Raw), I().SetUint64(m.Market.GetBaseLotSize()))
+	denominator := I().Mul(I().SetUint64(quoteLotSize), m.baseSplTokenMultiplier())
+
+	lots, remainder := I().QuoRem(numerator, denominator, I())
+	if remainder.Sign() != 0 {
+		return 0, fmt.Errorf("price %s is not a whole number of lots", price)
+	}
+	if !lots.IsUint64() {
+		return 0, solana.ErrAmountOverflow
+	}
+	return lots.Uint64(), nil
+}
+
 type OpenOrdersMeta struct {
 	OpenOrders OpenOrders
 }
@@ -0,0 +1,67 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serum
+
+import (
+	"testing"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/streamingfast/solana-go/programs/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarketMeta_Amounts(t *testing.T) {
+	// SOL/USDC like market: 9 base decimals, 6 quote decimals
+	meta := &MarketMeta{
+		BaseMint:  token.Mint{Decimals: 9},
+		QuoteMint: token.Mint{Decimals: 6},
+		Market:    &MarketV2{BaseLotSize: 100_000_000, QuoteLotSize: 100},
+	}
+
+	size, err := meta.BaseSizeLotsToAmount(25)
+	require.NoError(t, err)
+	assert.Equal(t, "2.5", size.String())
+
+	quote, err := meta.QuoteSizeLotsToAmount(25)
+	require.NoError(t, err)
+	assert.Equal(t, "0.0025", quote.String())
+
+	price, err := meta.PriceLotsToAmount(35_120)
+	require.NoError(t, err)
+	assert.Equal(t, solana.NewTokenAmount(35_120_000, 6), price)
+	assert.Equal(t, "35.12", price.String())
+
+	lots, err := meta.PriceAmountToLots(price)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(35_120), lots)
+
+	_, err = meta.PriceAmountToLots(solana.NewTokenAmount(35_120_001, 6))
+	assert.EqualError(t, err, "price 35.120001 is not a whole number of lots")
+
+	_, err = meta.PriceAmountToLots(solana.NewTokenAmount(1, 9))
+	assert.EqualError(t, err, "price has 9 decimals, quote token has 6")
+}
+
+func TestMarketMeta_PriceLotsToAmount_NotRepresentable(t *testing.T) {
+	meta := &MarketMeta{
+		BaseMint:  token.Mint{Decimals: 0},
+		QuoteMint: token.Mint{Decimals: 0},
+		Market:    &MarketV2{BaseLotSize: 3, QuoteLotSize: 1},
+	}
+
+	_, err := meta.PriceLotsToAmount(1)
+	assert.EqualError(t, err, "price 1 lots is not representable with 0 quote decimals")
+}
@@ -2,5 +2,18 @@ package serum
 
 import "github.com/streamingfast/solana-go"
 
+// DEXProgramIDV1 represents the fixed address on which the Serum DEX v1 smart contract is deployed
+var DEXProgramIDV1 = solana.MustPublicKeyFromBase58("4ckmDgGdxQoPDLUkDT3vHgSAkzA3QRdNq5ywwY4sUSJn")
+
+// DEXProgramIDV1b represents the second address on which the Serum DEX v1 smart contract is deployed
+var DEXProgramIDV1b = solana.MustPublicKeyFromBase58("BJ3jrUzddfuSrZHXSCxMUUQsjKEyLmuuyZebkcaFp2fg")
+
 // DEXProgramIDV2 represents the fixed address on which the Serum DEX v2 smart contract is deployed
 var DEXProgramIDV2 = solana.MustPublicKeyFromBase58("EUqojwWA2rd19FZrzeBncJsm38Jm1hEhE3zsmX3bRc2o")
+
+// DEXProgramIDV3 represents the fixed address on which the Serum DEX v3 smart contract is deployed
+var DEXProgramIDV3 = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+
+// DEXProgramIDs lists all known Serum DEX deployments, all of them share the
+// same instruction set, newer versions only adding instructions.
+var DEXProgramIDs = []solana.PublicKey{DEXProgramIDV1, DEXProgramIDV1b, DEXProgramIDV2, DEXProgramIDV3}
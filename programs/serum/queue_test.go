@@ -15,6 +15,7 @@
 package serum
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -42,7 +43,7 @@ func TestDecoder_EventQueue_Diff(t *testing.T) {
 		client := rpc.NewClient("https://api.mainnet-beta.solana.com:80/rpc")
 		account := solana.MustPublicKeyFromBase58("13iGJcA4w5hcJZDjJbJQor1zUiDLE4jv2rMW9HkD5Eo1")
 
-		info, err := client.GetAccountInfo(account)
+		info, err := client.GetAccountInfo(context.Background(), account)
 		require.NoError(t, err)
 		writeCompressedFile(t, oldDataFile, info.Value.Data)
 
@@ -52,7 +53,7 @@ func TestDecoder_EventQueue_Diff(t *testing.T) {
 
 		time.Sleep(900 * time.Millisecond)
 
-		info, err = client.GetAccountInfo(account)
+		info, err = client.GetAccountInfo(context.Background(), account)
 		require.NoError(t, err)
 		writeCompressedFile(t, newDataFile, info.Value.Data)
 
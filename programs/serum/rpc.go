@@ -47,7 +47,7 @@ func KnownMarket() ([]*MarketMeta, error) {
 }
 
 func FetchOpenOrders(ctx context.Context, rpcCli *rpc.Client, openOrdersAddr solana.PublicKey) (*OpenOrdersMeta, error) {
-	acctInfo, err := rpcCli.GetAccountInfo(openOrdersAddr)
+	acctInfo, err := rpcCli.GetAccountInfo(ctx, openOrdersAddr)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get open orders account:%w", err)
 	}
@@ -62,7 +62,7 @@ func FetchOpenOrders(ctx context.Context, rpcCli *rpc.Client, openOrdersAddr sol
 }
 
 func FetchMarket(ctx context.Context, rpcCli *rpc.Client, marketAddr solana.PublicKey) (*MarketMeta, error) {
-	acctInfo, err := rpcCli.GetAccountInfo(marketAddr)
+	acctInfo, err := rpcCli.GetAccountInfo(ctx, marketAddr)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get market account:%w", err)
 	}
@@ -73,7 +73,7 @@ func FetchMarket(ctx context.Context, rpcCli *rpc.Client, marketAddr solana.Publ
 
 	dataLen := len(acctInfo.Value.Data)
 	switch dataLen {
-	case 380:
+	case MARKET_V1_SIZE:
 		marketV1 := &MarketV1{}
 		if err := marketV1.Decode(acctInfo.Value.Data); err != nil {
 			return nil, fmt.Errorf("decoding market v1: %w", err)
@@ -81,7 +81,7 @@ func FetchMarket(ctx context.Context, rpcCli *rpc.Client, marketAddr solana.Publ
 
 		meta.Market = marketV1
 
-	case 388:
+	case MARKET_V2_SIZE:
 		marketV2 := &MarketV2{}
 		if err := marketV2.Decode(acctInfo.Value.Data); err != nil {
 			return nil, fmt.Errorf("decoding market v2: %w", err)
@@ -89,7 +89,7 @@ func FetchMarket(ctx context.Context, rpcCli *rpc.Client, marketAddr solana.Publ
 
 		meta.Market = marketV2
 
-	case 1476:
+	case MARKET_V3_SIZE:
 		marketV3 := &MarketV3{}
 		if err := marketV3.Decode(acctInfo.Value.Data); err != nil {
 			return nil, fmt.Errorf("decoding market v2: %w", err)
@@ -101,11 +101,11 @@ func FetchMarket(ctx context.Context, rpcCli *rpc.Client, marketAddr solana.Publ
 		return nil, fmt.Errorf("unsupported market data length: %d", dataLen)
 	}
 
-	if err := rpcCli.GetAccountDataIn(meta.Market.GetQuoteMint(), &meta.QuoteMint); err != nil {
+	if err := rpcCli.GetAccountDataIn(ctx, meta.Market.GetQuoteMint(), &meta.QuoteMint); err != nil {
 		return nil, fmt.Errorf("getting quote mint: %w", err)
 	}
 
-	if err := rpcCli.GetAccountDataIn(meta.Market.GetBaseMint(), &meta.BaseMint); err != nil {
+	if err := rpcCli.GetAccountDataIn(ctx, meta.Market.GetBaseMint(), &meta.BaseMint); err != nil {
 		return nil, fmt.Errorf("getting base token: %w", err)
 	}
 
@@ -24,6 +24,34 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	MARKET_V1_SIZE   = 380
+	MARKET_V2_SIZE   = 388
+	MARKET_V3_SIZE   = 1476
+	OPEN_ORDERS_SIZE = 3228
+)
+
+func init() {
+	for _, programID := range DEXProgramIDs {
+		solana.RegisterAccountDecoder(programID, solana.MatchDataSize(MARKET_V1_SIZE), func(key solana.PublicKey, data []byte) (interface{}, error) {
+			market := &MarketV1{}
+			return market, market.Decode(data)
+		})
+		solana.RegisterAccountDecoder(programID, solana.MatchDataSize(MARKET_V2_SIZE), func(key solana.PublicKey, data []byte) (interface{}, error) {
+			market := &MarketV2{}
+			return market, market.Decode(data)
+		})
+		solana.RegisterAccountDecoder(programID, solana.MatchDataSize(MARKET_V3_SIZE), func(key solana.PublicKey, data []byte) (interface{}, error) {
+			market := &MarketV3{}
+			return market, market.Decode(data)
+		})
+		solana.RegisterAccountDecoder(programID, solana.MatchDataSize(OPEN_ORDERS_SIZE), func(key solana.PublicKey, data []byte) (interface{}, error) {
+			openOrders := &OpenOrders{}
+			return openOrders, openOrders.Decode(data)
+		})
+	}
+}
+
 type AccountFlag uint64
 
 const (
@@ -371,6 +371,20 @@ func Test_OpenOrder_GetOrder(t *testing.T) {
 	assert.Equal(t, o.Price(), uint64(2112))
 }
 
+func TestDecodeAccount_Registry(t *testing.T) {
+	data := readHexFile(t, "testdata/serum-open-orders-new.hex")
+	require.Len(t, data, OPEN_ORDERS_SIZE)
+
+	expected := &OpenOrders{}
+	require.NoError(t, expected.Decode(data))
+
+	for _, programID := range DEXProgramIDs {
+		decoded, err := solana.DecodeAccount(solana.PublicKey{}, programID, data)
+		require.NoError(t, err)
+		assert.Equal(t, expected, decoded)
+	}
+}
+
 func TestIsBitZero(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -27,6 +27,7 @@ import (
 var PROGRAM_ID = solana.MustPublicKeyFromBase58("11111111111111111111111111111111")
 var SYSVAR_RENT = solana.MustPublicKeyFromBase58("SysvarRent111111111111111111111111111111111")
 var SYSVAR_CLOCK = solana.MustPublicKeyFromBase58("SysvarC1ock11111111111111111111111111111111")
+var SYSVAR_RECENT_BLOCKHASHES = solana.MustPublicKeyFromBase58("SysvarRecentB1ockHashes11111111111111111111")
 
 func init() {
 	solana.RegisterInstructionDecoder(PROGRAM_ID, registryDecodeInstruction)
@@ -87,6 +88,30 @@ func (i *Instruction) Accounts() (out []*solana.AccountMeta) {
 	case 2:
 		accounts := i.Impl.(*Transfer).Accounts
 		out = []*solana.AccountMeta{accounts.From, accounts.To}
+	case 3:
+		accounts := i.Impl.(*CreateAccountWithSeed).Accounts
+		out = []*solana.AccountMeta{accounts.From, accounts.To}
+		if accounts.Base != nil {
+			out = append(out, accounts.Base)
+		}
+	case 4:
+		accounts := i.Impl.(*AdvanceNonceAccount).Accounts
+		out = []*solana.AccountMeta{accounts.NonceAccount, accounts.RecentBlockhashesSysvar, accounts.NonceAuthority}
+	case 5:
+		accounts := i.Impl.(*WithdrawNonceAccount).Accounts
+		out = []*solana.AccountMeta{accounts.NonceAccount, accounts.To, accounts.RecentBlockhashesSysvar, accounts.RentSysvar, accounts.NonceAuthority}
+	case 6:
+		accounts := i.Impl.(*InitializeNonceAccount).Accounts
+		out = []*solana.AccountMeta{accounts.NonceAccount, accounts.RecentBlockhashesSysvar, accounts.RentSysvar}
+	case 7:
+		accounts := i.Impl.(*AuthorizeNonceAccount).Accounts
+		out = []*solana.AccountMeta{accounts.NonceAccount, accounts.NonceAuthority}
+	case 9:
+		accounts := i.Impl.(*AllocateWithSeed).Accounts
+		out = []*solana.AccountMeta{accounts.Account, accounts.Base}
+	case 10:
+		accounts := i.Impl.(*AssignWithSeed).Accounts
+		out = []*solana.AccountMeta{accounts.Account, accounts.Base}
 	}
 	return
 }
@@ -111,6 +136,14 @@ var InstructionImplDef = bin.NewVariantDefinition(bin.Uint32TypeIDEncoding, []bi
 	{"create_account", (*CreateAccount)(nil)},
 	{"assign", (*Assign)(nil)},
 	{"transfer", (*Transfer)(nil)},
+	{"create_account_with_seed", (*CreateAccountWithSeed)(nil)},
+	{"advance_nonce_account", (*AdvanceNonceAccount)(nil)},
+	{"withdraw_nonce_account", (*WithdrawNonceAccount)(nil)},
+	{"initialize_nonce_account", (*InitializeNonceAccount)(nil)},
+	{"authorize_nonce_account", (*AuthorizeNonceAccount)(nil)},
+	{"allocate", (*Allocate)(nil)},
+	{"allocate_with_seed", (*AllocateWithSeed)(nil)},
+	{"assign_with_seed", (*AssignWithSeed)(nil)},
 })
 
 func (i *Instruction) UnmarshalBinary(decoder *bin.Decoder) error {
@@ -169,33 +202,240 @@ func (i *Transfer) SetAccounts(accounts []*solana.AccountMeta) error {
 	return nil
 }
 
+type CreateAccountWithSeedAccounts struct {
+	From *solana.AccountMeta `text:"linear,notype"`
+	To   *solana.AccountMeta `text:"linear,notype"`
+	Base *solana.AccountMeta `text:"linear,notype"`
+}
+
 type CreateAccountWithSeed struct {
 	// Prefixed with byte 0x03
 	Base     solana.PublicKey
-	SeedSize int `bin:"sizeof=Seed"`
-	Seed     string
+	Seed     BincodeString
 	Lamports bin.Uint64
 	Space    bin.Uint64
 	Owner    solana.PublicKey
+	Accounts *CreateAccountWithSeedAccounts `bin:"-"`
+}
+
+func (i *CreateAccountWithSeed) SetAccounts(accounts []*solana.AccountMeta) error {
+	if len(accounts) < 2 {
+		return fmt.Errorf("insufficient account, CreateAccountWithSeed requires at-least 2 accounts not %d", len(accounts))
+	}
+	i.Accounts = &CreateAccountWithSeedAccounts{
+		From: accounts[0],
+		To:   accounts[1],
+	}
+	// The base account is only listed when it differs from the funding account
+	if len(accounts) >= 3 {
+		i.Accounts.Base = accounts[2]
+	}
+	return nil
+}
+
+// NewCreateAccountWithSeedInstruction creates the account at the address
+// derived from `base`, `seed` and `owner`, which is returned alongside the
+// instruction. Both `from` and `base` must sign the transaction.
+func NewCreateAccountWithSeedInstruction(from, base solana.PublicKey, seed string, lamports, space uint64, owner solana.PublicKey) (*Instruction, solana.PublicKey, error) {
+	to, err := solana.PublicKeyCreateWithSeed(base, seed, owner)
+	if err != nil {
+		return nil, solana.PublicKey{}, err
+	}
+
+	accounts := &CreateAccountWithSeedAccounts{
+		From: &solana.AccountMeta{PublicKey: from, IsSigner: true, IsWritable: true},
+		To:   &solana.AccountMeta{PublicKey: to, IsWritable: true},
+	}
+	if !base.Equals(from) {
+		accounts.Base = &solana.AccountMeta{PublicKey: base, IsSigner: true}
+	}
+
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 3,
+			Impl: &CreateAccountWithSeed{
+				Base:     base,
+				Seed:     BincodeString(seed),
+				Lamports: bin.Uint64(lamports),
+				Space:    bin.Uint64(space),
+				Owner:    owner,
+				Accounts: accounts,
+			},
+		},
+	}, to, nil
+}
+
+type AdvanceNonceAccountAccounts struct {
+	NonceAccount            *solana.AccountMeta `text:"linear,notype"`
+	RecentBlockhashesSysvar *solana.AccountMeta `text:"linear,notype"`
+	NonceAuthority          *solana.AccountMeta `text:"linear,notype"`
 }
 
 type AdvanceNonceAccount struct {
 	// Prefix with 0x04
+	Accounts *AdvanceNonceAccountAccounts `bin:"-"`
+}
+
+func (i *AdvanceNonceAccount) SetAccounts(accounts []*solana.AccountMeta) error {
+	if len(accounts) < 3 {
+		return fmt.Errorf("insufficient account, AdvanceNonceAccount requires at-least 3 accounts not %d", len(accounts))
+	}
+	i.Accounts = &AdvanceNonceAccountAccounts{
+		NonceAccount:            accounts[0],
+		RecentBlockhashesSysvar: accounts[1],
+		NonceAuthority:          accounts[2],
+	}
+	return nil
+}
+
+// NewAdvanceNonceAccountInstruction replaces the stored nonce value, it must be
+// the first instruction of a transaction using the nonce as its blockhash.
+func NewAdvanceNonceAccountInstruction(nonceAccount, nonceAuthority solana.PublicKey) *Instruction {
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 4,
+			Impl: &AdvanceNonceAccount{
+				Accounts: &AdvanceNonceAccountAccounts{
+					NonceAccount:            &solana.AccountMeta{PublicKey: nonceAccount, IsWritable: true},
+					RecentBlockhashesSysvar: &solana.AccountMeta{PublicKey: SYSVAR_RECENT_BLOCKHASHES},
+					NonceAuthority:          &solana.AccountMeta{PublicKey: nonceAuthority, IsSigner: true},
+				},
+			},
+		},
+	}
+}
+
+type WithdrawNonceAccountAccounts struct {
+	NonceAccount            *solana.AccountMeta `text:"linear,notype"`
+	To                      *solana.AccountMeta `text:"linear,notype"`
+	RecentBlockhashesSysvar *solana.AccountMeta `text:"linear,notype"`
+	RentSysvar              *solana.AccountMeta `text:"linear,notype"`
+	NonceAuthority          *solana.AccountMeta `text:"linear,notype"`
 }
 
 type WithdrawNonceAccount struct {
 	// Prefix with 0x05
 	Lamports bin.Uint64
+	Accounts *WithdrawNonceAccountAccounts `bin:"-"`
+}
+
+func (i *WithdrawNonceAccount) SetAccounts(accounts []*solana.AccountMeta) error {
+	if len(accounts) < 5 {
+		return fmt.Errorf("insufficient account, WithdrawNonceAccount requires at-least 5 accounts not %d", len(accounts))
+	}
+	i.Accounts = &WithdrawNonceAccountAccounts{
+		NonceAccount:            accounts[0],
+		To:                      accounts[1],
+		RecentBlockhashesSysvar: accounts[2],
+		RentSysvar:              accounts[3],
+		NonceAuthority:          accounts[4],
+	}
+	return nil
+}
+
+func NewWithdrawNonceAccountInstruction(lamports uint64, nonceAccount, to, nonceAuthority solana.PublicKey) *Instruction {
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 5,
+			Impl: &WithdrawNonceAccount{
+				Lamports: bin.Uint64(lamports),
+				Accounts: &WithdrawNonceAccountAccounts{
+					NonceAccount:            &solana.AccountMeta{PublicKey: nonceAccount, IsWritable: true},
+					To:                      &solana.AccountMeta{PublicKey: to, IsWritable: true},
+					RecentBlockhashesSysvar: &solana.AccountMeta{PublicKey: SYSVAR_RECENT_BLOCKHASHES},
+					RentSysvar:              &solana.AccountMeta{PublicKey: SYSVAR_RENT},
+					NonceAuthority:          &solana.AccountMeta{PublicKey: nonceAuthority, IsSigner: true},
+				},
+			},
+		},
+	}
+}
+
+type InitializeNonceAccountAccounts struct {
+	NonceAccount            *solana.AccountMeta `text:"linear,notype"`
+	RecentBlockhashesSysvar *solana.AccountMeta `text:"linear,notype"`
+	RentSysvar              *solana.AccountMeta `text:"linear,notype"`
 }
 
 type InitializeNonceAccount struct {
 	// Prefix with 0x06
 	AuthorizedAccount solana.PublicKey
+	Accounts          *InitializeNonceAccountAccounts `bin:"-"`
+}
+
+func (i *InitializeNonceAccount) SetAccounts(accounts []*solana.AccountMeta) error {
+	if len(accounts) < 3 {
+		return fmt.Errorf("insufficient account, InitializeNonceAccount requires at-least 3 accounts not %d", len(accounts))
+	}
+	i.Accounts = &InitializeNonceAccountAccounts{
+		NonceAccount:            accounts[0],
+		RecentBlockhashesSysvar: accounts[1],
+		RentSysvar:              accounts[2],
+	}
+	return nil
+}
+
+func NewInitializeNonceAccountInstruction(nonceAccount, nonceAuthority solana.PublicKey) *Instruction {
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 6,
+			Impl: &InitializeNonceAccount{
+				AuthorizedAccount: nonceAuthority,
+				Accounts: &InitializeNonceAccountAccounts{
+					NonceAccount:            &solana.AccountMeta{PublicKey: nonceAccount, IsWritable: true},
+					RecentBlockhashesSysvar: &solana.AccountMeta{PublicKey: SYSVAR_RECENT_BLOCKHASHES},
+					RentSysvar:              &solana.AccountMeta{PublicKey: SYSVAR_RENT},
+				},
+			},
+		},
+	}
+}
+
+// NewCreateNonceAccountInstructions returns the instructions creating and
+// initializing a nonce account funded by `from`. Both `from` and the new
+// nonce account must sign the transaction.
+func NewCreateNonceAccountInstructions(lamports uint64, from, nonceAccount, nonceAuthority solana.PublicKey) []solana.Instruction {
+	return []solana.Instruction{
+		NewCreateAccountInstruction(lamports, NONCE_ACCOUNT_SIZE, PROGRAM_ID, from, nonceAccount),
+		NewInitializeNonceAccountInstruction(nonceAccount, nonceAuthority),
+	}
+}
+
+type AuthorizeNonceAccountAccounts struct {
+	NonceAccount   *solana.AccountMeta `text:"linear,notype"`
+	NonceAuthority *solana.AccountMeta `text:"linear,notype"`
 }
 
 type AuthorizeNonceAccount struct {
 	// Prefix with 0x07
 	AuthorizeAccount solana.PublicKey
+	Accounts         *AuthorizeNonceAccountAccounts `bin:"-"`
+}
+
+func (i *AuthorizeNonceAccount) SetAccounts(accounts []*solana.AccountMeta) error {
+	if len(accounts) < 2 {
+		return fmt.Errorf("insufficient account, AuthorizeNonceAccount requires at-least 2 accounts not %d", len(accounts))
+	}
+	i.Accounts = &AuthorizeNonceAccountAccounts{
+		NonceAccount:   accounts[0],
+		NonceAuthority: accounts[1],
+	}
+	return nil
+}
+
+func NewAuthorizeNonceAccountInstruction(nonceAccount, nonceAuthority, newAuthority solana.PublicKey) *Instruction {
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 7,
+			Impl: &AuthorizeNonceAccount{
+				AuthorizeAccount: newAuthority,
+				Accounts: &AuthorizeNonceAccountAccounts{
+					NonceAccount:   &solana.AccountMeta{PublicKey: nonceAccount, IsWritable: true},
+					NonceAuthority: &solana.AccountMeta{PublicKey: nonceAuthority, IsSigner: true},
+				},
+			},
+		},
+	}
 }
 
 type Allocate struct {
@@ -203,19 +443,130 @@ type Allocate struct {
 	Space bin.Uint64
 }
 
+type AllocateWithSeedAccounts struct {
+	Account *solana.AccountMeta `text:"linear,notype"`
+	Base    *solana.AccountMeta `text:"linear,notype"`
+}
+
 type AllocateWithSeed struct {
 	// Prefixed with byte 0x09
 	Base     solana.PublicKey
-	SeedSize int `bin:"sizeof=Seed"`
-	Seed     string
+	Seed     BincodeString
 	Space    bin.Uint64
 	Owner    solana.PublicKey
+	Accounts *AllocateWithSeedAccounts `bin:"-"`
+}
+
+func (i *AllocateWithSeed) SetAccounts(accounts []*solana.AccountMeta) error {
+	if len(accounts) < 2 {
+		return fmt.Errorf("insufficient account, AllocateWithSeed requires at-least 2 accounts not %d", len(accounts))
+	}
+	i.Accounts = &AllocateWithSeedAccounts{
+		Account: accounts[0],
+		Base:    accounts[1],
+	}
+	return nil
+}
+
+// NewAllocateWithSeedInstruction allocates space for the account derived from
+// `base`, `seed` and `owner`, which is returned alongside the instruction.
+func NewAllocateWithSeedInstruction(base solana.PublicKey, seed string, space uint64, owner solana.PublicKey) (*Instruction, solana.PublicKey, error) {
+	account, err := solana.PublicKeyCreateWithSeed(base, seed, owner)
+	if err != nil {
+		return nil, solana.PublicKey{}, err
+	}
+
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 9,
+			Impl: &AllocateWithSeed{
+				Base:  base,
+				Seed:  BincodeString(seed),
+				Space: bin.Uint64(space),
+				Owner: owner,
+				Accounts: &AllocateWithSeedAccounts{
+					Account: &solana.AccountMeta{PublicKey: account, IsWritable: true},
+					Base:    &solana.AccountMeta{PublicKey: base, IsSigner: true},
+				},
+			},
+		},
+	}, account, nil
+}
+
+type AssignWithSeedAccounts struct {
+	Account *solana.AccountMeta `text:"linear,notype"`
+	Base    *solana.AccountMeta `text:"linear,notype"`
 }
 
 type AssignWithSeed struct {
 	// Prefixed with byte 0x0a
 	Base     solana.PublicKey
-	SeedSize int `bin:"sizeof=Seed"`
-	Seed     string
+	Seed     BincodeString
 	Owner    solana.PublicKey
+	Accounts *AssignWithSeedAccounts `bin:"-"`
+}
+
+func (i *AssignWithSeed) SetAccounts(accounts []*solana.AccountMeta) error {
+	if len(accounts) < 2 {
+		return fmt.Errorf("insufficient account, AssignWithSeed requires at-least 2 accounts not %d", len(accounts))
+	}
+	i.Accounts = &AssignWithSeedAccounts{
+		Account: accounts[0],
+		Base:    accounts[1],
+	}
+	return nil
+}
+
+// NewAssignWithSeedInstruction assigns the account derived from `base`, `seed`
+// and `owner`, which is returned alongside the instruction, to `owner`.
+func NewAssignWithSeedInstruction(base solana.PublicKey, seed string, owner solana.PublicKey) (*Instruction, solana.PublicKey, error) {
+	account, err := solana.PublicKeyCreateWithSeed(base, seed, owner)
+	if err != nil {
+		return nil, solana.PublicKey{}, err
+	}
+
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			TypeID: 10,
+			Impl: &AssignWithSeed{
+				Base:  base,
+				Seed:  BincodeString(seed),
+				Owner: owner,
+				Accounts: &AssignWithSeedAccounts{
+					Account: &solana.AccountMeta{PublicKey: account, IsWritable: true},
+					Base:    &solana.AccountMeta{PublicKey: base, IsSigner: true},
+				},
+			},
+		},
+	}, account, nil
+}
+
+// BincodeString is a string serialized the way Rust's bincode does, with a
+// little endian u64 length prefix instead of a varint one.
+type BincodeString string
+
+func (s BincodeString) MarshalBinary(encoder *bin.Encoder) error {
+	if err := encoder.WriteUint64(uint64(len(s)), binary.LittleEndian); err != nil {
+		return err
+	}
+	return encoder.WriteByteArray([]byte(s), false)
+}
+
+func (s *BincodeString) UnmarshalBinary(decoder *bin.Decoder) error {
+	length, err := decoder.ReadUint64(binary.LittleEndian)
+	if err != nil {
+		return err
+	}
+	if length > uint64(decoder.Remaining()) {
+		return fmt.Errorf("string length %d exceeds remaining %d bytes", length, decoder.Remaining())
+	}
+
+	data := make([]byte, length)
+	for i := range data {
+		if data[i], err = decoder.ReadByte(); err != nil {
+			return err
+		}
+	}
+	*s = BincodeString(data)
+	return nil
 }
@@ -15,9 +15,143 @@
 package system
 
 import (
+	"encoding/hex"
 	"testing"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSystemInstructions(t *testing.T) {
 	t.Skip()
 }
+
+func TestNonceInstructions(t *testing.T) {
+	nonceAccount := solana.MustPublicKeyFromBase58("2kPGkTUzGZDTwxamiC99vggZZ52Dj6TKLNTErXmbNVwt")
+	authority := solana.MustPublicKeyFromBase58("Gg1CWowuNc9ytKuX1p7hZ2mgBmWrjT9eNeXf8gvdq1Kd")
+	other := solana.MustPublicKeyFromBase58("Gr5UanqwiKA54GGnw4b1bB5M8eatQzj6s6FQ9FeTze5C")
+
+	tests := []struct {
+		name             string
+		inst             *Instruction
+		expectedData     string
+		expectedAccounts []*solana.AccountMeta
+	}{
+		{
+			name:         "advance",
+			inst:         NewAdvanceNonceAccountInstruction(nonceAccount, authority),
+			expectedData: "04000000",
+			expectedAccounts: []*solana.AccountMeta{
+				{PublicKey: nonceAccount, IsWritable: true},
+				{PublicKey: SYSVAR_RECENT_BLOCKHASHES},
+				{PublicKey: authority, IsSigner: true},
+			},
+		},
+		{
+			name:         "withdraw",
+			inst:         NewWithdrawNonceAccountInstruction(1000, nonceAccount, other, authority),
+			expectedData: "05000000e803000000000000",
+			expectedAccounts: []*solana.AccountMeta{
+				{PublicKey: nonceAccount, IsWritable: true},
+				{PublicKey: other, IsWritable: true},
+				{PublicKey: SYSVAR_RECENT_BLOCKHASHES},
+				{PublicKey: SYSVAR_RENT},
+				{PublicKey: authority, IsSigner: true},
+			},
+		},
+		{
+			name:         "initialize",
+			inst:         NewInitializeNonceAccountInstruction(nonceAccount, other),
+			expectedData: "06000000" + hex.EncodeToString(other[:]),
+			expectedAccounts: []*solana.AccountMeta{
+				{PublicKey: nonceAccount, IsWritable: true},
+				{PublicKey: SYSVAR_RECENT_BLOCKHASHES},
+				{PublicKey: SYSVAR_RENT},
+			},
+		},
+		{
+			name:         "authorize",
+			inst:         NewAuthorizeNonceAccountInstruction(nonceAccount, authority, other),
+			expectedData: "07000000" + hex.EncodeToString(other[:]),
+			expectedAccounts: []*solana.AccountMeta{
+				{PublicKey: nonceAccount, IsWritable: true},
+				{PublicKey: authority, IsSigner: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := test.inst.Data()
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedData, hex.EncodeToString(data))
+			assert.Equal(t, test.expectedAccounts, test.inst.Accounts())
+
+			decoded, err := DecodeInstruction(test.expectedAccounts, data)
+			require.NoError(t, err)
+			assert.Equal(t, test.inst.TypeID, decoded.TypeID)
+			assert.Equal(t, test.expectedAccounts, decoded.Accounts())
+		})
+	}
+}
+
+func TestWithSeedInstructions(t *testing.T) {
+	from := solana.MustPublicKeyFromBase58("2kPGkTUzGZDTwxamiC99vggZZ52Dj6TKLNTErXmbNVwt")
+	base := solana.MustPublicKeyFromBase58("Gg1CWowuNc9ytKuX1p7hZ2mgBmWrjT9eNeXf8gvdq1Kd")
+	owner := solana.MustPublicKeyFromBase58("Vote111111111111111111111111111111111111111")
+
+	derived, err := solana.PublicKeyCreateWithSeed(base, "stake:0", owner)
+	require.NoError(t, err)
+
+	create, address, err := NewCreateAccountWithSeedInstruction(from, base, "stake:0", 1000, 200, owner)
+	require.NoError(t, err)
+	assert.Equal(t, derived, address)
+	assert.Equal(t, []*solana.AccountMeta{
+		{PublicKey: from, IsSigner: true, IsWritable: true},
+		{PublicKey: derived, IsWritable: true},
+		{PublicKey: base, IsSigner: true},
+	}, create.Accounts())
+
+	data, err := create.Data()
+	require.NoError(t, err)
+	assert.Equal(t,
+		"03000000"+
+			hex.EncodeToString(base[:])+
+			"0700000000000000"+hex.EncodeToString([]byte("stake:0"))+
+			"e803000000000000"+
+			"c800000000000000"+
+			hex.EncodeToString(owner[:]),
+		hex.EncodeToString(data),
+	)
+
+	decoded, err := DecodeInstruction(create.Accounts(), data)
+	require.NoError(t, err)
+	decodedCreate := decoded.Impl.(*CreateAccountWithSeed)
+	assert.Equal(t, BincodeString("stake:0"), decodedCreate.Seed)
+	assert.Equal(t, owner, decodedCreate.Owner)
+	assert.Equal(t, create.Accounts(), decoded.Accounts())
+
+	selfFunded, _, err := NewCreateAccountWithSeedInstruction(base, base, "stake:0", 1000, 200, owner)
+	require.NoError(t, err)
+	assert.Len(t, selfFunded.Accounts(), 2)
+
+	allocate, address, err := NewAllocateWithSeedInstruction(base, "stake:0", 200, owner)
+	require.NoError(t, err)
+	assert.Equal(t, derived, address)
+	data, err = allocate.Data()
+	require.NoError(t, err)
+	decoded, err = DecodeInstruction(allocate.Accounts(), data)
+	require.NoError(t, err)
+	assert.Equal(t, allocate.Impl, decoded.Impl)
+
+	assign, address, err := NewAssignWithSeedInstruction(base, "stake:0", owner)
+	require.NoError(t, err)
+	assert.Equal(t, derived, address)
+	data, err = assign.Data()
+	require.NoError(t, err)
+	assert.Equal(t, "0a000000", hex.EncodeToString(data[:4]))
+	decoded, err = DecodeInstruction(assign.Accounts(), data)
+	require.NoError(t, err)
+	assert.Equal(t, assign.Impl, decoded.Impl)
+}
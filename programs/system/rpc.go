@@ -0,0 +1,46 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/streamingfast/solana-go/rpc"
+)
+
+// FetchNonceAccount fetches the durable nonce account `nonceAddr`, failing if
+// it's not an initialized nonce account owned by the system program.
+func FetchNonceAccount(ctx context.Context, rpcCli *rpc.Client, nonceAddr solana.PublicKey) (*NonceAccount, error) {
+	resp, err := rpcCli.GetAccountInfo(ctx, nonceAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.Value.Owner.Equals(PROGRAM_ID) {
+		return nil, fmt.Errorf("account %q is not owned by the system program, owner is %q", nonceAddr, resp.Value.Owner)
+	}
+
+	n := &NonceAccount{}
+	if err := n.Decode(resp.Value.Data); err != nil {
+		return nil, fmt.Errorf("unable to decode nonce account %q: %w", nonceAddr.String(), err)
+	}
+
+	if !n.IsInitialized() {
+		return nil, fmt.Errorf("nonce account %q is not initialized", nonceAddr.String())
+	}
+	return n, nil
+}
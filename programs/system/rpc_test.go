@@ -0,0 +1,72 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/streamingfast/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchNonceAccount(t *testing.T) {
+	nonceAddr := solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	nonce := solana.MustPublicKeyFromBase58("Gr5UanqwiKA54GGnw4b1bB5M8eatQzj6s6FQ9FeTze5C")
+
+	data := append([]byte{1, 0, 0, 0, 1, 0, 0, 0}, make([]byte, 32)...)
+	data = append(data, nonce[:]...)
+	data = append(data, make([]byte, 8)...)
+
+	tests := []struct {
+		name        string
+		owner       solana.PublicKey
+		expectedErr string
+	}{
+		{
+			name:  "system owned",
+			owner: PROGRAM_ID,
+		},
+		{
+			name:        "other owner",
+			owner:       solana.MustPublicKeyFromBase58("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"),
+			expectedErr: `account "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin" is not owned by the system program, owner is "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				fmt.Fprintf(rw, `{"jsonrpc":"2.0","id":0,"result":{"context":{"slot":1},"value":{"data":["%s","base64"],"executable":false,"lamports":1447680,"owner":"%s","rentEpoch":0}}}`, base64.StdEncoding.EncodeToString(data), test.owner)
+			}))
+			defer server.Close()
+
+			account, err := FetchNonceAccount(context.Background(), rpc.NewClient(server.URL), nonceAddr)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, nonce, account.Nonce)
+		})
+	}
+}
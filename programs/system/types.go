@@ -0,0 +1,75 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"fmt"
+
+	bin "github.com/streamingfast/binary"
+	"github.com/streamingfast/solana-go"
+)
+
+const NONCE_ACCOUNT_SIZE = 80
+
+func init() {
+	solana.RegisterAccountDecoder(PROGRAM_ID, solana.MatchDataSize(NONCE_ACCOUNT_SIZE), func(key solana.PublicKey, data []byte) (interface{}, error) {
+		nonce := &NonceAccount{}
+		return nonce, nonce.Decode(data)
+	})
+}
+
+type NonceVersion uint32
+
+const (
+	NonceVersionLegacy NonceVersion = iota
+	NonceVersionCurrent
+)
+
+type NonceState uint32
+
+const (
+	NonceStateUninitialized NonceState = iota
+	NonceStateInitialized
+)
+
+type FeeCalculator struct {
+	LamportsPerSignature bin.Uint64
+}
+
+type NonceAccount struct {
+	Version   NonceVersion
+	State     NonceState
+	Authority solana.PublicKey
+	// Nonce is the durable nonce, used in place of a recent blockhash
+	Nonce         solana.PublicKey
+	FeeCalculator FeeCalculator
+}
+
+func (n *NonceAccount) Decode(in []byte) error {
+	if len(in) < NONCE_ACCOUNT_SIZE {
+		return fmt.Errorf("unpack: expected %d bytes, got %d", NONCE_ACCOUNT_SIZE, len(in))
+	}
+
+	decoder := bin.NewDecoder(in)
+	err := decoder.Decode(&n)
+	if err != nil {
+		return fmt.Errorf("unpack: %w", err)
+	}
+	return nil
+}
+
+func (n *NonceAccount) IsInitialized() bool {
+	return n.State == NonceStateInitialized
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonceAccount_Decode(t *testing.T) {
+	authority := solana.MustPublicKeyFromBase58("Gg1CWowuNc9ytKuX1p7hZ2mgBmWrjT9eNeXf8gvdq1Kd")
+	nonce := solana.MustPublicKeyFromBase58("Gr5UanqwiKA54GGnw4b1bB5M8eatQzj6s6FQ9FeTze5C")
+
+	data, err := hex.DecodeString(
+		"01000000" + // version
+			"01000000" + // state
+			hex.EncodeToString(authority[:]) +
+			hex.EncodeToString(nonce[:]) +
+			"8813000000000000", // lamports per signature
+	)
+	require.NoError(t, err)
+
+	account := &NonceAccount{}
+	require.NoError(t, account.Decode(data))
+
+	assert.Equal(t, NonceVersionCurrent, account.Version)
+	assert.True(t, account.IsInitialized())
+	assert.Equal(t, authority, account.Authority)
+	assert.Equal(t, nonce, account.Nonce)
+	assert.Equal(t, uint64(5000), uint64(account.FeeCalculator.LamportsPerSignature))
+}
+
+func TestNonceAccount_Decode_Uninitialized(t *testing.T) {
+	account := &NonceAccount{}
+	require.NoError(t, account.Decode(make([]byte, NONCE_ACCOUNT_SIZE)))
+	assert.False(t, account.IsInitialized())
+
+	assert.Error(t, account.Decode(make([]byte, 10)))
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysvar
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/streamingfast/solana-go"
+)
+
+// Instruction is an instruction of the executing transaction, as exposed by
+// the Instructions sysvar.
+type Instruction struct {
+	ProgramID solana.PublicKey
+	Accounts  []*solana.AccountMeta
+	Data      []byte
+}
+
+// Instructions is the content of the Instructions sysvar, which programs use
+// to introspect the transaction being executed. The account only exists while
+// a transaction executes, it can't be fetched.
+type Instructions struct {
+	Instructions []*Instruction
+	// CurrentIndex is the index of the instruction being executed
+	CurrentIndex uint16
+}
+
+const (
+	instructionAccountIsSigner   = 1 << 0
+	instructionAccountIsWritable = 1 << 1
+)
+
+// Decode decodes the Instructions sysvar, a u16 instruction count followed by
+// the u16 offset of each instruction, the instructions and, in its last two
+// bytes, the index of the instruction being executed.
+func (i *Instructions) Decode(in []byte) error {
+	if len(in) < 4 {
+		return fmt.Errorf("unpack instructions: expected at least 4 bytes, got %d", len(in))
+	}
+
+	count := int(binary.LittleEndian.Uint16(in))
+	if 2+count*2 > len(in)-2 {
+		return fmt.Errorf("unpack instructions: %d instruction offsets exceed the %d bytes available", count, len(in))
+	}
+
+	i.Instructions = make([]*Instruction, count)
+	for idx := range i.Instructions {
+		instruction, err := LoadInstructionAt(in, idx)
+		if err != nil {
+			return fmt.Errorf("unpack instructions: %w", err)
+		}
+		i.Instructions[idx] = instruction
+	}
+
+	i.CurrentIndex = binary.LittleEndian.Uint16(in[len(in)-2:])
+	return nil
+}
+
+// Current returns the instruction being executed.
+func (i *Instructions) Current() (*Instruction, error) {
+	if int(i.CurrentIndex) >= len(i.Instructions) {
+		return nil, fmt.Errorf("current instruction index %d out of range, transaction has %d instructions", i.CurrentIndex, len(i.Instructions))
+	}
+	return i.Instructions[i.CurrentIndex], nil
+}
+
+// LoadInstructionAt decodes only the instruction at `index` of the
+// Instructions sysvar data.
+func LoadInstructionAt(in []byte, index int) (*Instruction, error) {
+	if len(in) < 2 {
+		return nil, fmt.Errorf("instruction %d: expected at least 2 bytes, got %d", index, len(in))
+	}
+
+	count := int(binary.LittleEndian.Uint16(in))
+	if index < 0 || index >= count {
+		return nil, fmt.Errorf("instruction %d: out of range, transaction has %d instructions", index, count)
+	}
+
+	offsetPosition := 2 + index*2
+	if offsetPosition+2 > len(in) {
+		return nil, fmt.Errorf("instruction %d: offset out of bounds", index)
+	}
+
+	reader := &instructionReader{data: in, position: int(binary.LittleEndian.Uint16(in[offsetPosition:]))}
+
+	accountCount, err := reader.readUint16()
+	if err != nil {
+		return nil, fmt.Errorf("instruction %d: accounts count: %w", index, err)
+	}
+
+	out := &Instruction{Accounts: make([]*solana.AccountMeta, accountCount)}
+	for accountIdx := range out.Accounts {
+		flags, err := reader.read(1)
+		if err != nil {
+			return nil, fmt.Errorf("instruction %d: account %d: %w", index, accountIdx, err)
+		}
+		key, err := reader.read(32)
+		if err != nil {
+			return nil, fmt.Errorf("instruction %d: account %d: %w", index, accountIdx, err)
+		}
+
+		out.Accounts[accountIdx] = &solana.AccountMeta{
+			PublicKey:  solana.PublicKeyFromBytes(key),
+			IsSigner:   flags[0]&instructionAccountIsSigner != 0,
+			IsWritable: flags[0]&instructionAccountIsWritable != 0,
+		}
+	}
+
+	programID, err := reader.read(32)
+	if err != nil {
+		return nil, fmt.Errorf("instruction %d: program ID: %w", index, err)
+	}
+	out.ProgramID = solana.PublicKeyFromBytes(programID)
+
+	dataLength, err := reader.readUint16()
+	if err != nil {
+		return nil, fmt.Errorf("instruction %d: data length: %w", index, err)
+	}
+	data, err := reader.read(int(dataLength))
+	if err != nil {
+		return nil, fmt.Errorf("instruction %d: data: %w", index, err)
+	}
+	out.Data = append([]byte{}, data...)
+
+	return out, nil
+}
+
+type instructionReader struct {
+	data     []byte
+	position int
+}
+
+func (r *instructionReader) read(count int) ([]byte, error) {
+	if r.position+count > len(r.data) {
+		return nil, fmt.Errorf("expected %d bytes at offset %d, only %d available", count, r.position, len(r.data)-r.position)
+	}
+
+	out := r.data[r.position : r.position+count]
+	r.position += count
+	return out, nil
+}
+
+func (r *instructionReader) readUint16() (uint16, error) {
+	data, err := r.read(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(data), nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysvar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/streamingfast/solana-go/rpc"
+)
+
+func FetchClock(ctx context.Context, rpcCli *rpc.Client) (*Clock, error) {
+	out := &Clock{}
+	if err := fetch(ctx, rpcCli, SYSVAR_CLOCK, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func FetchRent(ctx context.Context, rpcCli *rpc.Client) (*Rent, error) {
+	out := &Rent{}
+	if err := fetch(ctx, rpcCli, SYSVAR_RENT, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func FetchEpochSchedule(ctx context.Context, rpcCli *rpc.Client) (*EpochSchedule, error) {
+	out := &EpochSchedule{}
+	if err := fetch(ctx, rpcCli, SYSVAR_EPOCH_SCHEDULE, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func FetchEpochRewards(ctx context.Context, rpcCli *rpc.Client) (*EpochRewards, error) {
+	out := &EpochRewards{}
+	if err := fetch(ctx, rpcCli, SYSVAR_EPOCH_REWARDS, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func FetchFees(ctx context.Context, rpcCli *rpc.Client) (*Fees, error) {
+	out := &Fees{}
+	if err := fetch(ctx, rpcCli, SYSVAR_FEES, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func FetchRecentBlockhashes(ctx context.Context, rpcCli *rpc.Client) (RecentBlockhashes, error) {
+	var out RecentBlockhashes
+	if err := fetch(ctx, rpcCli, SYSVAR_RECENT_BLOCKHASHES, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func FetchSlotHashes(ctx context.Context, rpcCli *rpc.Client) (SlotHashes, error) {
+	var out SlotHashes
+	if err := fetch(ctx, rpcCli, SYSVAR_SLOT_HASHES, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func FetchSlotHistory(ctx context.Context, rpcCli *rpc.Client) (*SlotHistory, error) {
+	out := &SlotHistory{}
+	if err := fetch(ctx, rpcCli, SYSVAR_SLOT_HISTORY, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func FetchStakeHistory(ctx context.Context, rpcCli *rpc.Client) (StakeHistory, error) {
+	var out StakeHistory
+	if err := fetch(ctx, rpcCli, SYSVAR_STAKE_HISTORY, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func fetch(ctx context.Context, rpcCli *rpc.Client, address solana.PublicKey, out decodable) error {
+	resp, err := rpcCli.GetAccountInfo(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	if err := out.Decode(resp.Value.Data); err != nil {
+		return fmt.Errorf("unable to decode sysvar %q: %w", address.String(), err)
+	}
+	return nil
+}
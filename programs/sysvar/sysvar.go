@@ -0,0 +1,61 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sysvar decodes the sysvar accounts, through which the runtime
+// exposes the cluster state to programs.
+package sysvar
+
+import (
+	"github.com/streamingfast/solana-go"
+)
+
+// PROGRAM_ID is the owner of all sysvar accounts.
+var PROGRAM_ID = solana.MustPublicKeyFromBase58("Sysvar1111111111111111111111111111111111111")
+
+var (
+	SYSVAR_CLOCK              = solana.MustPublicKeyFromBase58("SysvarC1ock11111111111111111111111111111111")
+	SYSVAR_EPOCH_REWARDS      = solana.MustPublicKeyFromBase58("SysvarEpochRewards1111111111111111111111111")
+	SYSVAR_EPOCH_SCHEDULE     = solana.MustPublicKeyFromBase58("SysvarEpochSchedu1e111111111111111111111111")
+	SYSVAR_FEES               = solana.MustPublicKeyFromBase58("SysvarFees111111111111111111111111111111111")
+	SYSVAR_INSTRUCTIONS       = solana.MustPublicKeyFromBase58("Sysvar1nstructions1111111111111111111111111")
+	SYSVAR_RECENT_BLOCKHASHES = solana.MustPublicKeyFromBase58("SysvarRecentB1ockHashes11111111111111111111")
+	SYSVAR_RENT               = solana.MustPublicKeyFromBase58("SysvarRent111111111111111111111111111111111")
+	SYSVAR_SLOT_HASHES        = solana.MustPublicKeyFromBase58("SysvarS1otHashes111111111111111111111111111")
+	SYSVAR_SLOT_HISTORY       = solana.MustPublicKeyFromBase58("SysvarS1otHistory11111111111111111111111111")
+	SYSVAR_STAKE_HISTORY      = solana.MustPublicKeyFromBase58("SysvarStakeHistory1111111111111111111111111")
+)
+
+// decodable is implemented by every sysvar account type.
+type decodable interface {
+	Decode(in []byte) error
+}
+
+func init() {
+	register := func(size int, newAccount func() decodable) {
+		solana.RegisterAccountDecoder(PROGRAM_ID, solana.MatchDataSize(size), func(key solana.PublicKey, data []byte) (interface{}, error) {
+			account := newAccount()
+			return account, account.Decode(data)
+		})
+	}
+
+	register(CLOCK_SIZE, func() decodable { return &Clock{} })
+	register(EPOCH_REWARDS_SIZE, func() decodable { return &EpochRewards{} })
+	register(EPOCH_SCHEDULE_SIZE, func() decodable { return &EpochSchedule{} })
+	register(FEES_SIZE, func() decodable { return &Fees{} })
+	register(RECENT_BLOCKHASHES_SIZE, func() decodable { return &RecentBlockhashes{} })
+	register(RENT_SIZE, func() decodable { return &Rent{} })
+	register(SLOT_HASHES_SIZE, func() decodable { return &SlotHashes{} })
+	register(SLOT_HISTORY_SIZE, func() decodable { return &SlotHistory{} })
+	register(STAKE_HISTORY_SIZE, func() decodable { return &StakeHistory{} })
+}
@@ -0,0 +1,370 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysvar
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	bin "github.com/streamingfast/binary"
+	"github.com/streamingfast/solana-go"
+)
+
+const (
+	CLOCK_SIZE          = 40
+	EPOCH_REWARDS_SIZE  = 81
+	EPOCH_SCHEDULE_SIZE = 33
+	FEES_SIZE           = 8
+	RENT_SIZE           = 17
+
+	// Sysvars holding lists are allocated for their maximum number of entries,
+	// preceded by the u64 length of the list.
+	RECENT_BLOCKHASHES_SIZE = 8 + MAX_RECENT_BLOCKHASHES*40
+	SLOT_HASHES_SIZE        = 8 + MAX_SLOT_HASHES*40
+	STAKE_HISTORY_SIZE      = 8 + MAX_STAKE_HISTORY*32
+
+	// SLOT_HISTORY_SIZE is the size of the bit vector, an option tag, the
+	// block count, the blocks and the bit count, followed by the next slot.
+	SLOT_HISTORY_SIZE = 1 + 8 + MAX_SLOT_HISTORY/8 + 8 + 8
+)
+
+const (
+	MAX_RECENT_BLOCKHASHES = 150
+	MAX_SLOT_HASHES        = 512
+	MAX_STAKE_HISTORY      = 512
+	MAX_SLOT_HISTORY       = 1024 * 1024
+)
+
+type Clock struct {
+	Slot uint64
+	// EpochStartTimestamp is the unix timestamp of the first slot of the epoch
+	EpochStartTimestamp int64
+	Epoch               uint64
+	// LeaderScheduleEpoch is the most recent epoch for which the leader schedule
+	// has been generated
+	LeaderScheduleEpoch uint64
+	UnixTimestamp       int64
+}
+
+func (c *Clock) Decode(in []byte) error {
+	return decodeFixed(c, "clock", in, CLOCK_SIZE)
+}
+
+// ACCOUNT_STORAGE_OVERHEAD is the number of bytes accounted for, in addition
+// to its data, when computing the rent of an account.
+const ACCOUNT_STORAGE_OVERHEAD = 128
+
+type Rent struct {
+	LamportsPerByteYear uint64
+	// ExemptionThreshold is the number of years of rent an account must hold to
+	// be rent exempt
+	ExemptionThreshold float64
+	BurnPercent        uint8
+}
+
+func (r *Rent) Decode(in []byte) error {
+	return decodeFixed(r, "rent", in, RENT_SIZE)
+}
+
+// MinimumBalance returns the lamports an account holding `dataSize` bytes
+// must have to be rent exempt.
+func (r *Rent) MinimumBalance(dataSize uint64) uint64 {
+	return uint64(float64((ACCOUNT_STORAGE_OVERHEAD+dataSize)*r.LamportsPerByteYear) * r.ExemptionThreshold)
+}
+
+type EpochSchedule struct {
+	SlotsPerEpoch            uint64
+	LeaderScheduleSlotOffset uint64
+	// Warmup is true when the first epochs are shorter, doubling in length
+	// until reaching SlotsPerEpoch
+	Warmup           bool
+	FirstNormalEpoch uint64
+	FirstNormalSlot  uint64
+}
+
+func (e *EpochSchedule) Decode(in []byte) error {
+	return decodeFixed(e, "epoch schedule", in, EPOCH_SCHEDULE_SIZE)
+}
+
+// MINIMUM_SLOTS_PER_EPOCH is the length of the first epoch when the schedule
+// warms up.
+const MINIMUM_SLOTS_PER_EPOCH = 32
+
+// GetEpochAndSlotIndex returns the epoch of `slot` and its index in the epoch.
+func (e *EpochSchedule) GetEpochAndSlotIndex(slot uint64) (epoch uint64, slotIndex uint64) {
+	if slot < e.FirstNormalSlot {
+		// Warmup epochs are 32, 64, 128, ... slots long
+		epoch = uint64(bitLength(slot+MINIMUM_SLOTS_PER_EPOCH)) - uint64(bitLength(MINIMUM_SLOTS_PER_EPOCH))
+		epochLength := uint64(MINIMUM_SLOTS_PER_EPOCH) << epoch
+		return epoch, slot - (epochLength - MINIMUM_SLOTS_PER_EPOCH)
+	}
+
+	normalSlotIndex := slot - e.FirstNormalSlot
+	return e.FirstNormalEpoch + normalSlotIndex/e.SlotsPerEpoch, normalSlotIndex % e.SlotsPerEpoch
+}
+
+// GetFirstSlotInEpoch returns the first slot of `epoch`.
+func (e *EpochSchedule) GetFirstSlotInEpoch(epoch uint64) uint64 {
+	if epoch <= e.FirstNormalEpoch {
+		return (uint64(1)<<epoch - 1) * MINIMUM_SLOTS_PER_EPOCH
+	}
+	return (epoch-e.FirstNormalEpoch)*e.SlotsPerEpoch + e.FirstNormalSlot
+}
+
+func bitLength(v uint64) int {
+	out := 0
+	for ; v != 0; v >>= 1 {
+		out++
+	}
+	return out
+}
+
+// EpochRewards tracks the partitioned distribution of the staking rewards at
+// the start of an epoch.
+type EpochRewards struct {
+	DistributionStartingBlockHeight uint64
+	NumPartitions                   uint64
+	ParentBlockhash                 solana.PublicKey
+	TotalPoints                     bin.Uint128
+	TotalRewards                    uint64
+	DistributedRewards              uint64
+	// Active is true while rewards are being distributed
+	Active bool
+}
+
+func (e *EpochRewards) Decode(in []byte) error {
+	return decodeFixed(e, "epoch rewards", in, EPOCH_REWARDS_SIZE)
+}
+
+type FeeCalculator struct {
+	LamportsPerSignature uint64
+}
+
+// Fees is deprecated on chain, fees are returned by the `getFeeForMessage` RPC
+// method instead.
+type Fees struct {
+	FeeCalculator FeeCalculator
+}
+
+func (f *Fees) Decode(in []byte) error {
+	return decodeFixed(f, "fees", in, FEES_SIZE)
+}
+
+type RecentBlockhash struct {
+	Blockhash     solana.PublicKey
+	FeeCalculator FeeCalculator
+}
+
+// RecentBlockhashes is deprecated on chain, the most recent blockhash being
+// first.
+type RecentBlockhashes []RecentBlockhash
+
+func (r *RecentBlockhashes) Decode(in []byte) error {
+	decoder := bin.NewDecoder(in)
+	count, err := readLength(decoder, 40)
+	if err != nil {
+		return fmt.Errorf("unpack recent blockhashes: %w", err)
+	}
+
+	out := make(RecentBlockhashes, count)
+	for idx := range out {
+		if err := decoder.Decode(&out[idx]); err != nil {
+			return fmt.Errorf("unpack recent blockhashes: entry %d: %w", idx, err)
+		}
+	}
+	*r = out
+	return nil
+}
+
+type SlotHash struct {
+	Slot uint64
+	Hash solana.PublicKey
+}
+
+// SlotHashes holds the hashes of the most recent slots, the most recent
+// being first.
+type SlotHashes []SlotHash
+
+func (s *SlotHashes) Decode(in []byte) error {
+	decoder := bin.NewDecoder(in)
+	count, err := readLength(decoder, 40)
+	if err != nil {
+		return fmt.Errorf("unpack slot hashes: %w", err)
+	}
+
+	out := make(SlotHashes, count)
+	for idx := range out {
+		if err := decoder.Decode(&out[idx]); err != nil {
+			return fmt.Errorf("unpack slot hashes: entry %d: %w", idx, err)
+		}
+	}
+	*s = out
+	return nil
+}
+
+// Get returns the hash of `slot`, if still part of the sysvar.
+func (s SlotHashes) Get(slot uint64) (solana.PublicKey, bool) {
+	for _, entry := range s {
+		if entry.Slot == slot {
+			return entry.Hash, true
+		}
+	}
+	return solana.PublicKey{}, false
+}
+
+type StakeHistoryEntry struct {
+	Epoch        uint64
+	Effective    uint64
+	Activating   uint64
+	Deactivating uint64
+}
+
+// StakeHistory holds the cluster wide stake activation of the most recent
+// epochs, the most recent being first.
+type StakeHistory []StakeHistoryEntry
+
+func (s *StakeHistory) Decode(in []byte) error {
+	decoder := bin.NewDecoder(in)
+	count, err := readLength(decoder, 32)
+	if err != nil {
+		return fmt.Errorf("unpack stake history: %w", err)
+	}
+
+	out := make(StakeHistory, count)
+	for idx := range out {
+		if err := decoder.Decode(&out[idx]); err != nil {
+			return fmt.Errorf("unpack stake history: entry %d: %w", idx, err)
+		}
+	}
+	*s = out
+	return nil
+}
+
+// Get returns the stake history of `epoch`, if still part of the sysvar.
+func (s StakeHistory) Get(epoch uint64) (StakeHistoryEntry, bool) {
+	for _, entry := range s {
+		if entry.Epoch == epoch {
+			return entry, true
+		}
+	}
+	return StakeHistoryEntry{}, false
+}
+
+type SlotHistoryCheck uint8
+
+const (
+	SlotHistoryCheckFuture SlotHistoryCheck = iota
+	SlotHistoryCheckTooOld
+	SlotHistoryCheckFound
+	SlotHistoryCheckNotFound
+)
+
+func (c SlotHistoryCheck) String() string {
+	switch c {
+	case SlotHistoryCheckFuture:
+		return "future"
+	case SlotHistoryCheckTooOld:
+		return "too old"
+	case SlotHistoryCheckFound:
+		return "found"
+	case SlotHistoryCheckNotFound:
+		return "not found"
+	}
+	return fmt.Sprintf("unknown (%d)", uint8(c))
+}
+
+// SlotHistory records which of the last MAX_SLOT_HISTORY slots were produced,
+// as a bit vector indexed by `slot % MAX_SLOT_HISTORY`.
+type SlotHistory struct {
+	Bits     []uint64
+	BitCount uint64
+	NextSlot uint64
+}
+
+func (s *SlotHistory) Decode(in []byte) error {
+	decoder := bin.NewDecoder(in)
+
+	present, err := decoder.ReadBool()
+	if err != nil {
+		return fmt.Errorf("unpack slot history: %w", err)
+	}
+
+	s.Bits = nil
+	if present {
+		count, err := readLength(decoder, 8)
+		if err != nil {
+			return fmt.Errorf("unpack slot history: %w", err)
+		}
+
+		s.Bits = make([]uint64, count)
+		for idx := range s.Bits {
+			if s.Bits[idx], err = decoder.ReadUint64(binary.LittleEndian); err != nil {
+				return fmt.Errorf("unpack slot history: block %d: %w", idx, err)
+			}
+		}
+	}
+
+	if s.BitCount, err = decoder.ReadUint64(binary.LittleEndian); err != nil {
+		return fmt.Errorf("unpack slot history: bit count: %w", err)
+	}
+	if s.NextSlot, err = decoder.ReadUint64(binary.LittleEndian); err != nil {
+		return fmt.Errorf("unpack slot history: next slot: %w", err)
+	}
+	return nil
+}
+
+// Check reports whether `slot` was produced, as far as the history knows.
+func (s *SlotHistory) Check(slot uint64) SlotHistoryCheck {
+	if slot >= s.NextSlot {
+		return SlotHistoryCheckFuture
+	}
+	if s.NextSlot > MAX_SLOT_HISTORY && slot < s.NextSlot-MAX_SLOT_HISTORY {
+		return SlotHistoryCheckTooOld
+	}
+
+	bit := slot % MAX_SLOT_HISTORY
+	if bit >= s.BitCount || bit/64 >= uint64(len(s.Bits)) {
+		return SlotHistoryCheckNotFound
+	}
+	if s.Bits[bit/64]&(1<<(bit%64)) != 0 {
+		return SlotHistoryCheckFound
+	}
+	return SlotHistoryCheckNotFound
+}
+
+func decodeFixed(v interface{}, name string, in []byte, size int) error {
+	if len(in) < size {
+		return fmt.Errorf("unpack %s: expected %d bytes, got %d", name, size, len(in))
+	}
+
+	if err := bin.NewDecoder(in).Decode(v); err != nil {
+		return fmt.Errorf("unpack %s: %w", name, err)
+	}
+	return nil
+}
+
+// readLength reads the u64 length prefix of a list, checking that the data
+// holds that many entries of `entrySize` bytes.
+func readLength(decoder *bin.Decoder, entrySize int) (int, error) {
+	length, err := decoder.ReadUint64(binary.LittleEndian)
+	if err != nil {
+		return 0, fmt.Errorf("length: %w", err)
+	}
+
+	if length > uint64(decoder.Remaining()/entrySize) {
+		return 0, fmt.Errorf("length %d exceeds the %d bytes available", length, decoder.Remaining())
+	}
+	return int(length), nil
+}
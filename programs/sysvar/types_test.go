@@ -0,0 +1,210 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysvar
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func u64(v uint64) []byte {
+	out := make([]byte, 8)
+	binary.LittleEndian.PutUint64(out, v)
+	return out
+}
+
+func u16(v uint16) []byte {
+	out := make([]byte, 2)
+	binary.LittleEndian.PutUint16(out, v)
+	return out
+}
+
+func concat(parts ...[]byte) (out []byte) {
+	for _, part := range parts {
+		out = append(out, part...)
+	}
+	return out
+}
+
+func TestClock_Decode(t *testing.T) {
+	data := concat(u64(150_000_000), u64(1_650_000_000), u64(347), u64(348), u64(1_650_100_000))
+
+	decoded, err := solana.DecodeAccount(SYSVAR_CLOCK, PROGRAM_ID, data)
+	require.NoError(t, err)
+	assert.Equal(t, &Clock{
+		Slot:                150_000_000,
+		EpochStartTimestamp: 1_650_000_000,
+		Epoch:               347,
+		LeaderScheduleEpoch: 348,
+		UnixTimestamp:       1_650_100_000,
+	}, decoded)
+
+	assert.EqualError(t, (&Clock{}).Decode(data[:10]), "unpack clock: expected 40 bytes, got 10")
+}
+
+func TestRent_Decode(t *testing.T) {
+	data := concat(u64(3480), u64(math.Float64bits(2.0)), []byte{50})
+
+	rent := &Rent{}
+	require.NoError(t, rent.Decode(data))
+	assert.Equal(t, &Rent{LamportsPerByteYear: 3480, ExemptionThreshold: 2.0, BurnPercent: 50}, rent)
+
+	assert.Equal(t, uint64(890880), rent.MinimumBalance(0))
+	assert.Equal(t, uint64(2039280), rent.MinimumBalance(165))
+}
+
+func TestEpochSchedule(t *testing.T) {
+	data := concat(u64(8192), u64(8192), []byte{1}, u64(8), u64(8160))
+
+	schedule := &EpochSchedule{}
+	require.NoError(t, schedule.Decode(data))
+	assert.Equal(t, &EpochSchedule{SlotsPerEpoch: 8192, LeaderScheduleSlotOffset: 8192, Warmup: true, FirstNormalEpoch: 8, FirstNormalSlot: 8160}, schedule)
+
+	tests := []struct {
+		slot          uint64
+		expectedEpoch uint64
+		expectedIndex uint64
+	}{
+		{0, 0, 0},
+		{31, 0, 31},
+		{32, 1, 0},
+		{95, 1, 63},
+		{96, 2, 0},
+		{8159, 7, 4095},
+		{8160, 8, 0},
+		{8160 + 8192*3 + 5, 11, 5},
+	}
+
+	for _, test := range tests {
+		epoch, index := schedule.GetEpochAndSlotIndex(test.slot)
+		assert.Equal(t, test.expectedEpoch, epoch, "slot %d", test.slot)
+		assert.Equal(t, test.expectedIndex, index, "slot %d", test.slot)
+		assert.Equal(t, test.slot-test.expectedIndex, schedule.GetFirstSlotInEpoch(test.expectedEpoch), "slot %d", test.slot)
+	}
+}
+
+func TestEpochRewards_Decode(t *testing.T) {
+	hash := solana.MustPublicKeyFromBase58("SysvarC1ock11111111111111111111111111111111")
+	data := concat(u64(100), u64(4), hash[:], u64(7), u64(0), u64(1000), u64(250), []byte{1})
+	require.Len(t, data, EPOCH_REWARDS_SIZE)
+
+	rewards := &EpochRewards{}
+	require.NoError(t, rewards.Decode(data))
+	assert.Equal(t, uint64(100), rewards.DistributionStartingBlockHeight)
+	assert.Equal(t, hash, rewards.ParentBlockhash)
+	assert.Equal(t, "7", rewards.TotalPoints.BigInt().String())
+	assert.Equal(t, uint64(1000), rewards.TotalRewards)
+	assert.Equal(t, uint64(250), rewards.DistributedRewards)
+	assert.True(t, rewards.Active)
+}
+
+func TestSlotHashes_Decode(t *testing.T) {
+	hash1 := solana.MustPublicKeyFromBase58("SysvarC1ock11111111111111111111111111111111")
+	hash2 := solana.MustPublicKeyFromBase58("SysvarRent111111111111111111111111111111111")
+
+	data := make([]byte, SLOT_HASHES_SIZE)
+	copy(data, concat(u64(2), u64(11), hash1[:], u64(10), hash2[:]))
+
+	decoded, err := solana.DecodeAccount(SYSVAR_SLOT_HASHES, PROGRAM_ID, data)
+	require.NoError(t, err)
+
+	slotHashes := *decoded.(*SlotHashes)
+	assert.Equal(t, SlotHashes{{Slot: 11, Hash: hash1}, {Slot: 10, Hash: hash2}}, slotHashes)
+
+	hash, found := slotHashes.Get(10)
+	assert.True(t, found)
+	assert.Equal(t, hash2, hash)
+
+	_, found = slotHashes.Get(9)
+	assert.False(t, found)
+
+	assert.EqualError(t, (&SlotHashes{}).Decode(u64(2)), "unpack slot hashes: length 2 exceeds the 0 bytes available")
+}
+
+func TestStakeHistory_Decode(t *testing.T) {
+	data := make([]byte, STAKE_HISTORY_SIZE)
+	copy(data, concat(u64(1), u64(300), u64(1000), u64(20), u64(30)))
+
+	decoded, err := solana.DecodeAccount(SYSVAR_STAKE_HISTORY, PROGRAM_ID, data)
+	require.NoError(t, err)
+
+	entry, found := (*decoded.(*StakeHistory)).Get(300)
+	assert.True(t, found)
+	assert.Equal(t, StakeHistoryEntry{Epoch: 300, Effective: 1000, Activating: 20, Deactivating: 30}, entry)
+}
+
+func TestRecentBlockhashes_Decode(t *testing.T) {
+	hash := solana.MustPublicKeyFromBase58("SysvarC1ock11111111111111111111111111111111")
+
+	var blockhashes RecentBlockhashes
+	require.NoError(t, blockhashes.Decode(concat(u64(1), hash[:], u64(5000))))
+	assert.Equal(t, RecentBlockhashes{{Blockhash: hash, FeeCalculator: FeeCalculator{LamportsPerSignature: 5000}}}, blockhashes)
+}
+
+func TestSlotHistory(t *testing.T) {
+	blocks := make([]byte, MAX_SLOT_HISTORY/8)
+	// Slots 0, 2 and MAX_SLOT_HISTORY + 1 (wrapping to bit 1) are produced
+	blocks[0] = 0b0000_0111
+
+	data := concat([]byte{1}, u64(MAX_SLOT_HISTORY/64), blocks, u64(MAX_SLOT_HISTORY), u64(MAX_SLOT_HISTORY+2))
+	require.Len(t, data, SLOT_HISTORY_SIZE)
+
+	decoded, err := solana.DecodeAccount(SYSVAR_SLOT_HISTORY, PROGRAM_ID, data)
+	require.NoError(t, err)
+	history := decoded.(*SlotHistory)
+
+	assert.Equal(t, SlotHistoryCheckFuture, history.Check(MAX_SLOT_HISTORY+2))
+	assert.Equal(t, SlotHistoryCheckFound, history.Check(MAX_SLOT_HISTORY+1))
+	assert.Equal(t, SlotHistoryCheckNotFound, history.Check(5))
+	assert.Equal(t, SlotHistoryCheckFound, history.Check(2))
+	assert.Equal(t, SlotHistoryCheckTooOld, history.Check(1))
+}
+
+func TestInstructions_Decode(t *testing.T) {
+	programID := solana.MustPublicKeyFromBase58("ComputeBudget111111111111111111111111111111")
+	signer := solana.MustPublicKeyFromBase58("SysvarC1ock11111111111111111111111111111111")
+	readonly := solana.MustPublicKeyFromBase58("SysvarRent111111111111111111111111111111111")
+
+	first := concat(u16(2), []byte{0b11}, signer[:], []byte{0}, readonly[:], programID[:], u16(3), []byte{1, 2, 3})
+	second := concat(u16(0), programID[:], u16(0))
+
+	data := concat(u16(2), u16(6), u16(uint16(6+len(first))), first, second, u16(1))
+
+	instructions := &Instructions{}
+	require.NoError(t, instructions.Decode(data))
+	require.Len(t, instructions.Instructions, 2)
+	assert.Equal(t, &Instruction{
+		ProgramID: programID,
+		Accounts: []*solana.AccountMeta{
+			{PublicKey: signer, IsSigner: true, IsWritable: true},
+			{PublicKey: readonly},
+		},
+		Data: []byte{1, 2, 3},
+	}, instructions.Instructions[0])
+
+	current, err := instructions.Current()
+	require.NoError(t, err)
+	assert.Equal(t, &Instruction{ProgramID: programID, Accounts: []*solana.AccountMeta{}, Data: []byte{}}, current)
+
+	_, err = LoadInstructionAt(data, 2)
+	assert.EqualError(t, err, "instruction 2: out of range, transaction has 2 instructions")
+
+	assert.Error(t, instructions.Decode(data[:20]))
+}
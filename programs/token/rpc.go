@@ -27,8 +27,9 @@ import (
 
 //go:generate rice embed-go
 
-func FetchMints(rpcCli *rpc.Client) (out []*Mint, err error) {
+func FetchMints(ctx context.Context, rpcCli *rpc.Client) (out []*Mint, err error) {
 	resp, err := rpcCli.GetProgramAccounts(
+		ctx,
 		PROGRAM_ID,
 		&rpc.GetProgramAccountsOpts{
 			Filters: []rpc.RPCFilter{
@@ -58,8 +59,8 @@ func FetchMints(rpcCli *rpc.Client) (out []*Mint, err error) {
 	return
 }
 
-func FetchMint(rpcCli *rpc.Client, mintAddr solana.PublicKey) (out *Mint, err error) {
-	resp, err := rpcCli.GetAccountInfo(mintAddr)
+func FetchMint(ctx context.Context, rpcCli *rpc.Client, mintAddr solana.PublicKey) (out *Mint, err error) {
+	resp, err := rpcCli.GetAccountInfo(ctx, mintAddr)
 	if err != nil {
 		return nil, err
 	}
@@ -71,8 +72,9 @@ func FetchMint(rpcCli *rpc.Client, mintAddr solana.PublicKey) (out *Mint, err er
 	return m, nil
 }
 
-func FetchAccountsForOwner(rpcCli *rpc.Client, owner solana.PublicKey) (out []*Account, err error) {
+func FetchAccountsForOwner(ctx context.Context, rpcCli *rpc.Client, owner solana.PublicKey) (out []*Account, err error) {
 	resp, err := rpcCli.GetProgramAccounts(
+		ctx,
 		PROGRAM_ID,
 		&rpc.GetProgramAccountsOpts{
 			Filters: []rpc.RPCFilter{
@@ -101,8 +103,9 @@ func FetchAccountsForOwner(rpcCli *rpc.Client, owner solana.PublicKey) (out []*A
 	return
 }
 
-func FetchAccountHolders(rpcCli *rpc.Client, mint solana.PublicKey) (out []*Account, err error) {
+func FetchAccountHolders(ctx context.Context, rpcCli *rpc.Client, mint solana.PublicKey) (out []*Account, err error) {
 	resp, err := rpcCli.GetProgramAccounts(
+		ctx,
 		PROGRAM_ID,
 		&rpc.GetProgramAccountsOpts{
 			Filters: []rpc.RPCFilter{
@@ -131,8 +134,8 @@ func FetchAccountHolders(rpcCli *rpc.Client, mint solana.PublicKey) (out []*Acco
 	return
 }
 
-func TransferToken(ctx context.Context, rpcCli *rpc.Client, wsCli *ws.Client, amount uint64, senderSPLTokenAccount, mint, recipient solana.PublicKey, sender *solana.Account) (solana.PublicKey, string, error) {
-	blockHashResult, err := rpcCli.GetLatestBlockhash(rpc.CommitmentFinalized)
+func TransferToken(ctx context.Context, rpcCli *rpc.Client, wsCli *ws.Client, amount uint64, senderSPLTokenAccount, mint, recipient solana.PublicKey, sender solana.Signer) (solana.PublicKey, string, error) {
+	blockHashResult, err := rpcCli.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
 	if err != nil {
 		return solana.PublicKey{}, "", fmt.Errorf("unable retrieve recent block hash: %w", err)
 	}
@@ -155,14 +158,9 @@ func TransferToken(ctx context.Context, rpcCli *rpc.Client, wsCli *ws.Client, am
 		return solana.PublicKey{}, "", fmt.Errorf("unable to craft transaction: %w", err)
 	}
 
-	_, err = trx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
-		// create account need to be signed by the private key of the new account
-		// that is not in the vault and will be lost after the execution.
-		if key == sender.PublicKey() {
-			return &sender.PrivateKey
-		}
-		return nil
-	})
+	if _, err = trx.Sign(sender); err != nil {
+		return solana.PublicKey{}, "", fmt.Errorf("unable to sign transaction: %w", err)
+	}
 
 	trxHash, err := confirm.SendAndConfirmTransaction(ctx, rpcCli, wsCli, trx)
 	if err != nil {
@@ -172,8 +170,8 @@ func TransferToken(ctx context.Context, rpcCli *rpc.Client, wsCli *ws.Client, am
 	return recipientSPLTokenAccount, trxHash, nil
 }
 
-func DoCloseAccount(ctx context.Context, rpcCli *rpc.Client, wsCli *ws.Client, account, destination, owner solana.PublicKey, sender *solana.Account) (string, error) {
-	blockHashResult, err := rpcCli.GetLatestBlockhash(rpc.CommitmentFinalized)
+func DoCloseAccount(ctx context.Context, rpcCli *rpc.Client, wsCli *ws.Client, account, destination, owner solana.PublicKey, sender solana.Signer) (string, error) {
+	blockHashResult, err := rpcCli.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
 	if err != nil {
 		return "", fmt.Errorf("unable retrieve recent block hash: %w", err)
 	}
@@ -188,14 +186,9 @@ func DoCloseAccount(ctx context.Context, rpcCli *rpc.Client, wsCli *ws.Client, a
 		return "", fmt.Errorf("unable to craft transaction: %w", err)
 	}
 
-	_, err = trx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
-		// create account need to be signed by the private key of the new account
-		// that is not in the vault and will be lost after the execution.
-		if key == sender.PublicKey() {
-			return &sender.PrivateKey
-		}
-		return nil
-	})
+	if _, err = trx.Sign(sender); err != nil {
+		return "", fmt.Errorf("unable to sign transaction: %w", err)
+	}
 
 	trxHash, err := confirm.SendAndConfirmTransaction(ctx, rpcCli, wsCli, trx)
 	if err != nil {
@@ -7,6 +7,21 @@ import (
 	"github.com/streamingfast/solana-go"
 )
 
+func init() {
+	solana.RegisterAccountDecoder(PROGRAM_ID, solana.MatchDataSize(ACCOUNT_SIZE), func(key solana.PublicKey, data []byte) (interface{}, error) {
+		account := &Account{}
+		return account, account.Decode(key, data)
+	})
+	solana.RegisterAccountDecoder(PROGRAM_ID, solana.MatchDataSize(MINT_SIZE), func(key solana.PublicKey, data []byte) (interface{}, error) {
+		mint := &Mint{}
+		return mint, mint.Decode(data)
+	})
+	solana.RegisterAccountDecoder(PROGRAM_ID, solana.MatchDataSize(MULTISIG_SIZE), func(key solana.PublicKey, data []byte) (interface{}, error) {
+		multisig := &Multisig{}
+		return multisig, multisig.Decode(data)
+	})
+}
+
 // Token contract interface
 
 type Token struct {
@@ -43,6 +58,19 @@ func (a *Account) Decode(key solana.PublicKey, in []byte) error {
 	return nil
 }
 
+// TokenAmount returns the account's balance, `mint` being the account's mint.
+func (a *Account) TokenAmount(mint *Mint) solana.TokenAmount {
+	return solana.NewTokenAmount(uint64(a.Amount), mint.Decimals)
+}
+
+// DelegatedTokenAmount returns the amount the delegate can transfer, `mint`
+// being the account's mint.
+func (a *Account) DelegatedTokenAmount(mint *Mint) solana.TokenAmount {
+	return solana.NewTokenAmount(uint64(a.DelegatedAmount), mint.Decimals)
+}
+
+const MULTISIG_SIZE = 355
+
 type Multisig struct {
 	M             byte
 	N             byte
@@ -50,6 +78,15 @@ type Multisig struct {
 	Signers       [11]solana.PublicKey
 }
 
+func (m *Multisig) Decode(in []byte) error {
+	decoder := bin.NewDecoder(in)
+	err := decoder.Decode(&m)
+	if err != nil {
+		return fmt.Errorf("unpack: %w", err)
+	}
+	return nil
+}
+
 const MINT_SIZE = 82
 
 type Mint struct {
@@ -71,6 +108,16 @@ func (m *Mint) Decode(in []byte) error {
 	return nil
 }
 
+// SupplyAmount returns the mint's total supply.
+func (m *Mint) SupplyAmount() solana.TokenAmount {
+	return solana.NewTokenAmount(uint64(m.Supply), m.Decimals)
+}
+
+// ParseAmount parses a decimal amount of the token, like `1.5`.
+func (m *Mint) ParseAmount(amount string) (solana.TokenAmount, error) {
+	return solana.ParseTokenAmount(amount, m.Decimals)
+}
+
 type MintMeta struct {
 	TokenSymbol string
 	MintAddress solana.PublicKey
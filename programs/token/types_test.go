@@ -16,6 +16,7 @@ package token
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"testing"
@@ -70,7 +71,7 @@ func TestMint(t *testing.T) {
 	cli := rpc.NewClient("https://api.mainnet-beta.solana.com")
 
 	var m Mint
-	err := cli.GetAccountDataIn(addr, &m)
+	err := cli.GetAccountDataIn(context.Background(), addr, &m)
 	// handle `err`
 	require.NoError(t, err)
 
@@ -86,7 +87,7 @@ func TestRawMint(t *testing.T) {
 	addr := solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
 	cli := rpc.NewClient("https://api.mainnet-beta.solana.com")
 
-	resp, err := cli.GetAccountInfo(addr)
+	resp, err := cli.GetAccountInfo(context.Background(), addr)
 	// handle `err`
 	require.NoError(t, err)
 
@@ -96,3 +97,43 @@ func TestRawMint(t *testing.T) {
 	//  "Decimals":128,
 	//  "IsInitialized":true}
 }
+
+func TestDecodeAccount_Registry(t *testing.T) {
+	data, err := base58.Decode("SqtzmJArwV2556pK7AdHbHNPVP2L2WaR6zfcFeot94TzGRUyUMEWew558UxnYEGrmm9b9VZY7MS6TCHT5wqtzaA5Vy8ghoFyGmbRNC58CttRf5GzH9wfjCkncyrmKjfevyjrJ2W9XKLgYGth46ctFWzJJXCeHsYwDx1d")
+	require.NoError(t, err)
+	// On-chain accounts are ACCOUNT_SIZE long, padding the fields we decode
+	data = append(data, make([]byte, ACCOUNT_SIZE-len(data))...)
+	key := solana.MustPublicKeyFromBase58("Gr5UanqwiKA54GGnw4b1bB5M8eatQzj6s6FQ9FeTze5C")
+
+	keyedAccount := &rpc.KeyedAccount{
+		Pubkey:  key,
+		Account: &rpc.Account{Owner: PROGRAM_ID, Data: data},
+	}
+
+	decoded, err := keyedAccount.Decode()
+	require.NoError(t, err)
+	require.IsType(t, &Account{}, decoded)
+	assert.Equal(t, key, decoded.(*Account).Key)
+	assert.Equal(t, bin.Uint64(52830), decoded.(*Account).Amount)
+
+	decoded, err = solana.DecodeAccount(key, PROGRAM_ID, make([]byte, MINT_SIZE))
+	require.NoError(t, err)
+	assert.IsType(t, &Mint{}, decoded)
+
+	decoded, err = solana.DecodeAccount(key, PROGRAM_ID, make([]byte, MULTISIG_SIZE))
+	require.NoError(t, err)
+	assert.IsType(t, &Multisig{}, decoded)
+}
+
+func TestAccount_TokenAmount(t *testing.T) {
+	mint := &Mint{Supply: 1_000_000_000_000, Decimals: 6}
+	account := &Account{Amount: 1_500_001, DelegatedAmount: 500_000}
+
+	assert.Equal(t, "1.500001", account.TokenAmount(mint).String())
+	assert.Equal(t, "0.5", account.DelegatedTokenAmount(mint).String())
+	assert.Equal(t, "1000000", mint.SupplyAmount().String())
+
+	amount, err := mint.ParseAmount("2.25")
+	require.NoError(t, err)
+	assert.Equal(t, solana.NewTokenAmount(2_250_000, 6), amount)
+}
@@ -1,14 +1,16 @@
 package tokenregistry
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/streamingfast/solana-go"
 	"github.com/streamingfast/solana-go/rpc"
 )
 
-func GetTokenRegistryEntry(rpcCli *rpc.Client, mintAddress solana.PublicKey) (*TokenMeta, error) {
+func GetTokenRegistryEntry(ctx context.Context, rpcCli *rpc.Client, mintAddress solana.PublicKey) (*TokenMeta, error) {
 	resp, err := rpcCli.GetProgramAccounts(
+		ctx,
 		ProgramID(),
 		&rpc.GetProgramAccountsOpts{
 			Filters: []rpc.RPCFilter{
@@ -39,8 +41,9 @@ func GetTokenRegistryEntry(rpcCli *rpc.Client, mintAddress solana.PublicKey) (*T
 	return nil, rpc.ErrNotFound
 }
 
-func GetEntries(rpcCli *rpc.Client) (out []*TokenMeta, err error) {
+func GetEntries(ctx context.Context, rpcCli *rpc.Client) (out []*TokenMeta, err error) {
 	resp, err := rpcCli.GetProgramAccounts(
+		ctx,
 		ProgramID(),
 		&rpc.GetProgramAccountsOpts{
 			Filters: []rpc.RPCFilter{
@@ -2,6 +2,7 @@ package solana
 
 import (
 	"fmt"
+	"sync"
 )
 
 // InstructionDecoder receives the AccountMeta FOR THAT INSTRUCTION,
@@ -9,22 +10,219 @@ import (
 // CompiledInstruction.ResolveInstructionAccounts(message) beforehand.
 type InstructionDecoder func(instructionAccounts []*AccountMeta, data []byte) (interface{}, error)
 
+// Registry maps program IDs to the InstructionDecoder able to decode their
+// instructions. A program ID can also be an alias of another one, in which case
+// it's decoded by whatever decoder the aliased program ID currently has.
+//
+// Programs register themselves in DefaultRegistry, isolated registries can be
+// obtained with NewRegistry or by cloning the default one.
+type Registry struct {
+	lock     sync.RWMutex
+	decoders map[PublicKey]InstructionDecoder
+	aliases  map[PublicKey]PublicKey
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		decoders: map[PublicKey]InstructionDecoder{},
+		aliases:  map[PublicKey]PublicKey{},
+	}
+}
+
+// Register adds the decoder of `programID`, it's an error if the program ID is
+// already registered or is an alias.
+func (r *Registry) Register(programID PublicKey, decoder InstructionDecoder) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, found := r.decoders[programID]; found {
+		return fmt.Errorf("instruction decoder for program %q already registered", programID)
+	}
+	if target, found := r.aliases[programID]; found {
+		return fmt.Errorf("program %q is already an alias of program %q", programID, target)
+	}
+
+	r.set(programID, decoder)
+	return nil
+}
+
+// Replace registers the decoder of `programID`, overriding any decoder
+// previously registered for it. If `programID` was an alias, the alias is
+// removed.
+func (r *Registry) Replace(programID PublicKey, decoder InstructionDecoder) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.aliases, programID)
+	r.set(programID, decoder)
+}
+
+// Unregister removes the decoder or the alias of `programID`, returning whether
+// anything was removed. Aliases pointing to `programID` are kept and start to
+// resolve again once a new decoder is registered for it.
+func (r *Registry) Unregister(programID PublicKey) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, found := r.aliases[programID]; found {
+		delete(r.aliases, programID)
+		return true
+	}
+
+	if _, found := r.decoders[programID]; found {
+		delete(r.decoders, programID)
+		return true
+	}
+	return false
+}
+
+// Alias makes `alias` decoded by the decoder of `programID`, used for programs
+// deployed at multiple addresses. An alias always resolves to the current
+// decoder of `programID`, so replacing it affects all its aliases.
+func (r *Registry) Alias(alias PublicKey, programID PublicKey) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if alias == programID {
+		return fmt.Errorf("program %q cannot be an alias of itself", alias)
+	}
+	if _, found := r.decoders[alias]; found {
+		return fmt.Errorf("instruction decoder for program %q already registered", alias)
+	}
+	if target, found := r.aliases[alias]; found {
+		return fmt.Errorf("program %q is already an alias of program %q", alias, target)
+	}
+	for other, target := range r.aliases {
+		if target == alias {
+			return fmt.Errorf("program %q is aliased by program %q, it cannot itself be an alias", alias, other)
+		}
+	}
+	if target, found := r.aliases[programID]; found {
+		// Aliases are kept one level deep
+		programID = target
+	}
+
+	r.aliases[alias] = programID
+	return nil
+}
+
+// Lookup returns the decoder used for `programID`, resolving aliases.
+func (r *Registry) Lookup(programID PublicKey) (InstructionDecoder, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.lookup(programID)
+}
+
+// ProgramIDs returns the registered program IDs, aliases included.
+func (r *Registry) ProgramIDs() (out []PublicKey) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for programID := range r.decoders {
+		out = append(out, programID)
+	}
+	for alias := range r.aliases {
+		out = append(out, alias)
+	}
+	return out
+}
+
+// Clone returns an independent copy of the registry, further changes to either
+// of them are not visible in the other one.
+func (r *Registry) Clone() *Registry {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	out := NewRegistry()
+	for programID, decoder := range r.decoders {
+		out.decoders[programID] = decoder
+	}
+	for alias, programID := range r.aliases {
+		out.aliases[alias] = programID
+	}
+	return out
+}
+
+// DecodeInstruction decodes the instruction using the decoder registered for
+// `programID`.
+func (r *Registry) DecodeInstruction(programID PublicKey, accounts []*AccountMeta, data []byte) (interface{}, error) {
+	decoder, found := r.Lookup(programID)
+	if !found {
+		return nil, fmt.Errorf("unknown programID, cannot find any instruction decoder %q", programID)
+	}
+
+	return decoder(accounts, data)
+}
+
+func (r *Registry) lookup(programID PublicKey) (InstructionDecoder, bool) {
+	if target, found := r.aliases[programID]; found {
+		programID = target
+	}
+
+	decoder, found := r.decoders[programID]
+	return decoder, found
+}
+
+func (r *Registry) set(programID PublicKey, decoder InstructionDecoder) {
+	r.decoders[programID] = decoder
+}
+
+// DefaultRegistry is the registry programs register their decoder in when
+// imported, used by DecodeInstruction and DecodeTransaction.
+var DefaultRegistry = NewRegistry()
+
+// InstructionDecoderRegistry holds the decoders registered through
+// RegisterInstructionDecoder and RegisterInstructionDecoderAlias, keyed by
+// base58 program ID. Changes made later through the DefaultRegistry methods
+// are not reflected in it. The map is not safe for concurrent use, it's only
+// written while packages are initialized and is read by DecodeInstruction for
+// program IDs not found in DefaultRegistry.
+//
+// Deprecated: Use DefaultRegistry instead, entries must be added to this map
+// before any decoding starts, in an `init` function for example.
 var InstructionDecoderRegistry = map[string]InstructionDecoder{}
 
+// registeredInstructionDecoders are the program IDs InstructionDecoderRegistry
+// holds because they were registered in DefaultRegistry, DecodeInstruction only
+// uses the decoders DefaultRegistry currently has for them.
+var registeredInstructionDecoders = map[string]bool{}
+
+// RegisterInstructionDecoder registers the decoder in DefaultRegistry and
+// InstructionDecoderRegistry, panicking if the program ID is already
+// registered. It must be called while packages are initialized.
 func RegisterInstructionDecoder(programID PublicKey, decoder InstructionDecoder) {
-	p := programID.String()
-	if _, found := InstructionDecoderRegistry[p]; found {
-		panic(fmt.Sprintf("unable to re-register instruction decoder for program %q", p))
+	if err := DefaultRegistry.Register(programID, decoder); err != nil {
+		panic(fmt.Sprintf("unable to re-register instruction decoder for program %q: %s", programID, err))
+	}
+
+	InstructionDecoderRegistry[programID.String()] = decoder
+	registeredInstructionDecoders[programID.String()] = true
+}
+
+// RegisterInstructionDecoderAlias makes `alias` decoded by the decoder of
+// `programID` in DefaultRegistry, and adds that decoder to
+// InstructionDecoderRegistry for `alias`, panicking if `alias` is already
+// registered. It must be called while packages are initialized.
+func RegisterInstructionDecoderAlias(alias PublicKey, programID PublicKey) {
+	if err := DefaultRegistry.Alias(alias, programID); err != nil {
+		panic(fmt.Sprintf("unable to register instruction decoder alias for program %q: %s", alias, err))
 	}
 
-	InstructionDecoderRegistry[p] = decoder
+	if decoder, found := DefaultRegistry.Lookup(alias); found {
+		InstructionDecoderRegistry[alias.String()] = decoder
+		registeredInstructionDecoders[alias.String()] = true
+	}
 }
 
 func DecodeInstruction(programID PublicKey, accounts []*AccountMeta, data []byte) (interface{}, error) {
-	p := programID.String()
+	if decoder, found := DefaultRegistry.Lookup(programID); found {
+		return decoder(accounts, data)
+	}
 
+	p := programID.String()
 	decoder, found := InstructionDecoderRegistry[p]
-	if !found {
+	if !found || registeredInstructionDecoders[p] {
 		return nil, fmt.Errorf("unknown programID, cannot find any instruction decoder %q", p)
 	}
 
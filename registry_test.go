@@ -0,0 +1,131 @@
+package solana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func constantDecoder(value string) InstructionDecoder {
+	return func(accounts []*AccountMeta, data []byte) (interface{}, error) {
+		return value, nil
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	programA := MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn")
+	programB := MustPublicKeyFromBase58("9hFtYBYmBJCVguRYs9pBTWKYAFoKfjYR7zBPpEkVsmD")
+	programC := MustPublicKeyFromBase58("Vote111111111111111111111111111111111111111")
+
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(programA, constantDecoder("v1")))
+	assert.Error(t, registry.Register(programA, constantDecoder("v2")))
+
+	require.NoError(t, registry.Alias(programB, programA))
+	assert.Error(t, registry.Alias(programB, programA))
+	assert.Error(t, registry.Register(programB, constantDecoder("v2")))
+	assert.Error(t, registry.Alias(programA, programC), "aliased program cannot become an alias")
+
+	decoded, err := registry.DecodeInstruction(programB, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", decoded)
+
+	clone := registry.Clone()
+	registry.Replace(programA, constantDecoder("v2"))
+
+	decoded, err = registry.DecodeInstruction(programB, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", decoded, "alias follows replaced decoder")
+
+	decoded, err = clone.DecodeInstruction(programB, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", decoded, "clone is not affected by changes to its source")
+
+	assert.True(t, registry.Unregister(programA))
+	assert.False(t, registry.Unregister(programA))
+	_, err = registry.DecodeInstruction(programA, nil, nil)
+	assert.Error(t, err)
+	_, err = registry.DecodeInstruction(programB, nil, nil)
+	assert.Error(t, err)
+
+	assert.ElementsMatch(t, []PublicKey{programA, programB}, clone.ProgramIDs())
+}
+
+func TestDefaultRegistry_LegacyMap(t *testing.T) {
+	programID := MustPublicKeyFromBase58("RegistryTest1111111111111111111111111111111")
+	alias := MustPublicKeyFromBase58("RegistryA1ias111111111111111111111111111111")
+	legacy := MustPublicKeyFromBase58("RegistryLegacy11111111111111111111111111111")
+	defer func() {
+		DefaultRegistry.Unregister(alias)
+		DefaultRegistry.Unregister(programID)
+		for _, key := range []PublicKey{programID, alias, legacy} {
+			delete(InstructionDecoderRegistry, key.String())
+			delete(registeredInstructionDecoders, key.String())
+		}
+	}()
+
+	RegisterInstructionDecoder(programID, constantDecoder("v1"))
+	assert.Panics(t, func() { RegisterInstructionDecoder(programID, constantDecoder("v1")) })
+
+	RegisterInstructionDecoderAlias(alias, programID)
+	for _, key := range []PublicKey{programID, alias} {
+		require.Contains(t, InstructionDecoderRegistry, key.String())
+		decoded, err := InstructionDecoderRegistry[key.String()](nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "v1", decoded)
+	}
+
+	DefaultRegistry.Replace(programID, constantDecoder("v2"))
+	decoded, err := DecodeInstruction(alias, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", decoded)
+
+	InstructionDecoderRegistry[legacy.String()] = constantDecoder("legacy")
+	decoded, err = DecodeInstruction(legacy, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", decoded)
+
+	DefaultRegistry.Unregister(programID)
+	_, err = DecodeInstruction(alias, nil, nil)
+	assert.Error(t, err, "alias of an unregistered program is not decoded")
+	_, err = DecodeInstruction(programID, nil, nil)
+	assert.Error(t, err, "unregistered program is not decoded through the legacy map")
+
+	require.NoError(t, DefaultRegistry.Register(programID, constantDecoder("v3")))
+	decoded, err = DecodeInstruction(alias, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v3", decoded, "alias resolves again once its program is registered")
+}
+
+func TestDefaultRegistry_ConcurrentDecode(t *testing.T) {
+	programID := MustPublicKeyFromBase58("RegistryTest1111111111111111111111111111111")
+	alias := MustPublicKeyFromBase58("RegistryA1ias111111111111111111111111111111")
+	unknown := MustPublicKeyFromBase58("RegistryUnknown1111111111111111111111111111")
+	defer func() {
+		DefaultRegistry.Unregister(alias)
+		DefaultRegistry.Unregister(programID)
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			DefaultRegistry.Replace(programID, constantDecoder("v1"))
+			DefaultRegistry.Alias(alias, programID)
+			DefaultRegistry.Unregister(alias)
+			DefaultRegistry.Unregister(programID)
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		DecodeInstruction(unknown, nil, nil)
+		DecodeInstruction(alias, nil, nil)
+	}
+}
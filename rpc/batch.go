@@ -0,0 +1,144 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/streamingfast/logging"
+	"github.com/ybbus/jsonrpc"
+	"go.uber.org/zap"
+)
+
+// DefaultMaxBatchSize is the maximum number of calls DoBatch sends in a single
+// HTTP request unless configured otherwise with WithMaxBatchSize.
+const DefaultMaxBatchSize = 100
+
+// BatchRequest is a single call of a JSON-RPC batch. Once the batch is
+// performed, the call's result is decoded in `Out` or its error is set in
+// `Err`.
+type BatchRequest struct {
+	Method string
+	Params []interface{}
+	Out    interface{}
+	Err    error
+}
+
+func NewBatchRequest(out interface{}, method string, params ...interface{}) *BatchRequest {
+	return &BatchRequest{
+		Method: method,
+		Params: params,
+		Out:    out,
+	}
+}
+
+// DoBatch performs all `requests` in as few HTTP requests as possible, at most
+// the client's maximum batch size calls being sent at once. Results and
+// errors of the individual calls are set on each request, the returned error
+// reports failures of the batch as a whole (transport, cancellation, invalid
+// response) in which case the requests of the failed and remaining batches are
+// left untouched.
+func (c *Client) DoBatch(ctx context.Context, requests []*BatchRequest) error {
+	if c.router != nil {
+		return c.router.doBatch(ctx, requests)
+	}
+
+	size := c.maxBatchSize
+	if size <= 0 {
+		size = len(requests)
+	}
+
+	for start := 0; start < len(requests); start += size {
+		end := start + size
+		if end > len(requests) {
+			end = len(requests)
+		}
+
+		if err := c.doBatch(ctx, requests[start:end]); err != nil {
+			return fmt.Errorf("batch [%d, %d[: %w", start, end, err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) doBatch(ctx context.Context, requests []*BatchRequest) error {
+	// Calls are identified by their position in the batch, responses can be
+	// received in any order.
+	rpcRequests := make(jsonrpc.RPCRequests, len(requests))
+	for i, request := range requests {
+		rpcRequests[i] = jsonrpc.NewRequest(request.Method, request.Params...)
+		rpcRequests[i].ID = i
+	}
+
+	logger := logging.Logger(ctx, zlog).With(zap.Int("batch_size", len(requests)))
+	ctx = logging.WithLogger(ctx, logger)
+
+	startTime := time.Now()
+	logger.Debug("performing JSON-RPC batch call")
+	defer func() {
+		logger.Debug("performed JSON-RPC batch call", zap.Duration("overall", time.Since(startTime)))
+	}()
+
+	idempotent := true
+	for _, request := range requests {
+		idempotent = idempotent && !NonIdempotentMethods[request.Method]
+	}
+
+	var rpcResponses jsonrpc.RPCResponses
+	err := c.withRetry(ctx, logger, idempotent, func() error {
+		var raw json.RawMessage
+		if err := c.post(ctx, fmt.Sprintf("batch of %d", len(requests)), rpcRequests, &raw); err != nil {
+			return err
+		}
+
+		// Nodes answer with a single response, not a list, when they reject
+		// the batch as a whole (rate limiting, batch too large, ...).
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '{' {
+			var rpcResponse *jsonrpc.RPCResponse
+			if err := decodeJSON(trimmed, &rpcResponse); err != nil {
+				return fmt.Errorf("decode batch response: %w", err)
+			}
+			if rpcResponse.Error != nil {
+				return fmt.Errorf("rpc response: %w", rpcResponse.Error)
+			}
+			return fmt.Errorf("expected a list of rpc responses, got a single one")
+		}
+
+		if err := decodeJSON(raw, &rpcResponses); err != nil {
+			return fmt.Errorf("decode batch response: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	responses := make([]*jsonrpc.RPCResponse, len(requests))
+	for _, rpcResponse := range rpcResponses {
+		if rpcResponse == nil || rpcResponse.ID < 0 || rpcResponse.ID >= len(requests) {
+			continue
+		}
+		responses[rpcResponse.ID] = rpcResponse
+	}
+
+	for i, request := range requests {
+		rpcResponse := responses[i]
+		switch {
+		case rpcResponse == nil:
+			request.Err = fmt.Errorf("rpc response missing for %s() call", request.Method)
+		case rpcResponse.Error != nil:
+			request.Err = fmt.Errorf("rpc response: %w", rpcResponse.Error)
+		default:
+			request.Err = rpcResponse.GetObject(request.Out)
+		}
+	}
+	return nil
+}
+
+func decodeJSON(data []byte, out interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(out)
+}
@@ -0,0 +1,199 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ybbus/jsonrpc"
+)
+
+type batchCall struct {
+	ID     int               `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// mockBatchJSONRPC serves batches, answering each call with `handler` in the
+// reverse order of the requests. The received batches are returned.
+func mockBatchJSONRPC(t *testing.T, handler func(call batchCall) (result interface{}, rpcErr *jsonrpc.RPCError, skip bool)) (server *httptest.Server, batches *[][]batchCall) {
+	batches = &[][]batchCall{}
+	server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var calls []batchCall
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&calls))
+		*batches = append(*batches, calls)
+
+		var responses []map[string]interface{}
+		for i := len(calls) - 1; i >= 0; i-- {
+			result, rpcErr, skip := handler(calls[i])
+			if skip {
+				continue
+			}
+
+			response := map[string]interface{}{"jsonrpc": "2.0", "id": calls[i].ID}
+			if rpcErr != nil {
+				response["error"] = rpcErr
+			} else {
+				response["result"] = result
+			}
+			responses = append(responses, response)
+		}
+
+		require.NoError(t, json.NewEncoder(rw).Encode(responses))
+	}))
+
+	return server, batches
+}
+
+func TestClient_DoBatch(t *testing.T) {
+	server, batches := mockBatchJSONRPC(t, func(call batchCall) (interface{}, *jsonrpc.RPCError, bool) {
+		switch call.Method {
+		case "getSlot":
+			return 42, nil, false
+		case "getBalance":
+			return nil, &jsonrpc.RPCError{Code: -32602, Message: "Invalid param"}, false
+		case "getHealth":
+			return nil, nil, true
+		}
+		return call.Method, nil, false
+	})
+	defer server.Close()
+
+	client := NewClient(server.URL, WithMaxBatchSize(2))
+
+	var slot1, slot2 uint64
+	var balance interface{}
+	var health string
+	var version string
+	requests := []*BatchRequest{
+		NewBatchRequest(&slot1, "getSlot"),
+		NewBatchRequest(&balance, "getBalance", "invalid"),
+		NewBatchRequest(&version, "getVersion"),
+		NewBatchRequest(&health, "getHealth"),
+		NewBatchRequest(&slot2, "getSlot"),
+	}
+
+	require.NoError(t, client.DoBatch(context.Background(), requests))
+
+	require.Len(t, *batches, 3)
+	assert.Len(t, (*batches)[0], 2)
+	assert.Len(t, (*batches)[1], 2)
+	assert.Len(t, (*batches)[2], 1)
+	assert.Equal(t, `"invalid"`, string((*batches)[0][1].Params[0]))
+
+	assert.NoError(t, requests[0].Err)
+	assert.Equal(t, uint64(42), slot1)
+
+	var rpcErr *jsonrpc.RPCError
+	require.True(t, errors.As(requests[1].Err, &rpcErr))
+	assert.Equal(t, -32602, rpcErr.Code)
+
+	assert.NoError(t, requests[2].Err)
+	assert.Equal(t, "getVersion", version)
+
+	assert.EqualError(t, requests[3].Err, "rpc response missing for getHealth() call")
+
+	assert.NoError(t, requests[4].Err)
+	assert.Equal(t, uint64(42), slot2)
+}
+
+func TestClient_DoBatch_Rejected(t *testing.T) {
+	server, closer := mockJSONRPC(t, json.RawMessage(`{"jsonrpc":"2.0","error":{"code":429,"message":"Too many requests"},"id":null}`))
+	defer closer()
+
+	var slot uint64
+	requests := []*BatchRequest{NewBatchRequest(&slot, "getSlot")}
+
+	err := newTestClient(server.URL).DoBatch(context.Background(), requests)
+	require.Error(t, err)
+
+	var rpcErr *jsonrpc.RPCError
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, 429, rpcErr.Code)
+	assert.NoError(t, requests[0].Err)
+}
+
+func TestClient_GetAccountInfos(t *testing.T) {
+	existing := solana.MustPublicKeyFromBase58("SysvarC1ock11111111111111111111111111111111")
+	missing := solana.MustPublicKeyFromBase58("11111111111111111111111111111111")
+	failing := solana.MustPublicKeyFromBase58("SysvarRent111111111111111111111111111111111")
+
+	server, batches := mockBatchJSONRPC(t, func(call batchCall) (interface{}, *jsonrpc.RPCError, bool) {
+		if string(call.Params[0]) == `"`+existing.String()+`"` {
+			return map[string]interface{}{
+				"context": map[string]interface{}{"slot": 1},
+				"value": map[string]interface{}{
+					"data":       []string{"AQID", "base64"},
+					"executable": false,
+					"lamports":   1169280,
+					"owner":      "Sysvar1111111111111111111111111111111111111",
+					"rentEpoch":  0,
+				},
+			}, nil, false
+		}
+		if string(call.Params[0]) == `"`+failing.String()+`"` {
+			return nil, &jsonrpc.RPCError{Code: -32602, Message: "Invalid param"}, false
+		}
+		return map[string]interface{}{"context": map[string]interface{}{"slot": 1}, "value": nil}, nil, false
+	})
+	defer server.Close()
+
+	accounts, err := newTestClient(server.URL).GetAccountInfos(context.Background(), []solana.PublicKey{missing, failing, existing})
+	require.NoError(t, err)
+
+	require.Len(t, *batches, 1)
+	assert.Equal(t, "getAccountInfo", (*batches)[0][0].Method)
+
+	require.Len(t, accounts, 3)
+	assert.Equal(t, &AccountResult{Account: missing}, accounts[0])
+
+	assert.Equal(t, failing, accounts[1].Account)
+	assert.Nil(t, accounts[1].Value)
+	assert.EqualError(t, accounts[1].Err, "rpc response: -32602:Invalid param")
+
+	require.NoError(t, accounts[2].Err)
+	require.NotNil(t, accounts[2].Value)
+	assert.Equal(t, solana.Data([]byte{1, 2, 3}), accounts[2].Value.Data)
+	assert.Equal(t, uint64(1169280), uint64(accounts[2].Value.Lamports))
+}
+
+func TestClient_GetTransactions(t *testing.T) {
+	server, batches := mockBatchJSONRPC(t, func(call batchCall) (interface{}, *jsonrpc.RPCError, bool) {
+		switch string(call.Params[0]) {
+		case `"found"`:
+			return map[string]interface{}{"slot": 10, "version": 0}, nil, false
+		case `"failing"`:
+			return nil, &jsonrpc.RPCError{Code: -32009, Message: "Slot skipped"}, false
+		}
+		return nil, nil, false
+	})
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	commitment := CommitmentFinalized
+
+	trxs, err := client.GetTransactions(context.Background(), []string{"found", "failing", "unknown"}, &commitment)
+	require.NoError(t, err)
+	require.Len(t, trxs, 3)
+
+	require.NoError(t, trxs[0].Err)
+	require.NotNil(t, trxs[0].Value)
+	assert.Equal(t, uint64(10), uint64(trxs[0].Value.Slot))
+	require.NotNil(t, trxs[0].Value.Version)
+	assert.Equal(t, solana.MessageVersionV0, *trxs[0].Value.Version)
+
+	assert.Equal(t, "failing", trxs[1].Signature)
+	assert.Nil(t, trxs[1].Value)
+	assert.EqualError(t, trxs[1].Err, "rpc response: -32009:Slot skipped")
+
+	assert.Equal(t, &TransactionResult{Signature: "unknown"}, trxs[2])
+
+	require.Len(t, *batches, 1)
+	assert.JSONEq(t, `{"encoding":"json","commitment":"finalized","maxSupportedTransactionVersion":0}`, string((*batches)[0][0].Params[1]))
+}
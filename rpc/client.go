@@ -18,8 +18,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
 	"reflect"
@@ -45,25 +48,46 @@ var WithDebug = func() ClientOption {
 	}
 }
 
+// WithMaxBatchSize sets the maximum number of calls sent in a single HTTP
+// request by DoBatch, larger batches are split. A size of 0 or less disables
+// the splitting.
+var WithMaxBatchSize = func(size int) ClientOption {
+	return func(cli *Client) *Client {
+		cli.maxBatchSize = size
+		return cli
+	}
+}
+
 type Client struct {
 	rpcURL             string
-	rpcClient          jsonrpc.RPCClient
+	httpClient         *http.Client
 	headers            http.Header
 	requestIDGenerator func() int
+	maxBatchSize       int
+	retryPolicy        *RetryPolicy
+	rateLimiter        *tokenBucket
 	debug              bool
+
+	// router, when set, performs the calls in place of the client, see
+	// MultiClient.
+	router router
+}
+
+type router interface {
+	doRequest(ctx context.Context, out interface{}, method string, params []interface{}) error
+	doBatch(ctx context.Context, requests []*BatchRequest) error
 }
 
 func NewClient(rpcURL string, opts ...ClientOption) *Client {
 	c := &Client{
 		rpcURL: rpcURL,
-		rpcClient: jsonrpc.NewClientWithOpts(rpcURL, &jsonrpc.RPCClientOpts{
-			HTTPClient: &http.Client{
-				Transport: &withLoggingRoundTripper{
-					defaultLogger: &zlog,
-					tracer:        tracer,
-				}},
-		}),
+		httpClient: &http.Client{
+			Transport: &withLoggingRoundTripper{
+				defaultLogger: &zlog,
+				tracer:        tracer,
+			}},
 		requestIDGenerator: generateRequestID,
+		maxBatchSize:       DefaultMaxBatchSize,
 	}
 
 	for _, opt := range opts {
@@ -80,7 +104,7 @@ func (c *Client) SetHeader(k, v string) {
 	c.headers.Set(k, v)
 }
 
-func (c *Client) SendTransaction(transaction *solana.Transaction, opts *SendTransactionOptions) (signature string, err error) {
+func (c *Client) SendTransaction(ctx context.Context, transaction *solana.Transaction, opts *SendTransactionOptions) (signature string, err error) {
 	buf := new(bytes.Buffer)
 
 	if err := bin.NewEncoder(buf).Encode(transaction); err != nil {
@@ -106,7 +130,7 @@ func (c *Client) SendTransaction(transaction *solana.Transaction, opts *SendTran
 		obj,
 	}
 
-	if err := c.DoRequest(&signature, "sendTransaction", params...); err != nil {
+	if err := c.DoRequest(ctx, &signature, "sendTransaction", params...); err != nil {
 		var rpcError *jsonrpc.RPCError
 		if errors.As(err, &rpcError) {
 			instructionError := fromRPCError(rpcError)
@@ -124,12 +148,19 @@ func (c *Client) SendTransaction(transaction *solana.Transaction, opts *SendTran
 	return
 }
 
-func (c *Client) DoRequest(out interface{}, method string, params ...interface{}) error {
+// DoRequest performs the JSON-RPC call `method`, decoding its result in `out`.
+// The call is aborted when `ctx` is canceled or its deadline expires, and the
+// logger attached to `ctx` through `logging.WithLogger`, if any, is used.
+func (c *Client) DoRequest(ctx context.Context, out interface{}, method string, params ...interface{}) error {
+	if c.router != nil {
+		return c.router.doRequest(ctx, out, method, params)
+	}
+
 	request := jsonrpc.NewRequest(method, params...)
 	request.ID = c.requestIDGenerator()
 
-	logger := zlog.With(zap.Int("id", request.ID), zap.String("method", method))
-	ctx := logging.WithLogger(context.Background(), logger)
+	logger := logging.Logger(ctx, zlog).With(zap.Int("id", request.ID), zap.String("method", method))
+	ctx = logging.WithLogger(ctx, logger)
 
 	fields := []zapcore.Field{}
 	if tracer.Enabled() {
@@ -151,21 +182,96 @@ func (c *Client) DoRequest(out interface{}, method string, params ...interface{}
 		logger.Debug("performed JSON-RPC call", fields...)
 	}()
 
-	_ = ctx
-	rpcResponse, err := c.rpcClient.CallRaw(request)
+	var rpcResponse *jsonrpc.RPCResponse
+	err := c.withRetry(ctx, logger, !NonIdempotentMethods[method], func() (err error) {
+		rpcResponse, err = c.call(ctx, request)
+		if err != nil {
+			return fmt.Errorf("call raw: %w", err)
+		}
+
+		if rpcResponse.Error != nil {
+			return fmt.Errorf("rpc response: %w", rpcResponse.Error)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("call raw: %w", err)
+		return err
 	}
 
-	if rpcResponse.Error != nil {
-		return fmt.Errorf("rpc response: %w", rpcResponse.Error)
+	decodingTime = time.Now()
+	return rpcResponse.GetObject(out)
+}
+
+func (c *Client) call(ctx context.Context, request *jsonrpc.RPCRequest) (*jsonrpc.RPCResponse, error) {
+	var rpcResponse *jsonrpc.RPCResponse
+	if err := c.post(ctx, request.Method+"()", request, &rpcResponse); err != nil {
+		return nil, err
 	}
 
-	return rpcResponse.GetObject(out)
+	if rpcResponse == nil {
+		return nil, fmt.Errorf("rpc call %s() on %s: rpc response missing", request.Method, c.rpcURL)
+	}
+	return rpcResponse, nil
+}
+
+// post sends `payload` as JSON to the RPC endpoint and decodes the response
+// body in `out`, `name` identifies the call in errors.
+func (c *Client) post(ctx context.Context, name string, payload interface{}, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("rpc call %s on %s: encode request: %w", name, c.rpcURL, err)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, "POST", c.rpcURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("rpc call %s on %s: %w", name, c.rpcURL, err)
+	}
+
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Accept", "application/json")
+	for k, v := range c.headers {
+		httpRequest.Header[k] = v
+	}
+
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.Wait(ctx); err != nil {
+			return fmt.Errorf("rpc call %s on %s: rate limit: %w", name, c.rpcURL, err)
+		}
+	}
+
+	httpResponse, err := c.httpClient.Do(httpRequest)
+	if err != nil {
+		return fmt.Errorf("rpc call %s on %s: %w", name, c.rpcURL, err)
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode < 200 || httpResponse.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(httpResponse.Body, 4096))
+		return fmt.Errorf("rpc call %s on %s: %w", name, c.rpcURL, &HTTPError{
+			StatusCode: httpResponse.StatusCode,
+			Status:     httpResponse.Status,
+			RetryAfter: parseRetryAfter(httpResponse.Header.Get("Retry-After"), time.Now()),
+			Body:       body,
+		})
+	}
+
+	decoder := json.NewDecoder(httpResponse.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(out); err != nil {
+		return fmt.Errorf("rpc call %s on %s status code: %d. could not decode body to rpc response: %w", name, c.rpcURL, httpResponse.StatusCode, err)
+	}
+	return nil
 }
 
 var requestCounter = atomic.NewInt64(0)
 
+// configParams returns the params of a call taking only a config object.
+// Given as the single parameter, the object would be sent unwrapped by the
+// JSON-RPC client instead of as the first element of the params array.
+func configParams(obj map[string]interface{}) []interface{} {
+	return []interface{}{[]interface{}{obj}}
+}
+
 func generateRequestID() int {
 	return int(requestCounter.Inc())
 }
@@ -0,0 +1,85 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/streamingfast/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestClient_DoRequest_Headers(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		headers = req.Header
+		rw.Write([]byte(`{"jsonrpc":"2.0","result":42,"id":0}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	client.SetHeader("Authorization", "Bearer token")
+
+	slot, err := client.GetSlot(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), slot)
+	assert.Equal(t, "Bearer token", headers.Get("Authorization"))
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+}
+
+func TestClient_DoRequest_Canceled(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		select {
+		case <-unblock:
+		case <-req.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	client := newTestClient(server.URL)
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := client.GetSlot(ctx, nil)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected deadline exceeded, got %s", err)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+		}()
+
+		_, err := client.GetSlot(ctx, nil)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.Canceled), "expected canceled, got %s", err)
+	})
+}
+
+func TestClient_DoRequest_ContextLogger(t *testing.T) {
+	server, closer := mockJSONRPC(t, map[string]interface{}{"jsonrpc": "2.0", "result": 1, "id": 0})
+	defer closer()
+
+	core, logs := observer.New(zap.DebugLevel)
+	ctx := logging.WithLogger(context.Background(), zap.New(core).With(zap.String("request_scope", "test")))
+
+	_, err := newTestClient(server.URL).GetSlot(ctx, nil)
+	require.NoError(t, err)
+
+	entries := logs.FilterMessage("performing JSON-RPC call").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "test", entries[0].ContextMap()["request_scope"])
+	assert.Equal(t, "getSlot", entries[0].ContextMap()["method"])
+}
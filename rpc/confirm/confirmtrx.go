@@ -12,8 +12,18 @@ import (
 	"github.com/streamingfast/solana-go/rpc/ws"
 )
 
-func SendAndConfirmTransaction(ctx context.Context, rppClient *rpc.Client, wsClient *ws.Client, transaction *solana.Transaction) (signature string, err error) {
+// SendAndConfirmTransaction sends the transaction and waits for it to be
+// finalized. When signers are given, the transaction is signed with them before
+// being sent, otherwise it is expected to already be signed.
+func SendAndConfirmTransaction(ctx context.Context, rppClient *rpc.Client, wsClient *ws.Client, transaction *solana.Transaction, signers ...solana.Signer) (signature string, err error) {
+	if len(signers) > 0 {
+		if _, err := transaction.Sign(signers...); err != nil {
+			return "", fmt.Errorf("unable to sign transaction: %w", err)
+		}
+	}
+
 	sig, err := rppClient.SendTransaction(
+		ctx,
 		transaction,
 		&rpc.SendTransactionOptions{
 			SkipPreflight:       false,
@@ -1,6 +1,9 @@
 package rpc
 
 import (
+	"context"
+	"fmt"
+
 	bin "github.com/streamingfast/binary"
 	"github.com/streamingfast/solana-go"
 )
@@ -18,8 +21,8 @@ type Account struct {
 	RentEpoch  bin.Uint64       `json:"rentEpoch"`
 }
 
-func (c *Client) GetAccountDataIn(account solana.PublicKey, inVar interface{}) (err error) {
-	resp, err := c.GetAccountInfo(account)
+func (c *Client) GetAccountDataIn(ctx context.Context, account solana.PublicKey, inVar interface{}) (err error) {
+	resp, err := c.GetAccountInfo(ctx, account)
 	if err != nil {
 		return err
 	}
@@ -27,13 +30,8 @@ func (c *Client) GetAccountDataIn(account solana.PublicKey, inVar interface{}) (
 	return bin.NewDecoder(resp.Value.Data).Decode(inVar)
 }
 
-func (c *Client) GetAccountInfo(account solana.PublicKey) (out *GetAccountInfoResult, err error) {
-	obj := map[string]interface{}{
-		"encoding": "base64",
-	}
-	params := []interface{}{account, obj}
-
-	err = c.DoRequest(&out, "getAccountInfo", params...)
+func (c *Client) GetAccountInfo(ctx context.Context, account solana.PublicKey) (out *GetAccountInfoResult, err error) {
+	err = c.DoRequest(ctx, &out, "getAccountInfo", getAccountInfoParams(account)...)
 	if err != nil {
 		return nil, err
 	}
@@ -44,3 +42,55 @@ func (c *Client) GetAccountInfo(account solana.PublicKey) (out *GetAccountInfoRe
 
 	return out, nil
 }
+
+// AccountResult is the outcome of fetching one of the accounts of
+// GetAccountInfos, Value being nil when the account does not exist.
+type AccountResult struct {
+	Account solana.PublicKey
+	Value   *Account
+	Err     error
+}
+
+// GetAccountInfos fetches all `accounts` using batched calls, see DoBatch.
+// Results are in the order of `accounts`, the failure to fetch one of them is
+// reported in its result without affecting the others. The returned error is
+// only set when the batch itself failed.
+func (c *Client) GetAccountInfos(ctx context.Context, accounts []solana.PublicKey) ([]*AccountResult, error) {
+	results := make([]*GetAccountInfoResult, len(accounts))
+	requests := make([]*BatchRequest, len(accounts))
+	for i, account := range accounts {
+		requests[i] = NewBatchRequest(&results[i], "getAccountInfo", getAccountInfoParams(account)...)
+	}
+
+	if err := c.DoBatch(ctx, requests); err != nil {
+		return nil, fmt.Errorf("get account infos: %w", err)
+	}
+
+	out := make([]*AccountResult, len(accounts))
+	for i, request := range requests {
+		out[i] = &AccountResult{Account: accounts[i], Err: request.Err}
+		if request.Err == nil && results[i] != nil {
+			out[i].Value = results[i].Value
+		}
+	}
+	return out, nil
+}
+
+func getAccountInfoParams(account solana.PublicKey) []interface{} {
+	obj := map[string]interface{}{
+		"encoding": "base64",
+	}
+	return []interface{}{account, obj}
+}
+
+// Decode decodes the account's data with the decoder registered for its owner
+// in solana.DefaultAccountDecoderRegistry, see DecodeWith.
+func (a *Account) Decode(key solana.PublicKey) (interface{}, error) {
+	return a.DecodeWith(solana.DefaultAccountDecoderRegistry, key)
+}
+
+// DecodeWith decodes the account's data with the decoder registered for its
+// owner in `registry`.
+func (a *Account) DecodeWith(registry *solana.AccountDecoderRegistry, key solana.PublicKey) (interface{}, error) {
+	return registry.DecodeAccount(key, a.Owner, a.Data)
+}
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/streamingfast/solana-go"
 	"github.com/stretchr/testify/assert"
@@ -49,7 +50,7 @@ func TestClient_GetAccountInfo(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			client, cleanup, assertions := test.clientFunc(t)
 			defer cleanup()
-			out, err := client.GetAccountInfo(test.key)
+			out, err := client.GetAccountInfo(context.Background(), test.key)
 			if test.expectError {
 				require.Error(t, err)
 			} else {
@@ -1,22 +1,23 @@
 package rpc
 
 import (
-	bin "github.com/streamingfast/binary"
+	"context"
+
 	"github.com/streamingfast/solana-go"
 )
 
 type GetBalanceResult struct {
 	RPCContext
-	Value bin.Uint64 `json:"value"`
+	Value solana.Lamports `json:"value"`
 }
 
-func (c *Client) GetBalance(publicKey solana.PublicKey, commitment *CommitmentType) (out *GetBalanceResult, err error) {
+func (c *Client) GetBalance(ctx context.Context, publicKey solana.PublicKey, commitment *CommitmentType) (out *GetBalanceResult, err error) {
 	params := []interface{}{publicKey.String()}
 	if commitment != nil {
 		params = append(params, map[string]string{
 			"commitment": string(*commitment),
 		})
 	}
-	err = c.DoRequest(&out, "getBalance", params...)
+	err = c.DoRequest(ctx, &out, "getBalance", params...)
 	return
 }
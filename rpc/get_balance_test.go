@@ -1,8 +1,8 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
-	bin "github.com/streamingfast/binary"
 	"github.com/streamingfast/solana-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -26,7 +26,7 @@ func TestClient_GetBalance(t *testing.T) {
 				return client, closer, func() {}
 			},
 			key:       solana.MustPublicKeyFromBase58("6wrL8rQzDWSH7PJyZRGsdBiNcrpD8Wd6vJzGVBinuCL3"),
-			expectOut: &GetBalanceResult{RPCContext: RPCContext{Context{Slot: 0x7d942fa}}, Value: bin.Uint64(5465913541)},
+			expectOut: &GetBalanceResult{RPCContext: RPCContext{Context{Slot: 0x7d942fa}}, Value: solana.Lamports(5465913541)},
 		},
 		{
 			name: "real json rpc request",
@@ -45,7 +45,7 @@ func TestClient_GetBalance(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			client, cleanup, assertions := test.clientFunc(t)
 			defer cleanup()
-			out, err := client.GetBalance(test.key, nil)
+			out, err := client.GetBalance(context.Background(), test.key, nil)
 			if test.expectError {
 				require.Error(t, err)
 			} else {
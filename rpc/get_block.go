@@ -1,10 +1,45 @@
 package rpc
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
 	bin "github.com/streamingfast/binary"
 	"github.com/streamingfast/solana-go"
 )
 
+const (
+	EncodingJSON       = EncodingType("json")
+	EncodingJSONParsed = EncodingType("jsonParsed")
+)
+
+// TransactionDetailsType is the level of transaction details returned in
+// blocks.
+type TransactionDetailsType string
+
+const (
+	TransactionDetailsFull       = TransactionDetailsType("full")
+	TransactionDetailsSignatures = TransactionDetailsType("signatures")
+	TransactionDetailsAccounts   = TransactionDetailsType("accounts")
+	TransactionDetailsNone       = TransactionDetailsType("none")
+)
+
+type GetBlockOpts struct {
+	// Encoding of the transactions, EncodingJSON by default. EncodingBase64
+	// and EncodingBase58 transactions are decoded in solana.Transaction.
+	Encoding           EncodingType
+	TransactionDetails TransactionDetailsType
+	// Rewards includes the block rewards when set, the node includes them by
+	// default.
+	Rewards    *bool
+	Commitment CommitmentType
+	// MaxSupportedTransactionVersion must be set to 0 to fetch blocks holding
+	// versioned transactions, nodes reject them otherwise.
+	MaxSupportedTransactionVersion *uint64
+}
+
 type GetBlockResult struct {
 	// For blocs below ~ 900K RPC nodes often return nil
 	BlockHeight *bin.Uint64 `json:"blockHeight"`
@@ -13,30 +48,146 @@ type GetBlockResult struct {
 	Blockhash         solana.PublicKey `json:"blockhash"`
 	ParentSlot        bin.Uint64       `json:"parentSlot"`
 	PreviousBlockhash solana.PublicKey `json:"previousBlockhash"`
-	//Transactions      []struct {
-	//	Meta        interface{} `json:"meta"`
-	//	Transaction struct {
-	//		Message struct {
-	//			AccountKeys []string `json:"accountKeys"`
-	//			Header      struct {
-	//				NumReadonlySignedAccounts   int `json:"numReadonlySignedAccounts"`
-	//				NumReadonlyUnsignedAccounts int `json:"numReadonlyUnsignedAccounts"`
-	//				NumRequiredSignatures       int `json:"numRequiredSignatures"`
-	//			} `json:"header"`
-	//			Instructions []struct {
-	//				Accounts       []int  `json:"accounts"`
-	//				Data           string `json:"data"`
-	//				ProgramIdIndex int    `json:"programIdIndex"`
-	//			} `json:"instructions"`
-	//			RecentBlockhash string `json:"recentBlockhash"`
-	//		} `json:"message"`
-	//		Signatures []string `json:"signatures"`
-	//	} `json:"transaction"`
-	//} `json:"transactions"`
-}
-
-func (c *Client) GetBlock(slotNum uint64) (out *GetBlockResult, err error) {
+	// Transactions is set when the transaction details are
+	// TransactionDetailsFull or TransactionDetailsAccounts.
+	Transactions []*BlockTransaction `json:"transactions"`
+	// Signatures is set when the transaction details are
+	// TransactionDetailsSignatures.
+	Signatures []solana.Signature `json:"signatures"`
+	Rewards    []*Reward          `json:"rewards"`
+}
+
+type BlockTransaction struct {
+	Transaction *EncodedTransaction `json:"transaction"`
+	Meta        *Meta               `json:"meta"`
+	// Version is only set when a MaxSupportedTransactionVersion is requested.
+	Version *solana.MessageVersion `json:"version"`
+}
+
+// UnmarshalJSON sets the addresses loaded from the address table lookups of
+// the meta on the decoded `Transaction.Binary` message, so its accounts are
+// resolved like the ones of legacy messages.
+func (t *BlockTransaction) UnmarshalJSON(data []byte) error {
+	type blockTransaction BlockTransaction
+	if err := json.Unmarshal(data, (*blockTransaction)(t)); err != nil {
+		return err
+	}
+
+	if t.Transaction != nil && t.Transaction.Binary != nil && t.Meta != nil && t.Meta.LoadedAddresses != nil {
+		t.Transaction.Binary.Message.SetLoadedAddresses(*t.Meta.LoadedAddresses)
+	}
+	return nil
+}
+
+// EncodedTransaction is a transaction in one of the encodings of
+// GetBlockOpts. The transaction is decoded in `Binary` for the base64 and
+// base58 encodings, in `JSON` for the json encoding. Other representations
+// (jsonParsed encoding, accounts transaction details) are only available in
+// `Raw`.
+type EncodedTransaction struct {
+	Raw    json.RawMessage
+	Binary *solana.Transaction
+	JSON   *Transaction
+}
+
+func (t *EncodedTransaction) UnmarshalJSON(data []byte) error {
+	t.Raw = append(json.RawMessage(nil), data...)
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil
+	}
+
+	if trimmed[0] == '[' {
+		var raw solana.Data
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return fmt.Errorf("encoded transaction: %w", err)
+		}
+
+		trx, err := solana.TransactionFromData(raw)
+		if err != nil {
+			return fmt.Errorf("encoded transaction: %w", err)
+		}
+		t.Binary = trx
+		return nil
+	}
+
+	// The jsonParsed encoding has account objects instead of keys, the
+	// accounts transaction details have no message.
+	var peek struct {
+		Message *struct {
+			AccountKeys []json.RawMessage `json:"accountKeys"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(trimmed, &peek); err != nil {
+		return fmt.Errorf("encoded transaction: %w", err)
+	}
+	if peek.Message == nil || (len(peek.Message.AccountKeys) > 0 && peek.Message.AccountKeys[0][0] != '"') {
+		return nil
+	}
+
+	if err := json.Unmarshal(trimmed, &t.JSON); err != nil {
+		return fmt.Errorf("encoded transaction: %w", err)
+	}
+	return nil
+}
+
+func (t EncodedTransaction) MarshalJSON() ([]byte, error) {
+	if len(t.Raw) == 0 {
+		return []byte("null"), nil
+	}
+	return t.Raw, nil
+}
+
+// RewardType is the kind of a reward, empty for older blocks.
+type RewardType string
+
+const (
+	RewardTypeFee     = RewardType("fee")
+	RewardTypeRent    = RewardType("rent")
+	RewardTypeStaking = RewardType("staking")
+	RewardTypeVoting  = RewardType("voting")
+)
+
+type Reward struct {
+	Pubkey solana.PublicKey `json:"pubkey"`
+	// Lamports credited, or debited when negative, to the account.
+	Lamports    bin.Int64  `json:"lamports"`
+	PostBalance bin.Uint64 `json:"postBalance"`
+	RewardType  RewardType `json:"rewardType"`
+	// Commission of vote accounts when the reward was credited, only set for
+	// voting and staking rewards.
+	Commission *uint8 `json:"commission"`
+}
+
+func (c *Client) GetBlock(ctx context.Context, slotNum uint64) (out *GetBlockResult, err error) {
+	return c.GetBlockWithOpts(ctx, slotNum, nil)
+}
+
+// GetBlockWithOpts returns the block at `slotNum`, the node's defaults apply
+// to the unset `opts`.
+func (c *Client) GetBlockWithOpts(ctx context.Context, slotNum uint64, opts *GetBlockOpts) (out *GetBlockResult, err error) {
 	params := []interface{}{slotNum}
-	err = c.DoRequest(&out, "getBlock", params)
+	if opts != nil {
+		obj := map[string]interface{}{}
+		if opts.Encoding != "" {
+			obj["encoding"] = opts.Encoding
+		}
+		if opts.TransactionDetails != "" {
+			obj["transactionDetails"] = opts.TransactionDetails
+		}
+		if opts.Rewards != nil {
+			obj["rewards"] = *opts.Rewards
+		}
+		if opts.Commitment != "" {
+			obj["commitment"] = opts.Commitment
+		}
+		if opts.MaxSupportedTransactionVersion != nil {
+			obj["maxSupportedTransactionVersion"] = *opts.MaxSupportedTransactionVersion
+		}
+		params = append(params, obj)
+	}
+
+	err = c.DoRequest(ctx, &out, "getBlock", params...)
 	return
 }
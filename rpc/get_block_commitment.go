@@ -0,0 +1,21 @@
+package rpc
+
+import (
+	"context"
+
+	bin "github.com/streamingfast/binary"
+)
+
+type GetBlockCommitmentResult struct {
+	// Commitment holds the amount of cluster stake in lamports that has voted
+	// on the block at each depth from 0 to MAX_LOCKOUT_HISTORY, nil for an
+	// unknown block.
+	Commitment []bin.Uint64 `json:"commitment"`
+	// TotalStake is the total active stake of the current epoch in lamports.
+	TotalStake bin.Uint64 `json:"totalStake"`
+}
+
+func (c *Client) GetBlockCommitment(ctx context.Context, slot uint64) (out *GetBlockCommitmentResult, err error) {
+	err = c.DoRequest(ctx, &out, "getBlockCommitment", slot)
+	return
+}
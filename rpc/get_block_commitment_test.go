@@ -0,0 +1,32 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	bin "github.com/streamingfast/binary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetBlockCommitment(t *testing.T) {
+	server, closer := mockJSONRPC(t, json.RawMessage(`{"jsonrpc":"2.0","result":{"commitment":[0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,10,32],"totalStake":42},"id":0}`))
+	defer closer()
+
+	out, err := newTestClient(server.URL).GetBlockCommitment(context.Background(), 5)
+	require.NoError(t, err)
+	require.Len(t, out.Commitment, 33)
+	assert.Equal(t, bin.Uint64(10), out.Commitment[31])
+	assert.Equal(t, bin.Uint64(42), out.TotalStake)
+	assert.Equal(t, map[string]interface{}{"id": float64(0), "jsonrpc": "2.0", "method": "getBlockCommitment", "params": []interface{}{float64(5)}}, server.RequestBody(t))
+}
+
+func TestClient_GetBlockCommitment_Unknown(t *testing.T) {
+	server, closer := mockJSONRPC(t, json.RawMessage(`{"jsonrpc":"2.0","result":{"commitment":null,"totalStake":42},"id":0}`))
+	defer closer()
+
+	out, err := newTestClient(server.URL).GetBlockCommitment(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Nil(t, out.Commitment)
+}
@@ -0,0 +1,21 @@
+package rpc
+
+import (
+	"context"
+
+	bin "github.com/streamingfast/binary"
+)
+
+func (c *Client) GetBlockHeight(ctx context.Context, commitment *CommitmentType) (uint64, error) {
+	var params []interface{}
+	if commitment != nil {
+		params = configParams(map[string]interface{}{"commitment": *commitment})
+	}
+
+	var out bin.Uint64
+	err := c.DoRequest(ctx, &out, "getBlockHeight", params...)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(out), nil
+}
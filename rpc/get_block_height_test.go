@@ -0,0 +1,23 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetBlockHeight(t *testing.T) {
+	server, closer := mockJSONRPC(t, json.RawMessage(`{"jsonrpc":"2.0","result":1233,"id":0}`))
+	defer closer()
+
+	commitment := CommitmentFinalized
+	out, err := newTestClient(server.URL).GetBlockHeight(context.Background(), &commitment)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1233), out)
+	assert.Equal(t, map[string]interface{}{"id": float64(0), "jsonrpc": "2.0", "method": "getBlockHeight", "params": []interface{}{
+		map[string]interface{}{"commitment": "finalized"},
+	}}, server.RequestBody(t))
+}
@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	bin "github.com/streamingfast/binary"
+	"github.com/streamingfast/solana-go"
+)
+
+type GetBlockProductionOpts struct {
+	Commitment CommitmentType
+	// Identity only returns the production of this validator identity.
+	Identity *solana.PublicKey
+	// Range defaults to the current epoch.
+	Range *SlotRange
+}
+
+type SlotRange struct {
+	FirstSlot bin.Uint64 `json:"firstSlot"`
+	// LastSlot defaults to the highest slot when nil.
+	LastSlot *bin.Uint64 `json:"lastSlot,omitempty"`
+}
+
+type GetBlockProductionResult struct {
+	RPCContext
+	Value BlockProductionResult `json:"value"`
+}
+
+type BlockProductionResult struct {
+	// ByIdentity is keyed by validator identity, base58 encoded.
+	ByIdentity map[string]BlockProduction `json:"byIdentity"`
+	Range      SlotRange                  `json:"range"`
+}
+
+// BlockProduction is the number of leader slots of a validator and the number
+// of blocks it produced in them.
+type BlockProduction struct {
+	LeaderSlots    uint64
+	BlocksProduced uint64
+}
+
+func (p *BlockProduction) UnmarshalJSON(data []byte) error {
+	var values []uint64
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	if len(values) != 2 {
+		return fmt.Errorf("invalid block production, expected 2 values, found %d", len(values))
+	}
+
+	p.LeaderSlots, p.BlocksProduced = values[0], values[1]
+	return nil
+}
+
+func (p BlockProduction) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]uint64{p.LeaderSlots, p.BlocksProduced})
+}
+
+func (c *Client) GetBlockProduction(ctx context.Context, opts *GetBlockProductionOpts) (out *GetBlockProductionResult, err error) {
+	var params []interface{}
+	if opts != nil {
+		obj := map[string]interface{}{}
+		if opts.Commitment != "" {
+			obj["commitment"] = opts.Commitment
+		}
+		if opts.Identity != nil {
+			obj["identity"] = opts.Identity.String()
+		}
+		if opts.Range != nil {
+			obj["range"] = opts.Range
+		}
+		params = configParams(obj)
+	}
+
+	err = c.DoRequest(ctx, &out, "getBlockProduction", params...)
+	return
+}
@@ -0,0 +1,38 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetBlockProduction(t *testing.T) {
+	server, closer := mockJSONRPC(t, json.RawMessage(`{"jsonrpc":"2.0","result":{"context":{"slot":9887},"value":{"byIdentity":{"85iYT5RuzRTDgjyRa3cP8SYhM2j21fj7NhfJ3peu1DPr":[9888,9886]},"range":{"firstSlot":0,"lastSlot":9887}}},"id":0}`))
+	defer closer()
+
+	identity := solana.MustPublicKeyFromBase58("85iYT5RuzRTDgjyRa3cP8SYhM2j21fj7NhfJ3peu1DPr")
+	out, err := newTestClient(server.URL).GetBlockProduction(context.Background(), &GetBlockProductionOpts{
+		Commitment: CommitmentConfirmed,
+		Identity:   &identity,
+		Range:      &SlotRange{FirstSlot: 0, LastSlot: puint64(9887)},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{"id": float64(0), "jsonrpc": "2.0", "method": "getBlockProduction", "params": []interface{}{
+		map[string]interface{}{
+			"commitment": "confirmed",
+			"identity":   "85iYT5RuzRTDgjyRa3cP8SYhM2j21fj7NhfJ3peu1DPr",
+			"range":      map[string]interface{}{"firstSlot": float64(0), "lastSlot": float64(9887)},
+		},
+	}}, server.RequestBody(t))
+
+	assert.Equal(t, uint64(9887), uint64(out.Context.Slot))
+	assert.Equal(t, map[string]BlockProduction{
+		"85iYT5RuzRTDgjyRa3cP8SYhM2j21fj7NhfJ3peu1DPr": {LeaderSlots: 9888, BlocksProduced: 9886},
+	}, out.Value.ByIdentity)
+	assert.Equal(t, SlotRange{FirstSlot: 0, LastSlot: puint64(9887)}, out.Value.Range)
+}
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/streamingfast/solana-go"
 	"github.com/stretchr/testify/assert"
@@ -11,11 +12,12 @@ import (
 
 func TestClient_GetBlock(t *testing.T) {
 	tests := []struct {
-		name        string
-		clientFunc  func(t *testing.T) (*Client, func(), func())
-		slotNum     uint64
-		expectError bool
-		expectOut   interface{}
+		name             string
+		clientFunc       func(t *testing.T) (*Client, func(), func())
+		slotNum          uint64
+		expectError      bool
+		expectOut        *GetBlockResult
+		expectSignatures []string
 	}{
 		{
 			name: "mock json rpc request",
@@ -33,7 +35,12 @@ func TestClient_GetBlock(t *testing.T) {
 				Blockhash:         solana.MustPublicKeyFromBase58("DUCT8VSgk2BXkMhQfxKVYfikEZCQf4dZ4ioPdGdaVxMN"),
 				ParentSlot:        429,
 				PreviousBlockhash: solana.MustPublicKeyFromBase58("HA2fJgGqmQezCXJRVNZAWPbRMXCPjUyo7VjRF47JGdYs"),
-				//Transactions:      nil,
+			},
+			expectSignatures: []string{
+				"MsdZAVaCjHcVWs8zMJinXvntufdXwtHJWCRLSyw9zeAZuNDec6s41H12KFFyPHbq3uj98wRjMa86z6nW2kUv1Zs",
+				"RetEYHPRuKmR5qyXqWxSusma3PEqeYdteSahAkasiMicsq312toEddsc16jQdPr1NFGkPvnQ9MkmossLTsEmeXM",
+				"5iwUiSfvaiD2JEeHZKg72wMBcUatg8tW7qSh3ryHqTYuAprpskVZLbfKqPrQ7VMjEVj9oLAGW84JHNKJ99TNBpdV",
+				"5Vf9ppLf9saAwEoAxfo3TJfuUbE7LyC7nBNgM9iovjSTt8SSMYsJjxhvvmSRsMjans5SqGXxuT1xbnm8MuK6kcSp",
 			},
 		},
 		{
@@ -53,12 +60,20 @@ func TestClient_GetBlock(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			client, cleanup, assertions := test.clientFunc(t)
 			defer cleanup()
-			out, err := client.GetBlock(test.slotNum)
+			out, err := client.GetBlock(context.Background(), test.slotNum)
 			if test.expectError {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
 				if !isNil(test.expectOut) {
+					var signatures []string
+					for _, trx := range out.Transactions {
+						require.NotNil(t, trx.Transaction.JSON)
+						signatures = append(signatures, trx.Transaction.JSON.Signatures...)
+					}
+					assert.Equal(t, test.expectSignatures, signatures)
+
+					out.Transactions = nil
 					assert.Equal(t, test.expectOut, out)
 				}
 				assertions()
@@ -66,3 +81,197 @@ func TestClient_GetBlock(t *testing.T) {
 		})
 	}
 }
+
+type testInstruction struct {
+	accounts  []*solana.AccountMeta
+	programID solana.PublicKey
+	data      []byte
+}
+
+func (i *testInstruction) Accounts() []*solana.AccountMeta { return i.accounts }
+func (i *testInstruction) ProgramID() solana.PublicKey     { return i.programID }
+func (i *testInstruction) Data() ([]byte, error)           { return i.data, nil }
+
+func TestClient_GetBlockWithOpts(t *testing.T) {
+	_, payer, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+
+	trx, err := solana.NewTransaction([]solana.Instruction{
+		&testInstruction{
+			accounts:  []*solana.AccountMeta{{PublicKey: payer.PublicKey(), IsSigner: true, IsWritable: true}},
+			programID: solana.MustPublicKeyFromBase58("11111111111111111111111111111111"),
+			data:      []byte{1, 2, 3},
+		},
+	}, solana.MustPublicKeyFromBase58("HA2fJgGqmQezCXJRVNZAWPbRMXCPjUyo7VjRF47JGdYs"))
+	require.NoError(t, err)
+	_, err = trx.Sign(payer)
+	require.NoError(t, err)
+
+	encoded, err := trx.ToBase64()
+	require.NoError(t, err)
+
+	server, closer := mockJSONRPC(t, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      0,
+		"result": map[string]interface{}{
+			"blockHeight":       80300230,
+			"blockTime":         nil,
+			"blockhash":         "DUCT8VSgk2BXkMhQfxKVYfikEZCQf4dZ4ioPdGdaVxMN",
+			"parentSlot":        429,
+			"previousBlockhash": "HA2fJgGqmQezCXJRVNZAWPbRMXCPjUyo7VjRF47JGdYs",
+			"rewards": []interface{}{
+				map[string]interface{}{"commission": nil, "lamports": 5000, "postBalance": 499999845000, "pubkey": "GdnSyH3YtwcxFvQrVVJMm1JhTS4QVX7MFsX56uJLUfiZ", "rewardType": "fee"},
+			},
+			"transactions": []interface{}{
+				map[string]interface{}{
+					"transaction": []string{encoded, "base64"},
+					"version":     0,
+					"meta": map[string]interface{}{
+						"err":                  "AccountInUse",
+						"fee":                  5000,
+						"preBalances":          []int{10000},
+						"postBalances":         []int{5000},
+						"computeUnitsConsumed": 150,
+						"loadedAddresses": map[string]interface{}{
+							"writable": []string{"sCtiJieP8B3SwYnXemiLpRFRR8KJLMtsMVN25fAFWjW"},
+							"readonly": []string{},
+						},
+						"rewards": []interface{}{
+							map[string]interface{}{"commission": 10, "lamports": -20, "postBalance": 100, "pubkey": "GdnSyH3YtwcxFvQrVVJMm1JhTS4QVX7MFsX56uJLUfiZ", "rewardType": "voting"},
+						},
+					},
+				},
+			},
+		},
+	})
+	defer closer()
+
+	rewards := true
+	maxVersion := uint64(0)
+	out, err := newTestClient(server.URL).GetBlockWithOpts(context.Background(), 430, &GetBlockOpts{
+		Encoding:                       EncodingBase64,
+		TransactionDetails:             TransactionDetailsFull,
+		Rewards:                        &rewards,
+		Commitment:                     CommitmentConfirmed,
+		MaxSupportedTransactionVersion: &maxVersion,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []interface{}{float64(430), map[string]interface{}{
+		"encoding":                       "base64",
+		"transactionDetails":             "full",
+		"rewards":                        true,
+		"commitment":                     "confirmed",
+		"maxSupportedTransactionVersion": float64(0),
+	}}, server.RequestBody(t)["params"])
+
+	require.Len(t, out.Rewards, 1)
+	assert.Equal(t, &Reward{
+		Pubkey:      solana.MustPublicKeyFromBase58("GdnSyH3YtwcxFvQrVVJMm1JhTS4QVX7MFsX56uJLUfiZ"),
+		Lamports:    5000,
+		PostBalance: 499999845000,
+		RewardType:  RewardTypeFee,
+	}, out.Rewards[0])
+
+	require.Len(t, out.Transactions, 1)
+	blockTrx := out.Transactions[0]
+
+	require.NotNil(t, blockTrx.Version)
+	assert.Equal(t, solana.MessageVersionV0, *blockTrx.Version)
+
+	require.NotNil(t, blockTrx.Transaction.Binary)
+	assert.Nil(t, blockTrx.Transaction.JSON)
+	assert.Equal(t, trx.Signatures, blockTrx.Transaction.Binary.Signatures)
+	require.NoError(t, blockTrx.Transaction.Binary.VerifySignatures())
+
+	meta := blockTrx.Meta
+	require.NotNil(t, meta)
+	assert.Equal(t, "AccountInUse", meta.Err.Type)
+	assert.Equal(t, uint64(150), uint64(*meta.ComputeUnitsConsumed))
+	assert.Equal(t, &solana.LoadedAddresses{
+		Writable: []solana.PublicKey{solana.MustPublicKeyFromBase58("sCtiJieP8B3SwYnXemiLpRFRR8KJLMtsMVN25fAFWjW")},
+		Readonly: []solana.PublicKey{},
+	}, meta.LoadedAddresses)
+	require.Len(t, meta.Rewards, 1)
+	assert.Equal(t, int64(-20), int64(meta.Rewards[0].Lamports))
+	assert.Equal(t, uint8(10), *meta.Rewards[0].Commission)
+	assert.Equal(t, RewardTypeVoting, meta.Rewards[0].RewardType)
+}
+
+func TestBlockTransaction_UnmarshalJSON_LoadedAddresses(t *testing.T) {
+	payer := solana.PublicKey{0x01}
+	program := solana.PublicKey{0x02}
+	writable := solana.PublicKey{0x10}
+	readonly := solana.PublicKey{0x11}
+
+	trx := &solana.Transaction{
+		Signatures: []solana.Signature{{}},
+		Message: solana.Message{
+			Version:     solana.MessageVersionV0,
+			Header:      solana.MessageHeader{NumRequiredSignatures: 1, NumReadonlyUnsignedAccounts: 1},
+			AccountKeys: []solana.PublicKey{payer, program},
+			Instructions: []solana.CompiledInstruction{
+				{ProgramIDIndex: 1, AccountCount: 3, Accounts: []uint8{0, 2, 3}},
+			},
+			AddressTableLookups: []solana.MessageAddressTableLookup{
+				{AccountKey: solana.PublicKey{0x03}, WritableIndexes: []uint8{4}, ReadonlyIndexes: []uint8{7}},
+			},
+		},
+	}
+	encoded, err := trx.ToBase64()
+	require.NoError(t, err)
+
+	var out BlockTransaction
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"transaction": ["`+encoded+`", "base64"],
+		"version": 0,
+		"meta": {
+			"fee": 5000,
+			"preBalances": [10000, 1, 1, 1],
+			"postBalances": [5000, 1, 1, 1],
+			"loadedAddresses": {"writable": ["`+writable.String()+`"], "readonly": ["`+readonly.String()+`"]}
+		}
+	}`), &out))
+
+	message := &out.Transaction.Binary.Message
+	assert.Equal(t, []solana.PublicKey{payer, program, writable, readonly}, message.AllAccountKeys())
+	assert.True(t, message.IsWritable(writable))
+	assert.False(t, message.IsWritable(readonly))
+
+	accounts, err := message.Instructions[0].ResolveInstructionAccounts(message)
+	require.NoError(t, err)
+	assert.Equal(t, []*solana.AccountMeta{
+		{PublicKey: payer, IsSigner: true, IsWritable: true},
+		{PublicKey: writable, IsWritable: true},
+		{PublicKey: readonly},
+	}, accounts)
+}
+
+func TestEncodedTransaction_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         string
+		expectJSON bool
+	}{
+		{"json", `{"message":{"accountKeys":["GdnSyH3YtwcxFvQrVVJMm1JhTS4QVX7MFsX56uJLUfiZ"],"header":{"numReadonlySignedAccounts":0,"numReadonlyUnsignedAccounts":0,"numRequiredSignatures":1},"instructions":[],"recentBlockhash":"HA2fJgGqmQezCXJRVNZAWPbRMXCPjUyo7VjRF47JGdYs"},"signatures":["MsdZAVaCjHcVWs8zMJinXvntufdXwtHJWCRLSyw9zeAZuNDec6s41H12KFFyPHbq3uj98wRjMa86z6nW2kUv1Zs"]}`, true},
+		{"json parsed", `{"message":{"accountKeys":[{"pubkey":"GdnSyH3YtwcxFvQrVVJMm1JhTS4QVX7MFsX56uJLUfiZ","signer":true,"source":"transaction","writable":true}],"instructions":[{"parsed":{"type":"transfer"},"program":"system","programId":"11111111111111111111111111111111"}],"recentBlockhash":"HA2fJgGqmQezCXJRVNZAWPbRMXCPjUyo7VjRF47JGdYs"},"signatures":["MsdZAVaCjHcVWs8zMJinXvntufdXwtHJWCRLSyw9zeAZuNDec6s41H12KFFyPHbq3uj98wRjMa86z6nW2kUv1Zs"]}`, false},
+		{"accounts details", `{"accountKeys":[{"pubkey":"GdnSyH3YtwcxFvQrVVJMm1JhTS4QVX7MFsX56uJLUfiZ","signer":true,"source":"transaction","writable":true}],"signatures":["MsdZAVaCjHcVWs8zMJinXvntufdXwtHJWCRLSyw9zeAZuNDec6s41H12KFFyPHbq3uj98wRjMa86z6nW2kUv1Zs"]}`, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var trx EncodedTransaction
+			require.NoError(t, json.Unmarshal([]byte(test.in), &trx))
+			assert.Nil(t, trx.Binary)
+			assert.Equal(t, test.expectJSON, trx.JSON != nil)
+			assert.JSONEq(t, test.in, string(trx.Raw))
+
+			if test.expectJSON {
+				assert.Equal(t, []string{"MsdZAVaCjHcVWs8zMJinXvntufdXwtHJWCRLSyw9zeAZuNDec6s41H12KFFyPHbq3uj98wRjMa86z6nW2kUv1Zs"}, trx.JSON.Signatures)
+			}
+		})
+	}
+
+	var trx EncodedTransaction
+	assert.Error(t, json.Unmarshal([]byte(`["invalid","base64"]`), &trx))
+}
@@ -0,0 +1,22 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	bin "github.com/streamingfast/binary"
+)
+
+// GetBlockTime returns the estimated production time of the block at `slot`,
+// ErrNotFound when the node has no time for it.
+func (c *Client) GetBlockTime(ctx context.Context, slot uint64) (time.Time, error) {
+	var out *bin.Int64
+	if err := c.DoRequest(ctx, &out, "getBlockTime", slot); err != nil {
+		return time.Time{}, err
+	}
+
+	if out == nil {
+		return time.Time{}, ErrNotFound
+	}
+	return time.Unix(int64(*out), 0), nil
+}
@@ -0,0 +1,29 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetBlockTime(t *testing.T) {
+	server, closer := mockJSONRPC(t, json.RawMessage(`{"jsonrpc":"2.0","result":1574721591,"id":0}`))
+	defer closer()
+
+	out, err := newTestClient(server.URL).GetBlockTime(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1574721591, 0), out)
+	assert.Equal(t, map[string]interface{}{"id": float64(0), "jsonrpc": "2.0", "method": "getBlockTime", "params": []interface{}{float64(5)}}, server.RequestBody(t))
+}
+
+func TestClient_GetBlockTime_NotFound(t *testing.T) {
+	server, closer := mockJSONRPC(t, json.RawMessage(`{"jsonrpc":"2.0","result":null,"id":0}`))
+	defer closer()
+
+	_, err := newTestClient(server.URL).GetBlockTime(context.Background(), 5)
+	assert.Equal(t, ErrNotFound, err)
+}
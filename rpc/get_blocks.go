@@ -0,0 +1,38 @@
+package rpc
+
+import (
+	"context"
+)
+
+// MaxGetBlocksRange is the maximum number of slots between the start and end
+// slots of a `getBlocks` call.
+const MaxGetBlocksRange = 500_000
+
+// GetBlocks returns the confirmed blocks between `startSlot` and `endSlot`
+// included, up to the latest confirmed block when `endSlot` is nil. Only
+// CommitmentConfirmed and CommitmentFinalized (default) are supported.
+func (c *Client) GetBlocks(ctx context.Context, startSlot uint64, endSlot *uint64, commitment CommitmentType) (out []uint64, err error) {
+	params := []interface{}{startSlot}
+	if endSlot != nil {
+		params = append(params, *endSlot)
+	}
+	if commitment != "" {
+		params = append(params, map[string]interface{}{"commitment": commitment})
+	}
+
+	err = c.DoRequest(ctx, &out, "getBlocks", params...)
+	return
+}
+
+// GetBlocksWithLimit returns at most `limit` confirmed blocks starting at
+// `startSlot`. Only CommitmentConfirmed and CommitmentFinalized (default) are
+// supported.
+func (c *Client) GetBlocksWithLimit(ctx context.Context, startSlot uint64, limit uint64, commitment CommitmentType) (out []uint64, err error) {
+	params := []interface{}{startSlot, limit}
+	if commitment != "" {
+		params = append(params, map[string]interface{}{"commitment": commitment})
+	}
+
+	err = c.DoRequest(ctx, &out, "getBlocksWithLimit", params...)
+	return
+}
@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetBlocks(t *testing.T) {
+	server, closer := mockJSONRPC(t, json.RawMessage(`{"jsonrpc":"2.0","result":[5,6,7,8,10],"id":0}`))
+	defer closer()
+
+	client := newTestClient(server.URL)
+
+	endSlot := uint64(10)
+	out, err := client.GetBlocks(context.Background(), 5, &endSlot, CommitmentConfirmed)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{5, 6, 7, 8, 10}, out)
+	assert.Equal(t, map[string]interface{}{"id": float64(0), "jsonrpc": "2.0", "method": "getBlocks", "params": []interface{}{
+		float64(5), float64(10), map[string]interface{}{"commitment": "confirmed"},
+	}}, server.RequestBody(t))
+
+	_, err = client.GetBlocks(context.Background(), 5, nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{float64(5)}, server.RequestBody(t)["params"])
+}
+
+func TestClient_GetBlocksWithLimit(t *testing.T) {
+	server, closer := mockJSONRPC(t, json.RawMessage(`{"jsonrpc":"2.0","result":[5,6,7],"id":0}`))
+	defer closer()
+
+	out, err := newTestClient(server.URL).GetBlocksWithLimit(context.Background(), 5, 3, "")
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{5, 6, 7}, out)
+	assert.Equal(t, map[string]interface{}{"id": float64(0), "jsonrpc": "2.0", "method": "getBlocksWithLimit", "params": []interface{}{
+		float64(5), float64(3),
+	}}, server.RequestBody(t))
+}
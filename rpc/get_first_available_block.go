@@ -0,0 +1,18 @@
+package rpc
+
+import (
+	"context"
+
+	bin "github.com/streamingfast/binary"
+)
+
+// GetFirstAvailableBlock returns the slot of the lowest confirmed block that
+// has not been purged from the node's ledger.
+func (c *Client) GetFirstAvailableBlock(ctx context.Context) (uint64, error) {
+	var out bin.Uint64
+	err := c.DoRequest(ctx, &out, "getFirstAvailableBlock")
+	if err != nil {
+		return 0, err
+	}
+	return uint64(out), nil
+}
@@ -0,0 +1,20 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetFirstAvailableBlock(t *testing.T) {
+	server, closer := mockJSONRPC(t, json.RawMessage(`{"jsonrpc":"2.0","result":250000,"id":0}`))
+	defer closer()
+
+	out, err := newTestClient(server.URL).GetFirstAvailableBlock(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(250000), out)
+	assert.Equal(t, map[string]interface{}{"id": float64(0), "jsonrpc": "2.0", "method": "getFirstAvailableBlock"}, server.RequestBody(t))
+}
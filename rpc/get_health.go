@@ -0,0 +1,12 @@
+package rpc
+
+import (
+	"context"
+)
+
+// GetHealth returns "ok" when the node is healthy, an error with code
+// RPCErrorCodeNodeUnhealthy otherwise.
+func (c *Client) GetHealth(ctx context.Context) (out string, err error) {
+	err = c.DoRequest(ctx, &out, "getHealth")
+	return
+}
@@ -1,6 +1,8 @@
 package rpc
 
 import (
+	"context"
+
 	bin "github.com/streamingfast/binary"
 	"github.com/streamingfast/solana-go"
 )
@@ -15,10 +17,10 @@ type BlockhashResult struct {
 	LastValidBlockHeight bin.Uint64       `json:"lastValidBlockHeight"`
 }
 
-func (c *Client) GetLatestBlockhash(commitment CommitmentType) (out *GetRecentBlockhashResult, err error) {
+func (c *Client) GetLatestBlockhash(ctx context.Context, commitment CommitmentType) (out *GetRecentBlockhashResult, err error) {
 	params := []interface{}{map[string]string{
 		"commitment": string(commitment),
 	}}
-	err = c.DoRequest(&out, "getLatestBlockhash", params)
+	err = c.DoRequest(ctx, &out, "getLatestBlockhash", params)
 	return
 }
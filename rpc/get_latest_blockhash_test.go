@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/streamingfast/solana-go"
 	"github.com/stretchr/testify/assert"
@@ -52,7 +53,7 @@ func TestClient_GetLatestBlockHash(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			client, cleanup, assertions := test.clientFunc(t)
 			defer cleanup()
-			out, err := client.GetLatestBlockhash(test.commitment)
+			out, err := client.GetLatestBlockhash(context.Background(), test.commitment)
 			if test.expectError {
 				require.Error(t, err)
 			} else {
@@ -1,7 +1,9 @@
 package rpc
 
-func (c *Client) GetMinimumBalanceForRentExemption(dataSize int) (lamport int, err error) {
+import "context"
+
+func (c *Client) GetMinimumBalanceForRentExemption(ctx context.Context, dataSize int) (lamport int, err error) {
 	params := []interface{}{dataSize}
-	err = c.DoRequest(&lamport, "getMinimumBalanceForRentExemption", params...)
+	err = c.DoRequest(ctx, &lamport, "getMinimumBalanceForRentExemption", params...)
 	return
 }
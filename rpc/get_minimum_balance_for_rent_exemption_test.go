@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,7 +48,7 @@ func TestClient_GetMinimumBalanceForRentExemption(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			client, cleanup, assertions := test.clientFunc(t)
 			defer cleanup()
-			out, err := client.GetMinimumBalanceForRentExemption(test.dataSize)
+			out, err := client.GetMinimumBalanceForRentExemption(context.Background(), test.dataSize)
 			if test.expectError {
 				require.Error(t, err)
 			} else {
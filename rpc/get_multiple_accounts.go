@@ -0,0 +1,184 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	bin "github.com/streamingfast/binary"
+	"github.com/streamingfast/solana-go"
+)
+
+// MaxMultipleAccounts is the maximum number of accounts nodes accept in a
+// single `getMultipleAccounts` call.
+const MaxMultipleAccounts = 100
+
+// DefaultMultipleAccountsConcurrency is the number of `getMultipleAccounts`
+// calls performed concurrently by GetMultipleAccounts unless configured in
+// its options.
+const DefaultMultipleAccountsConcurrency = 4
+
+// EncodingType is the encoding of account data returned by the node.
+type EncodingType string
+
+const (
+	EncodingBase64     = EncodingType("base64")
+	EncodingBase58     = EncodingType("base58")
+	EncodingBase64Zstd = EncodingType("base64+zstd")
+)
+
+// DataSlice limits the returned account data to `Length` bytes starting at
+// `Offset`.
+type DataSlice struct {
+	Offset uint64 `json:"offset"`
+	Length uint64 `json:"length"`
+}
+
+type GetMultipleAccountsOpts struct {
+	Commitment CommitmentType
+	// Encoding of the data sent by the node, base64 by default. The data is
+	// always decoded in the returned accounts.
+	Encoding       EncodingType
+	DataSlice      *DataSlice
+	MinContextSlot *uint64
+
+	// Concurrency is the maximum number of calls performed at once when more
+	// than MaxMultipleAccounts accounts are requested, it's not sent to the
+	// node.
+	Concurrency int
+}
+
+type GetMultipleAccountsResult struct {
+	RPCContext
+	// Value holds the accounts in the order they were requested, an account
+	// that does not exist is nil.
+	Value []*Account `json:"value"`
+}
+
+// GetMultipleAccounts fetches `accounts`, split in calls of at most
+// MaxMultipleAccounts accounts performed concurrently. The context of the
+// result is the one of the call that observed the lowest slot.
+func (c *Client) GetMultipleAccounts(ctx context.Context, accounts []solana.PublicKey, opts *GetMultipleAccountsOpts) (*GetMultipleAccountsResult, error) {
+	obj := map[string]interface{}{
+		"encoding": EncodingBase64,
+	}
+	concurrency := DefaultMultipleAccountsConcurrency
+	if opts != nil {
+		if opts.Commitment != "" {
+			obj["commitment"] = opts.Commitment
+		}
+		if opts.Encoding != "" {
+			obj["encoding"] = opts.Encoding
+		}
+		if opts.DataSlice != nil {
+			obj["dataSlice"] = opts.DataSlice
+		}
+		if opts.MinContextSlot != nil {
+			obj["minContextSlot"] = *opts.MinContextSlot
+		}
+		if opts.Concurrency > 0 {
+			concurrency = opts.Concurrency
+		}
+	}
+
+	out := &GetMultipleAccountsResult{Value: make([]*Account, len(accounts))}
+	if len(accounts) == 0 {
+		return out, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var lock sync.Mutex
+	var firstErr error
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+
+	for start := 0; start < len(accounts); start += MaxMultipleAccounts {
+		end := start + MaxMultipleAccounts
+		if end > len(accounts) {
+			end = len(accounts)
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			var result *GetMultipleAccountsResult
+			err := c.DoRequest(ctx, &result, "getMultipleAccounts", accounts[start:end], obj)
+			if err == nil && (result == nil || len(result.Value) != end-start) {
+				err = fmt.Errorf("expected %d accounts in response", end-start)
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("get multiple accounts: accounts [%d, %d[: %w", start, end, err)
+					cancel()
+				}
+				return
+			}
+
+			copy(out.Value[start:end], result.Value)
+			if out.Context.Slot == 0 || result.Context.Slot < out.Context.Slot {
+				out.Context.Slot = result.Context.Slot
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("get multiple accounts: %w", err)
+	}
+	return out, nil
+}
+
+// GetMultipleAccountsDataIn fetches `accounts` with GetMultipleAccounts and
+// decodes the data of each of them in the matching element of `inVars`, as
+// GetAccountDataIn does for a single account. The `inVars` of accounts that
+// do not exist are left untouched, the returned error wrapping ErrNotFound
+// lists them once all the other accounts are decoded.
+func (c *Client) GetMultipleAccountsDataIn(ctx context.Context, accounts []solana.PublicKey, inVars []interface{}) error {
+	if len(accounts) != len(inVars) {
+		return fmt.Errorf("expected as many decoding targets as accounts, got %d for %d accounts", len(inVars), len(accounts))
+	}
+
+	resp, err := c.GetMultipleAccounts(ctx, accounts, nil)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for i, account := range resp.Value {
+		if account == nil {
+			missing = append(missing, accounts[i].String())
+			continue
+		}
+
+		if err := bin.NewDecoder(account.Data).Decode(inVars[i]); err != nil {
+			return fmt.Errorf("decode account %s: %w", accounts[i], err)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("accounts %s: %w", strings.Join(missing, ", "), ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+// mockMultipleAccounts answers `getMultipleAccounts` with accounts holding
+// their index in `accounts` as data, accounts at an index multiple of 3 don't
+// exist.
+func mockMultipleAccounts(t *testing.T, accounts []solana.PublicKey) (server *httptest.Server, params *[][]interface{}, maxConcurrent *atomic.Int64) {
+	indexes := map[string]uint64{}
+	for i, account := range accounts {
+		indexes[account.String()] = uint64(i)
+	}
+
+	var lock sync.Mutex
+	params = &[][]interface{}{}
+	concurrent, maxConcurrent := atomic.NewInt64(0), atomic.NewInt64(0)
+
+	server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		current := concurrent.Inc()
+		defer concurrent.Dec()
+		for max := maxConcurrent.Load(); current > max && !maxConcurrent.CAS(max, current); max = maxConcurrent.Load() {
+		}
+
+		var request struct {
+			ID     int           `json:"id"`
+			Params []interface{} `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&request))
+
+		lock.Lock()
+		*params = append(*params, request.Params)
+		lock.Unlock()
+
+		var values []interface{}
+		for _, key := range request.Params[0].([]interface{}) {
+			index := indexes[key.(string)]
+			if index%3 == 0 {
+				values = append(values, nil)
+				continue
+			}
+
+			data := make([]byte, 8)
+			binary.LittleEndian.PutUint64(data, index)
+			values = append(values, map[string]interface{}{
+				"data":       []string{base64.StdEncoding.EncodeToString(data), "base64"},
+				"executable": false,
+				"lamports":   index,
+				"owner":      "11111111111111111111111111111111",
+				"rentEpoch":  0,
+			})
+		}
+
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      request.ID,
+			"result": map[string]interface{}{
+				"context": map[string]interface{}{"slot": 1000 + len(*params)},
+				"value":   values,
+			},
+		})
+	}))
+
+	return server, params, maxConcurrent
+}
+
+func newTestAccounts(count int) []solana.PublicKey {
+	accounts := make([]solana.PublicKey, count)
+	for i := range accounts {
+		binary.LittleEndian.PutUint64(accounts[i][:], uint64(i+1))
+	}
+	return accounts
+}
+
+func TestClient_GetMultipleAccounts(t *testing.T) {
+	accounts := newTestAccounts(250)
+	server, params, maxConcurrent := mockMultipleAccounts(t, accounts)
+	defer server.Close()
+
+	minContextSlot := uint64(900)
+	out, err := newTestClient(server.URL).GetMultipleAccounts(context.Background(), accounts, &GetMultipleAccountsOpts{
+		Commitment:     CommitmentConfirmed,
+		DataSlice:      &DataSlice{Offset: 0, Length: 8},
+		MinContextSlot: &minContextSlot,
+		Concurrency:    2,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *params, 3)
+	var sizes []int
+	for _, call := range *params {
+		sizes = append(sizes, len(call[0].([]interface{})))
+		assert.Equal(t, map[string]interface{}{
+			"commitment":     "confirmed",
+			"dataSlice":      map[string]interface{}{"offset": float64(0), "length": float64(8)},
+			"encoding":       "base64",
+			"minContextSlot": float64(900),
+		}, call[1])
+	}
+	sort.Ints(sizes)
+	assert.Equal(t, []int{50, 100, 100}, sizes)
+	assert.True(t, maxConcurrent.Load() <= 2)
+
+	assert.Equal(t, uint64(1001), uint64(out.Context.Slot))
+	require.Len(t, out.Value, 250)
+	for i, account := range out.Value {
+		if i%3 == 0 {
+			assert.Nil(t, account, "account %d", i)
+			continue
+		}
+
+		require.NotNil(t, account, "account %d", i)
+		assert.Equal(t, uint64(i), binary.LittleEndian.Uint64(account.Data))
+	}
+}
+
+func TestClient_GetMultipleAccounts_Error(t *testing.T) {
+	server, closer := mockJSONRPC(t, json.RawMessage(`{"jsonrpc":"2.0","error":{"code":-32016,"message":"Minimum context slot has not been reached"},"id":0}`))
+	defer closer()
+
+	_, err := newTestClient(server.URL).GetMultipleAccounts(context.Background(), newTestAccounts(2), nil)
+	assert.EqualError(t, err, "get multiple accounts: accounts [0, 2[: rpc response: -32016:Minimum context slot has not been reached")
+}
+
+func TestClient_GetMultipleAccountsDataIn(t *testing.T) {
+	accounts := newTestAccounts(5)
+	server, _, _ := mockMultipleAccounts(t, accounts)
+	defer server.Close()
+
+	values := make([]uint64, len(accounts))
+	inVars := make([]interface{}, len(accounts))
+	for i := range values {
+		values[i] = 42
+		inVars[i] = &values[i]
+	}
+
+	err := newTestClient(server.URL).GetMultipleAccountsDataIn(context.Background(), accounts, inVars)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Contains(t, err.Error(), accounts[0].String())
+	assert.Contains(t, err.Error(), accounts[3].String())
+
+	assert.Equal(t, []uint64{42, 1, 2, 42, 4}, values)
+}
@@ -1,6 +1,9 @@
 package rpc
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/streamingfast/solana-go"
 )
 
@@ -17,7 +20,7 @@ type GetProgramAccountsOpts struct {
 	Filters []RPCFilter `json:"filters,omitempty"`
 }
 
-func (c *Client) GetProgramAccounts(publicKey solana.PublicKey, opts *GetProgramAccountsOpts) (out GetProgramAccountsResult, err error) {
+func (c *Client) GetProgramAccounts(ctx context.Context, publicKey solana.PublicKey, opts *GetProgramAccountsOpts) (out GetProgramAccountsResult, err error) {
 	obj := map[string]interface{}{
 		"encoding": "base64",
 	}
@@ -32,6 +35,21 @@ func (c *Client) GetProgramAccounts(publicKey solana.PublicKey, opts *GetProgram
 
 	params := []interface{}{publicKey, obj}
 
-	err = c.DoRequest(&out, "getProgramAccounts", params...)
+	err = c.DoRequest(ctx, &out, "getProgramAccounts", params...)
 	return
 }
+
+// Decode decodes the account's data with the decoder registered for its owner
+// in solana.DefaultAccountDecoderRegistry.
+func (a *KeyedAccount) Decode() (interface{}, error) {
+	return a.DecodeWith(solana.DefaultAccountDecoderRegistry)
+}
+
+// DecodeWith decodes the account's data with the decoder registered for its
+// owner in `registry`.
+func (a *KeyedAccount) DecodeWith(registry *solana.AccountDecoderRegistry) (interface{}, error) {
+	if a.Account == nil {
+		return nil, fmt.Errorf("account %s has no data", a.Pubkey)
+	}
+	return a.Account.DecodeWith(registry, a.Pubkey)
+}
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"github.com/streamingfast/solana-go"
@@ -93,7 +94,7 @@ func TestClient_GetProgramAccount(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			client, cleanup, assertions := test.clientFunc(t)
 			defer cleanup()
-			out, err := client.GetProgramAccounts(test.address, test.opts)
+			out, err := client.GetProgramAccounts(context.Background(), test.address, test.opts)
 			if test.expectError {
 				require.Error(t, err)
 			} else {
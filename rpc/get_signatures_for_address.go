@@ -1,6 +1,8 @@
 package rpc
 
 import (
+	"context"
+
 	bin "github.com/streamingfast/binary"
 	"github.com/streamingfast/solana-go"
 )
@@ -22,7 +24,7 @@ type GetSignaturesForAddressOpts struct {
 	Until  string `json:"until,omitempty"`
 }
 
-func (c *Client) GetSignaturesForAddress(address solana.PublicKey, opts *GetSignaturesForAddressOpts) (out GetSignaturesForAddressResult, err error) {
+func (c *Client) GetSignaturesForAddress(ctx context.Context, address solana.PublicKey, opts *GetSignaturesForAddressOpts) (out GetSignaturesForAddressResult, err error) {
 	params := []interface{}{address.String()}
 	if opts != nil {
 		filter := map[string]interface{}{}
@@ -37,7 +39,7 @@ func (c *Client) GetSignaturesForAddress(address solana.PublicKey, opts *GetSign
 		}
 		params = append(params, filter)
 	}
-	err = c.DoRequest(&out, "getSignaturesForAddress", params...)
+	err = c.DoRequest(ctx, &out, "getSignaturesForAddress", params...)
 	return
 }
 
@@ -45,6 +47,6 @@ func (c *Client) GetSignaturesForAddress(address solana.PublicKey, opts *GetSign
 //
 //	params := []interface{}{address.String(), opts}
 //
-//	err = c.DoRequest(&out, "getConfirmedSignaturesForAddress2", params...)
+//	err = c.DoRequest(ctx, &out, "getConfirmedSignaturesForAddress2", params...)
 //	return
 //}
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/streamingfast/solana-go"
 	"github.com/stretchr/testify/assert"
@@ -75,7 +76,7 @@ func TestClient_GetSignaturesForAddress(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			client, cleanup, assertions := test.clientFunc(t)
 			defer cleanup()
-			out, err := client.GetSignaturesForAddress(test.address, test.opts)
+			out, err := client.GetSignaturesForAddress(context.Background(), test.address, test.opts)
 			if test.expectError {
 				require.Error(t, err)
 			} else {
@@ -1,17 +1,19 @@
 package rpc
 
 import (
+	"context"
+
 	bin "github.com/streamingfast/binary"
 )
 
-func (c *Client) GetSlot(commitment *CommitmentType) (uint64, error) {
+func (c *Client) GetSlot(ctx context.Context, commitment *CommitmentType) (uint64, error) {
 	var params []interface{}
 	if commitment != nil {
 		params = append(params, string(*commitment))
 	}
 
 	var out bin.Uint64
-	err := c.DoRequest(&out, "getSlot", params...)
+	err := c.DoRequest(ctx, &out, "getSlot", params...)
 	if err != nil {
 		return 0, err
 	}
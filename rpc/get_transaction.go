@@ -1,8 +1,11 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+
 	bin "github.com/streamingfast/binary"
 	"github.com/streamingfast/solana-go"
 )
@@ -12,6 +15,9 @@ type GetTransactionResponse struct {
 	BlockTime   *bin.Uint64  `json:"blockTime"`
 	Transaction *Transaction `json:"transaction"`
 	Meta        *Meta        `json:"meta"`
+	// Version is the version of the transaction's message, nil for nodes not
+	// supporting versioned transactions.
+	Version *solana.MessageVersion `json:"version"`
 }
 
 type Transaction struct {
@@ -28,10 +34,11 @@ func (t *Transaction) ResolveProgramIdIndex(programIdIndex uint64) (out solana.P
 }
 
 type Message struct {
-	AccountKeys     []solana.PublicKey `json:"accountKeys"`
-	Header          MessageHeader      `json:"header"`
-	Instructions    []Instruction      `json:"instructions"`
-	RecentBlockhash solana.PublicKey   `json:"recentBlockhash"`
+	AccountKeys         []solana.PublicKey                 `json:"accountKeys"`
+	Header              MessageHeader                      `json:"header"`
+	Instructions        []Instruction                      `json:"instructions"`
+	RecentBlockhash     solana.PublicKey                   `json:"recentBlockhash"`
+	AddressTableLookups []solana.MessageAddressTableLookup `json:"addressTableLookups,omitempty"`
 }
 
 type MessageHeader struct {
@@ -55,7 +62,11 @@ type Meta struct {
 	PostTokenBalances []*TokeBalance      `json:"postTokenBalances"`
 	PreTokenBalances  []*TokeBalance      `json:"preTokenBalances"`
 	LogMessages       []string            `json:"logMessages"`
-	Rewards           []interface{}       `json:"rewards"`
+	Rewards           []*Reward           `json:"rewards"`
+	// LoadedAddresses are the accounts loaded from the address table lookups
+	// of versioned transactions.
+	LoadedAddresses      *solana.LoadedAddresses `json:"loadedAddresses"`
+	ComputeUnitsConsumed *bin.Uint64             `json:"computeUnitsConsumed"`
 }
 
 type InnerInstruction struct {
@@ -70,44 +81,56 @@ type InstructionMeta struct {
 }
 
 type TokeBalance struct {
-	AccountIndex bin.Uint64       `json:"accountIndex"`
-	Mint         solana.PublicKey `json:"mint"`
-	Owner        solana.PublicKey `json:"owner"`
-	//UiTokenAmount struct {
-	//	Amount   string     `json:"amount"`
-	//	Decimals bin.Uint64 `json:"decimals"`
-	//	// deprecated
-	//	UiAmount       *float64 `json:"uiAmount"`
-	//	UiAmountString string   `json:"uiAmountString"`
-	//} `json:"uiTokenAmount"`
+	AccountIndex  bin.Uint64          `json:"accountIndex"`
+	Mint          solana.PublicKey    `json:"mint"`
+	Owner         solana.PublicKey    `json:"owner"`
+	ProgramID     solana.PublicKey    `json:"programId"`
+	UiTokenAmount *solana.TokenAmount `json:"uiTokenAmount"`
 }
 
+// TransactionError is the error of a failed transaction. Errors specific to
+// an instruction set InstructionIndex, InstructionErrorType and
+// InstructionErrorCode, other errors (`AccountInUse`, `InsufficientFundsForFee`,
+// ...) only set Type.
 type TransactionError struct {
 	Raw                  map[string]interface{} `json:"data,omitempty"`
+	Type                 string
 	InstructionIndex     uint64
 	InstructionErrorCode string
 	InstructionErrorType string
 }
 
 func (t *TransactionError) UnmarshalJSON(data []byte) (err error) {
+	var errName string
+	if err := json.Unmarshal(data, &errName); err == nil {
+		t.Type = errName
+		t.Raw = map[string]interface{}{errName: nil}
+		return nil
+	}
+
 	var errMap map[string]interface{}
 	if err := json.Unmarshal(data, &errMap); err != nil {
 		return err
 	}
 	t.Raw = errMap
+	for errType := range errMap {
+		t.Type = errType
+	}
 	if instructionError, ok := t.Raw["InstructionError"].([]interface{}); ok {
 		if len(instructionError) == 2 {
-			if idx, ok := instructionError[0].(uint64); ok {
-				t.InstructionIndex = idx
+			if idx, ok := instructionError[0].(float64); ok {
+				t.InstructionIndex = uint64(idx)
 			}
-			if instErr, ok := instructionError[1].(map[string]interface{}); ok {
+			if str, ok := instructionError[1].(string); ok {
+				t.InstructionErrorType = str
+			} else if instErr, ok := instructionError[1].(map[string]interface{}); ok {
 				for instErrType, instErrCode := range instErr {
 					t.InstructionErrorType = instErrType
 
 					if str, ok := instErrCode.(string); ok {
 						t.InstructionErrorCode = str
-					} else if num, ok := instErrCode.(json.Number); ok {
-						t.InstructionErrorCode = fmt.Sprintf("%s", num)
+					} else if num, ok := instErrCode.(float64); ok {
+						t.InstructionErrorCode = strconv.FormatFloat(num, 'f', -1, 64)
 					} else {
 						t.InstructionErrorCode = "unknown"
 					}
@@ -118,20 +141,56 @@ func (t *TransactionError) UnmarshalJSON(data []byte) (err error) {
 	return
 }
 
-func (c *Client) GetConfirmedTransaction(signature string) (out *GetTransactionResponse, err error) {
+func (c *Client) GetConfirmedTransaction(ctx context.Context, signature string) (out *GetTransactionResponse, err error) {
 	conf := CommitmentConfirmed
-	return c.GetTransaction(signature, &conf)
+	return c.GetTransaction(ctx, signature, &conf)
 }
 
 // GetTransaction For processing many dependent transactions in series, it's recommended to use "confirmed" commitment, which balances speed with rollback safety. For total safety, it's recommended to use"finalized" commitment.
-func (c *Client) GetTransaction(signature string, commitmentType *CommitmentType) (out *GetTransactionResponse, err error) {
+func (c *Client) GetTransaction(ctx context.Context, signature string, commitmentType *CommitmentType) (out *GetTransactionResponse, err error) {
+	err = c.DoRequest(ctx, &out, "getTransaction", getTransactionParams(signature, commitmentType)...)
+	return
+}
+
+// TransactionResult is the outcome of fetching one of the transactions of
+// GetTransactions, Value being nil when the transaction was not found.
+type TransactionResult struct {
+	Signature string
+	Value     *GetTransactionResponse
+	Err       error
+}
+
+// GetTransactions fetches the transactions of all `signatures` using batched
+// calls, see DoBatch. Results are in the order of `signatures`, the failure
+// to fetch one of them is reported in its result without affecting the
+// others. The returned error is only set when the batch itself failed.
+func (c *Client) GetTransactions(ctx context.Context, signatures []string, commitmentType *CommitmentType) ([]*TransactionResult, error) {
+	out := make([]*TransactionResult, len(signatures))
+	requests := make([]*BatchRequest, len(signatures))
+	for i, signature := range signatures {
+		out[i] = &TransactionResult{Signature: signature}
+		requests[i] = NewBatchRequest(&out[i].Value, "getTransaction", getTransactionParams(signature, commitmentType)...)
+	}
+
+	if err := c.DoBatch(ctx, requests); err != nil {
+		return nil, fmt.Errorf("get transactions: %w", err)
+	}
+
+	for i, request := range requests {
+		out[i].Err = request.Err
+	}
+	return out, nil
+}
+
+// getTransactionParams requests versioned transactions too, nodes reject
+// fetching v0 transactions otherwise.
+func getTransactionParams(signature string, commitmentType *CommitmentType) []interface{} {
 	opts := map[string]interface{}{
-		"encoding": "json",
+		"encoding":                       "json",
+		"maxSupportedTransactionVersion": 0,
 	}
 	if commitmentType != nil {
-		opts["Commitment"] = *commitmentType
+		opts["commitment"] = *commitmentType
 	}
-	params := []interface{}{signature, opts}
-	err = c.DoRequest(&out, "getTransaction", params...)
-	return
+	return []interface{}{signature, opts}
 }
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	bin "github.com/streamingfast/binary"
 	"github.com/streamingfast/solana-go"
@@ -25,7 +26,8 @@ func TestClient_GetTransaction(t *testing.T) {
 				client := newTestClient(server.URL)
 				return client, closer, func() {
 					assert.Equal(t, map[string]interface{}{"id": float64(0), "jsonrpc": "2.0", "method": "getTransaction", "params": []interface{}{"29RTUcaTCA48QBsQmYxjBofUogEyk8q6cJiThCWqYkCPNEvSGZgYyrwtRXJKDW4VWMLN8qeLjyH28cwXQzsKwExs", map[string]interface{}{
-						"encoding": "json",
+						"encoding":                       "json",
+						"maxSupportedTransactionVersion": float64(0),
 					}}}, server.RequestBody(t))
 				}
 			},
@@ -164,21 +166,24 @@ func TestClient_GetTransaction(t *testing.T) {
 					},
 					PostTokenBalances: []*TokeBalance{
 						{
-							AccountIndex: 2,
-							Mint:         solana.MustPublicKeyFromBase58("HHVpMURCU6gkLnW8tkwGzw6YJoWCj9RuLLESiTALY48x"),
-							Owner:        solana.MustPublicKeyFromBase58("HQc8axxhdu9jLfKtwcsmmGaF6LZPFgNjPAV1kThh3dew"),
+							AccountIndex:  2,
+							Mint:          solana.MustPublicKeyFromBase58("HHVpMURCU6gkLnW8tkwGzw6YJoWCj9RuLLESiTALY48x"),
+							Owner:         solana.MustPublicKeyFromBase58("HQc8axxhdu9jLfKtwcsmmGaF6LZPFgNjPAV1kThh3dew"),
+							UiTokenAmount: &solana.TokenAmount{Raw: 1, Decimals: 0},
 						},
 						{
-							AccountIndex: 11,
-							Mint:         solana.MustPublicKeyFromBase58("F35m318ScNzFAb8iizXKpHque7n9d1p6pvyfAJ3ZRZzd"),
-							Owner:        solana.MustPublicKeyFromBase58("6wrL8rQzDWSH7PJyZRGsdBiNcrpD8Wd6vJzGVBinuCL3"),
+							AccountIndex:  11,
+							Mint:          solana.MustPublicKeyFromBase58("F35m318ScNzFAb8iizXKpHque7n9d1p6pvyfAJ3ZRZzd"),
+							Owner:         solana.MustPublicKeyFromBase58("6wrL8rQzDWSH7PJyZRGsdBiNcrpD8Wd6vJzGVBinuCL3"),
+							UiTokenAmount: &solana.TokenAmount{Raw: 1, Decimals: 0},
 						},
 					},
 					PreTokenBalances: []*TokeBalance{
 						{
-							AccountIndex: 11,
-							Mint:         solana.MustPublicKeyFromBase58("F35m318ScNzFAb8iizXKpHque7n9d1p6pvyfAJ3ZRZzd"),
-							Owner:        solana.MustPublicKeyFromBase58("6wrL8rQzDWSH7PJyZRGsdBiNcrpD8Wd6vJzGVBinuCL3"),
+							AccountIndex:  11,
+							Mint:          solana.MustPublicKeyFromBase58("F35m318ScNzFAb8iizXKpHque7n9d1p6pvyfAJ3ZRZzd"),
+							Owner:         solana.MustPublicKeyFromBase58("6wrL8rQzDWSH7PJyZRGsdBiNcrpD8Wd6vJzGVBinuCL3"),
+							UiTokenAmount: &solana.TokenAmount{Raw: 1, Decimals: 0},
 						},
 					},
 					LogMessages: []string{
@@ -233,7 +238,7 @@ func TestClient_GetTransaction(t *testing.T) {
 						"Program metaqbxxUerdq28cj1RbAWkYQm3ybzjb6a8bt518x1s consumed 62955 of 200000 compute units",
 						"Program metaqbxxUerdq28cj1RbAWkYQm3ybzjb6a8bt518x1s success",
 					},
-					Rewards: []interface{}{},
+					Rewards: []*Reward{},
 				},
 			},
 		},
@@ -244,7 +249,8 @@ func TestClient_GetTransaction(t *testing.T) {
 				client := newTestClient(server.URL)
 				return client, closer, func() {
 					assert.Equal(t, map[string]interface{}{"id": float64(0), "jsonrpc": "2.0", "method": "getTransaction", "params": []interface{}{"29RTUcaTCA48QBsQmYxjBofUogEyk8q6cJiThCWqYkCPNEvSGZgYyrwtRXJKDW4VWMLN8qeLjyH28cwXQzsKwExs", map[string]interface{}{
-						"encoding": "json",
+						"encoding":                       "json",
+						"maxSupportedTransactionVersion": float64(0),
 					}}}, server.RequestBody(t))
 				}
 			},
@@ -296,8 +302,9 @@ func TestClient_GetTransaction(t *testing.T) {
 								},
 							},
 						},
+						Type:                 "InstructionError",
 						InstructionIndex:     0,
-						InstructionErrorCode: "unknown",
+						InstructionErrorCode: "41",
 						InstructionErrorType: "Custom",
 					},
 					Fee: 5000,
@@ -350,7 +357,7 @@ func TestClient_GetTransaction(t *testing.T) {
 						"Program Zo1ggzTUKMY5bYnDvT5mtVeZxzf2FaLTbKkmvGUhUQk consumed 41649 of 200000 compute units",
 						"Program Zo1ggzTUKMY5bYnDvT5mtVeZxzf2FaLTbKkmvGUhUQk failed: custom program error: 0x29",
 					},
-					Rewards: []interface{}{},
+					Rewards: []*Reward{},
 				},
 			},
 		},
@@ -371,7 +378,7 @@ func TestClient_GetTransaction(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			client, cleanup, assertions := test.clientFunc(t)
 			defer cleanup()
-			out, err := client.GetTransaction(test.signature, nil)
+			out, err := client.GetTransaction(context.Background(), test.signature, nil)
 			if test.expectError {
 				require.Error(t, err)
 			} else {
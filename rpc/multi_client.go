@@ -0,0 +1,444 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ybbus/jsonrpc"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// SelectionStrategy decides which endpoint of a MultiClient serves a call.
+type SelectionStrategy int
+
+const (
+	// SelectRoundRobin spreads calls evenly over the endpoints.
+	SelectRoundRobin SelectionStrategy = iota
+	// SelectLatency sends calls to endpoints with a probability inversely
+	// proportional to their observed latency.
+	SelectLatency
+)
+
+type MultiClientOption = func(m *MultiClient) *MultiClient
+
+// WithSelection sets how endpoints are picked, SelectRoundRobin by default.
+var WithSelection = func(strategy SelectionStrategy) MultiClientOption {
+	return func(m *MultiClient) *MultiClient {
+		m.strategy = strategy
+		return m
+	}
+}
+
+// WithHedging sends idempotent calls to another endpoint when the current one
+// did not answer after `delay`, the first successful response is used and the
+// other calls are canceled.
+var WithHedging = func(delay time.Duration) MultiClientOption {
+	return func(m *MultiClient) *MultiClient {
+		m.hedgeDelay = delay
+		return m
+	}
+}
+
+// WithHealthChecks checks the endpoints every `interval` with `getHealth` and
+// `getSlot`. Endpoints that are unhealthy, unreachable or more than
+// `maxSlotLag` slots behind the most advanced endpoint are ejected: they only
+// serve calls when all the healthy endpoints failed, until they pass a check
+// again.
+var WithHealthChecks = func(interval time.Duration, maxSlotLag uint64) MultiClientOption {
+	return func(m *MultiClient) *MultiClient {
+		m.healthCheckInterval = interval
+		m.maxSlotLag = maxSlotLag
+		return m
+	}
+}
+
+// MultiClient spreads calls over several RPC endpoints, failing over to the
+// next endpoint when one fails. It embeds a Client exposing the usual methods,
+// `multi.Client` can be given to any helper taking a `*rpc.Client`.
+//
+// Endpoint failures (transport errors, HTTP errors and transient JSON-RPC
+// errors) are failed over, other JSON-RPC errors are returned as is. Calls of
+// NonIdempotentMethods are sent to a single endpoint.
+type MultiClient struct {
+	*Client
+
+	endpoints []*endpoint
+	next      *atomic.Uint64
+
+	strategy            SelectionStrategy
+	hedgeDelay          time.Duration
+	healthCheckInterval time.Duration
+	maxSlotLag          uint64
+
+	closeOnce sync.Once
+	done      chan struct{}
+	stopped   sync.WaitGroup
+}
+
+type endpoint struct {
+	client  *Client
+	healthy *atomic.Bool
+	// latency is the moving average of successful calls durations in
+	// nanoseconds, 0 until a call succeeded.
+	latency *atomic.Int64
+}
+
+// NewMultiClient returns a client spreading calls over `clients`, each of
+// them being configured independently (headers, retry policy, rate limit).
+// Close must be called to stop the health checks.
+func NewMultiClient(clients []*Client, opts ...MultiClientOption) *MultiClient {
+	m := &MultiClient{
+		next: atomic.NewUint64(0),
+		done: make(chan struct{}),
+	}
+	m.Client = &Client{
+		requestIDGenerator: generateRequestID,
+		maxBatchSize:       DefaultMaxBatchSize,
+		router:             m,
+	}
+
+	for _, client := range clients {
+		m.endpoints = append(m.endpoints, &endpoint{
+			client:  client,
+			healthy: atomic.NewBool(true),
+			latency: atomic.NewInt64(0),
+		})
+	}
+
+	for _, opt := range opts {
+		m = opt(m)
+	}
+
+	if m.healthCheckInterval > 0 {
+		m.stopped.Add(1)
+		go m.healthChecks()
+	}
+
+	return m
+}
+
+// Close stops the health checks.
+func (m *MultiClient) Close() {
+	m.closeOnce.Do(func() {
+		close(m.done)
+		m.stopped.Wait()
+	})
+}
+
+func (m *MultiClient) doRequest(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	return m.race(ctx, !NonIdempotentMethods[method], out, func(ctx context.Context, client *Client, out interface{}) error {
+		return client.DoRequest(ctx, out, method, params...)
+	})
+}
+
+func (m *MultiClient) doBatch(ctx context.Context, requests []*BatchRequest) error {
+	idempotent := true
+	for _, request := range requests {
+		idempotent = idempotent && !NonIdempotentMethods[request.Method]
+	}
+
+	// Batches are not hedged, requests results are set in place and cannot be
+	// shared by concurrent attempts.
+	return m.failover(ctx, idempotent, func(ctx context.Context, client *Client) error {
+		return client.DoBatch(ctx, requests)
+	})
+}
+
+type attempt struct {
+	out interface{}
+	err error
+}
+
+// race performs `call` on the endpoints in selection order, moving to the
+// next endpoint when one fails or, when hedging, does not answer in time.
+// The result of the first successful attempt is set in `out`.
+func (m *MultiClient) race(ctx context.Context, idempotent bool, out interface{}, call func(ctx context.Context, client *Client, out interface{}) error) error {
+	if m.hedgeDelay <= 0 || !idempotent {
+		return m.failover(ctx, idempotent, func(ctx context.Context, client *Client) error {
+			return call(ctx, client, out)
+		})
+	}
+
+	endpoints := m.ordered()
+	if len(endpoints) == 0 {
+		return fmt.Errorf("multi client: no endpoints")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	attempts := make(chan attempt, len(endpoints))
+	launch := func(e *endpoint) {
+		attemptOut := newOut(out)
+		go func() {
+			attempts <- attempt{out: attemptOut, err: e.call(ctx, func(ctx context.Context) error {
+				return call(ctx, e.client, attemptOut)
+			})}
+		}()
+	}
+
+	launch(endpoints[0])
+	launched, pending := 1, 1
+
+	hedge := time.NewTicker(m.hedgeDelay)
+	defer hedge.Stop()
+
+	var lastErr error
+	for {
+		select {
+		case result := <-attempts:
+			pending--
+			if result.err == nil {
+				setOut(out, result.out)
+				return nil
+			}
+
+			lastErr = result.err
+			if !m.shouldFailover(ctx, result.err) {
+				return result.err
+			}
+
+			if launched < len(endpoints) {
+				launch(endpoints[launched])
+				launched++
+				pending++
+			} else if pending == 0 {
+				return fmt.Errorf("multi client: all %d endpoints failed: %w", len(endpoints), lastErr)
+			}
+
+		case <-hedge.C:
+			if launched < len(endpoints) {
+				zlog.Debug("hedging JSON-RPC call", zap.Int("endpoint", launched))
+				launch(endpoints[launched])
+				launched++
+				pending++
+			}
+		}
+	}
+}
+
+// failover performs `call` on the endpoints in selection order until it
+// succeeds. Non `idempotent` calls are performed on a single endpoint.
+func (m *MultiClient) failover(ctx context.Context, idempotent bool, call func(ctx context.Context, client *Client) error) error {
+	endpoints := m.ordered()
+	if len(endpoints) == 0 {
+		return fmt.Errorf("multi client: no endpoints")
+	}
+	if !idempotent {
+		endpoints = endpoints[:1]
+	}
+
+	var err error
+	for _, e := range endpoints {
+		err = e.call(ctx, func(ctx context.Context) error {
+			return call(ctx, e.client)
+		})
+		if err == nil || !m.shouldFailover(ctx, err) {
+			return err
+		}
+	}
+
+	if len(endpoints) == 1 {
+		return err
+	}
+	return fmt.Errorf("multi client: all %d endpoints failed: %w", len(endpoints), err)
+}
+
+// shouldFailover tells if `err` is specific to the endpoint that returned it,
+// another endpoint can answer successfully.
+func (m *MultiClient) shouldFailover(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var rpcErr *jsonrpc.RPCError
+	if errors.As(err, &rpcErr) {
+		return containsCode(transientRPCErrorCodes, rpcErr.Code)
+	}
+	return true
+}
+
+// ordered returns the endpoints in the order they should be tried, healthy
+// endpoints first.
+func (m *MultiClient) ordered() []*endpoint {
+	var healthy, unhealthy []*endpoint
+	for _, e := range m.endpoints {
+		if e.healthy.Load() {
+			healthy = append(healthy, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+
+	switch m.strategy {
+	case SelectLatency:
+		healthy = byLatency(healthy)
+	default:
+		if len(healthy) > 0 {
+			start := int(m.next.Inc()-1) % len(healthy)
+			healthy = append(healthy[start:], healthy[:start]...)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// byLatency picks the first endpoint randomly, weighted by the inverse of its
+// latency, the others following from the fastest to the slowest. Endpoints
+// without latency yet are given the best one so they get a chance to be
+// measured.
+func byLatency(endpoints []*endpoint) []*endpoint {
+	if len(endpoints) <= 1 {
+		return endpoints
+	}
+
+	best := int64(0)
+	for _, e := range endpoints {
+		if latency := e.latency.Load(); latency > 0 && (best == 0 || latency < best) {
+			best = latency
+		}
+	}
+	if best == 0 {
+		best = 1
+	}
+
+	latencies := make(map[*endpoint]int64, len(endpoints))
+	total := 0.0
+	for _, e := range endpoints {
+		latency := e.latency.Load()
+		if latency <= 0 {
+			latency = best
+		}
+		latencies[e] = latency
+		total += 1 / float64(latency)
+	}
+
+	sorted := append([]*endpoint(nil), endpoints...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return latencies[sorted[i]] < latencies[sorted[j]]
+	})
+
+	pick := rand.Float64() * total
+	for i, e := range sorted {
+		pick -= 1 / float64(latencies[e])
+		if pick <= 0 || i == len(sorted)-1 {
+			return append([]*endpoint{e}, append(sorted[:i:i], sorted[i+1:]...)...)
+		}
+	}
+	return sorted
+}
+
+// call performs `f`, recording its latency when it succeeds.
+func (e *endpoint) call(ctx context.Context, f func(ctx context.Context) error) error {
+	start := time.Now()
+	if err := f(ctx); err != nil {
+		return err
+	}
+
+	e.observe(time.Since(start))
+	return nil
+}
+
+func (e *endpoint) observe(latency time.Duration) {
+	for {
+		previous := e.latency.Load()
+		next := int64(latency)
+		if previous > 0 {
+			next = previous + (int64(latency)-previous)/5
+		}
+		if e.latency.CAS(previous, next) {
+			return
+		}
+	}
+}
+
+func (m *MultiClient) healthChecks() {
+	defer m.stopped.Done()
+
+	ticker := time.NewTicker(m.healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		m.checkHealth()
+
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkHealth updates the health of all endpoints.
+func (m *MultiClient) checkHealth() {
+	ctx, cancel := context.WithTimeout(context.Background(), m.healthCheckInterval)
+	defer cancel()
+
+	slots := make([]uint64, len(m.endpoints))
+	errs := make([]error, len(m.endpoints))
+
+	var wg sync.WaitGroup
+	for i, e := range m.endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+
+			if _, err := e.client.GetHealth(ctx); err != nil {
+				errs[i] = fmt.Errorf("get health: %w", err)
+				return
+			}
+
+			slot, err := e.client.GetSlot(ctx, nil)
+			if err != nil {
+				errs[i] = fmt.Errorf("get slot: %w", err)
+				return
+			}
+			slots[i] = slot
+		}(i, e)
+	}
+	wg.Wait()
+
+	highest := uint64(0)
+	for i, slot := range slots {
+		if errs[i] == nil && slot > highest {
+			highest = slot
+		}
+	}
+
+	for i, e := range m.endpoints {
+		err := errs[i]
+		if err == nil && slots[i]+m.maxSlotLag < highest {
+			err = fmt.Errorf("slot %d is more than %d slots behind %d", slots[i], m.maxSlotLag, highest)
+		}
+
+		healthy := err == nil
+		if e.healthy.Swap(healthy) != healthy {
+			if healthy {
+				zlog.Info("rpc endpoint back to healthy", zap.String("url", e.client.rpcURL))
+			} else {
+				zlog.Info("rpc endpoint ejected", zap.String("url", e.client.rpcURL), zap.Error(err))
+			}
+		}
+	}
+}
+
+func newOut(out interface{}) interface{} {
+	if out == nil {
+		return nil
+	}
+	return reflect.New(reflect.TypeOf(out).Elem()).Interface()
+}
+
+func setOut(out, value interface{}) {
+	if out == nil {
+		return
+	}
+	reflect.ValueOf(out).Elem().Set(reflect.ValueOf(value).Elem())
+}
@@ -0,0 +1,262 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ybbus/jsonrpc"
+	"go.uber.org/atomic"
+)
+
+type mockNode struct {
+	*httptest.Server
+	calls *atomic.Int64
+
+	slot    uint64
+	healthy bool
+	delay   time.Duration
+	status  int
+	rpcErr  *jsonrpc.RPCError
+}
+
+// newMockNode starts a node answering `getSlot` and `getHealth`, configured
+// before the first call.
+func newMockNode(configure func(node *mockNode)) *mockNode {
+	node := &mockNode{calls: atomic.NewInt64(0), slot: 100, healthy: true}
+	if configure != nil {
+		configure(node)
+	}
+
+	node.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var request jsonrpc.RPCRequest
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+			// Batches
+			rw.Write([]byte(`[{"jsonrpc":"2.0","result":1,"id":0}]`))
+			return
+		}
+
+		if request.Method != "getHealth" {
+			node.calls.Inc()
+		}
+
+		if node.delay > 0 {
+			select {
+			case <-time.After(node.delay):
+			case <-req.Context().Done():
+				return
+			}
+		}
+
+		if node.status != 0 {
+			rw.WriteHeader(node.status)
+			return
+		}
+
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+		switch {
+		case node.rpcErr != nil:
+			response["error"] = node.rpcErr
+		case request.Method == "getHealth" && !node.healthy:
+			response["error"] = &jsonrpc.RPCError{Code: RPCErrorCodeNodeUnhealthy, Message: "Node is unhealthy"}
+		case request.Method == "getHealth":
+			response["result"] = "ok"
+		case request.Method == "getSlot":
+			response["result"] = node.slot
+		default:
+			response["result"] = request.Method
+		}
+
+		json.NewEncoder(rw).Encode(response)
+	}))
+
+	return node
+}
+
+func TestMultiClient_RoundRobin(t *testing.T) {
+	a, b := newMockNode(nil), newMockNode(nil)
+	defer a.Close()
+	defer b.Close()
+
+	multi := NewMultiClient([]*Client{NewClient(a.URL), NewClient(b.URL)})
+	defer multi.Close()
+
+	for i := 0; i < 4; i++ {
+		slot, err := multi.GetSlot(context.Background(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(100), slot)
+	}
+
+	assert.Equal(t, int64(2), a.calls.Load())
+	assert.Equal(t, int64(2), b.calls.Load())
+}
+
+func TestMultiClient_Failover(t *testing.T) {
+	tests := []struct {
+		name        string
+		failing     func(node *mockNode)
+		expectErr   bool
+		expectCalls int64
+	}{
+		{
+			name:        "http error",
+			failing:     func(node *mockNode) { node.status = http.StatusServiceUnavailable },
+			expectCalls: 1,
+		},
+		{
+			name:        "transient rpc error",
+			failing:     func(node *mockNode) { node.rpcErr = &jsonrpc.RPCError{Code: RPCErrorCodeNodeUnhealthy} },
+			expectCalls: 1,
+		},
+		{
+			name:      "rpc error",
+			failing:   func(node *mockNode) { node.rpcErr = &jsonrpc.RPCError{Code: -32602, Message: "Invalid params"} },
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			failing, working := newMockNode(test.failing), newMockNode(nil)
+			defer failing.Close()
+			defer working.Close()
+
+			multi := NewMultiClient([]*Client{NewClient(failing.URL), NewClient(working.URL)})
+			defer multi.Close()
+
+			_, err := multi.GetSlot(context.Background(), nil)
+			assert.Equal(t, int64(1), failing.calls.Load())
+			assert.Equal(t, test.expectCalls, working.calls.Load())
+			if test.expectErr {
+				var rpcErr *jsonrpc.RPCError
+				require.True(t, errors.As(err, &rpcErr))
+				assert.Equal(t, -32602, rpcErr.Code)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMultiClient_Failover_AllFailing(t *testing.T) {
+	a := newMockNode(func(node *mockNode) { node.status = http.StatusBadGateway })
+	b := newMockNode(func(node *mockNode) { node.status = http.StatusServiceUnavailable })
+	defer a.Close()
+	defer b.Close()
+
+	multi := NewMultiClient([]*Client{NewClient(a.URL), NewClient(b.URL)})
+	defer multi.Close()
+
+	_, err := multi.GetSlot(context.Background(), nil)
+	require.Error(t, err)
+
+	var httpErr *HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+}
+
+func TestMultiClient_NonIdempotent(t *testing.T) {
+	failing := newMockNode(func(node *mockNode) { node.status = http.StatusServiceUnavailable })
+	working := newMockNode(nil)
+	defer failing.Close()
+	defer working.Close()
+
+	multi := NewMultiClient([]*Client{NewClient(failing.URL), NewClient(working.URL)})
+	defer multi.Close()
+
+	account := solana.MustPublicKeyFromBase58("11111111111111111111111111111111")
+	_, err := multi.RequestAirdrop(context.Background(), &account, 1, CommitmentFinalized)
+	require.Error(t, err)
+	assert.Equal(t, int64(1), failing.calls.Load())
+	assert.Equal(t, int64(0), working.calls.Load())
+}
+
+func TestMultiClient_Hedging(t *testing.T) {
+	slow := newMockNode(func(node *mockNode) { node.delay = 5 * time.Second; node.slot = 1 })
+	fast := newMockNode(func(node *mockNode) { node.slot = 2 })
+	defer slow.Close()
+	defer fast.Close()
+
+	multi := NewMultiClient([]*Client{NewClient(slow.URL), NewClient(fast.URL)}, WithHedging(20*time.Millisecond))
+	defer multi.Close()
+
+	start := time.Now()
+	slot, err := multi.GetSlot(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), slot)
+	assert.True(t, time.Since(start) < time.Second, "expected the hedged call to answer, took %s", time.Since(start))
+	assert.Equal(t, int64(1), slow.calls.Load())
+	assert.Equal(t, int64(1), fast.calls.Load())
+}
+
+func TestMultiClient_Batch(t *testing.T) {
+	failing := newMockNode(func(node *mockNode) { node.status = http.StatusServiceUnavailable })
+	working := newMockNode(nil)
+	defer failing.Close()
+	defer working.Close()
+
+	multi := NewMultiClient([]*Client{NewClient(failing.URL), NewClient(working.URL)})
+	defer multi.Close()
+
+	var out uint64
+	requests := []*BatchRequest{NewBatchRequest(&out, "getSlot")}
+	require.NoError(t, multi.DoBatch(context.Background(), requests))
+	require.NoError(t, requests[0].Err)
+	assert.Equal(t, uint64(1), out)
+}
+
+func TestMultiClient_HealthChecks(t *testing.T) {
+	ahead := newMockNode(func(node *mockNode) { node.slot = 1000 })
+	lagging := newMockNode(func(node *mockNode) { node.slot = 900 })
+	unhealthy := newMockNode(func(node *mockNode) { node.healthy = false; node.slot = 1000 })
+	defer ahead.Close()
+	defer lagging.Close()
+	defer unhealthy.Close()
+
+	multi := NewMultiClient([]*Client{NewClient(lagging.URL), NewClient(unhealthy.URL), NewClient(ahead.URL)}, WithHealthChecks(time.Hour, 50))
+	defer multi.Close()
+
+	require.Eventually(t, func() bool {
+		return !multi.endpoints[0].healthy.Load() && !multi.endpoints[1].healthy.Load()
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, multi.endpoints[2].healthy.Load())
+
+	before := lagging.calls.Load()
+	for i := 0; i < 3; i++ {
+		slot, err := multi.GetSlot(context.Background(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1000), slot)
+	}
+	assert.Equal(t, before, lagging.calls.Load())
+
+	ordered := multi.ordered()
+	assert.Equal(t, multi.endpoints[2], ordered[0])
+	assert.Len(t, ordered, 3)
+}
+
+func TestByLatency(t *testing.T) {
+	newEndpoint := func(latency time.Duration) *endpoint {
+		return &endpoint{latency: atomic.NewInt64(int64(latency)), healthy: atomic.NewBool(true)}
+	}
+
+	fast, slow, unknown := newEndpoint(time.Millisecond), newEndpoint(time.Second), newEndpoint(0)
+
+	firsts := map[*endpoint]int{}
+	for i := 0; i < 1000; i++ {
+		ordered := byLatency([]*endpoint{slow, fast, unknown})
+		require.Len(t, ordered, 3)
+		assert.ElementsMatch(t, []*endpoint{slow, fast, unknown}, ordered)
+		firsts[ordered[0]]++
+	}
+
+	assert.True(t, firsts[slow] < 50, "slow endpoint picked first %d times", firsts[slow])
+	assert.True(t, firsts[fast] > 300, "fast endpoint picked first %d times", firsts[fast])
+	assert.True(t, firsts[unknown] > 300, "unmeasured endpoint picked first %d times", firsts[unknown])
+}
@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the HTTP requests sent to the endpoint to
+// `requestsPerSecond` on average, allowing bursts of up to `burst` requests.
+// Calls wait for their turn, or until their context is done. Each batch
+// sent and each retry attempt counts as one request.
+var WithRateLimit = func(requestsPerSecond float64, burst int) ClientOption {
+	return func(cli *Client) *Client {
+		cli.rateLimiter = newTokenBucket(requestsPerSecond, burst)
+		return cli
+	}
+}
+
+// tokenBucket is a token bucket rate limiter, tokens are reserved in order so
+// waiting callers are served first come, first served.
+type tokenBucket struct {
+	lock sync.Mutex
+
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	now func() time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+// Wait blocks until a token is available, returning the context's error if
+// it's done first.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	delay := b.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token, returning how long to wait for it to be available.
+func (b *tokenBucket) reserve() time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.rate <= 0 {
+		return 0
+	}
+
+	now := b.now()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel gives back a token reserved by a caller that stopped waiting.
+func (b *tokenBucket) cancel() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
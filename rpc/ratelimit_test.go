@@ -0,0 +1,55 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	bucket := newTokenBucket(10, 2)
+	bucket.now = func() time.Time { return now }
+
+	assert.Equal(t, time.Duration(0), bucket.reserve())
+	assert.Equal(t, time.Duration(0), bucket.reserve())
+	assert.Equal(t, 100*time.Millisecond, bucket.reserve())
+	assert.Equal(t, 200*time.Millisecond, bucket.reserve())
+
+	bucket.cancel()
+	assert.Equal(t, 200*time.Millisecond, bucket.reserve())
+
+	now = now.Add(time.Second)
+	assert.Equal(t, time.Duration(0), bucket.reserve())
+	assert.Equal(t, time.Duration(0), bucket.reserve())
+	assert.Equal(t, 100*time.Millisecond, bucket.reserve())
+}
+
+func TestTokenBucket_Wait(t *testing.T) {
+	bucket := newTokenBucket(1, 1)
+	require.NoError(t, bucket.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, bucket.Wait(ctx))
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	server, calls := mockFlakyJSONRPC(nil, `{"jsonrpc":"2.0","result":42,"id":0}`)
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRateLimit(20, 1))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := client.GetSlot(context.Background(), nil)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int64(3), calls.Load())
+	assert.True(t, time.Since(start) >= 90*time.Millisecond, "expected calls to be throttled, took %s", time.Since(start))
+}
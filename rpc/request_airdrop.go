@@ -1,11 +1,12 @@
 package rpc
 
 import (
+	"context"
 	"fmt"
 	"github.com/streamingfast/solana-go"
 )
 
-func (c *Client) RequestAirdrop(account *solana.PublicKey, lamport uint64, commitment CommitmentType) (signature string, err error) {
+func (c *Client) RequestAirdrop(ctx context.Context, account *solana.PublicKey, lamport uint64, commitment CommitmentType) (signature string, err error) {
 
 	obj := map[string]interface{}{
 		"commitment": commitment,
@@ -17,7 +18,7 @@ func (c *Client) RequestAirdrop(account *solana.PublicKey, lamport uint64, commi
 		obj,
 	}
 
-	if err := c.DoRequest(&signature, "requestAirdrop", params...); err != nil {
+	if err := c.DoRequest(ctx, &signature, "requestAirdrop", params...); err != nil {
 		return "", fmt.Errorf("send transaction: rpc send: %w", err)
 	}
 	return
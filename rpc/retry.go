@@ -0,0 +1,216 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jpillora/backoff"
+	"github.com/ybbus/jsonrpc"
+	"go.uber.org/zap"
+)
+
+// Solana JSON-RPC error codes of transient conditions, a later attempt of the
+// same call can succeed.
+const (
+	RPCErrorCodeBlockNotAvailable        = -32004
+	RPCErrorCodeNodeUnhealthy            = -32005
+	RPCErrorCodeBlockStatusNotAvailable  = -32014
+	RPCErrorCodeMinContextSlotNotReached = -32016
+)
+
+var transientRPCErrorCodes = []int{
+	RPCErrorCodeBlockNotAvailable,
+	RPCErrorCodeNodeUnhealthy,
+	RPCErrorCodeBlockStatusNotAvailable,
+	RPCErrorCodeMinContextSlotNotReached,
+}
+
+// NonIdempotentMethods are the JSON-RPC methods not retried unless the retry
+// policy has `RetryNonIdempotent` set, performing them twice can have effects
+// the caller did not ask for.
+var NonIdempotentMethods = map[string]bool{
+	"sendTransaction": true,
+	"requestAirdrop":  true,
+}
+
+// HTTPError is returned when the RPC endpoint answers with a non 2xx HTTP
+// status.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	// RetryAfter is the delay requested by the endpoint through the
+	// `Retry-After` header, 0 when absent.
+	RetryAfter time.Duration
+	Body       []byte
+}
+
+func (e *HTTPError) Error() string {
+	body := strings.TrimSpace(string(e.Body))
+	if len(body) > 256 {
+		body = body[:256] + "..."
+	}
+	if body == "" {
+		return fmt.Sprintf("http status %s", e.Status)
+	}
+	return fmt.Sprintf("http status %s: %s", e.Status, body)
+}
+
+// RetryPolicy configures how failed calls are retried, see WithRetryPolicy.
+//
+// Transport errors, HTTP statuses in `RetryStatusCodes` and JSON-RPC errors
+// with a code in `RetryRPCErrorCodes` are retried with an exponential backoff
+// with jitter, or after the delay of the `Retry-After` header when sent by
+// the endpoint, capped to `MaxBackoff`. Calls of a batch are retried together only when the whole
+// batch fails, errors of individual calls are not retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a call is performed,
+	// including the first attempt.
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	Factor      float64
+
+	RetryStatusCodes   []int
+	RetryRPCErrorCodes []int
+
+	// RetryNonIdempotent allows retrying the calls of NonIdempotentMethods.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns the policy used by WithRetryPolicy when given a
+// nil policy.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 5,
+		MinBackoff:  250 * time.Millisecond,
+		MaxBackoff:  10 * time.Second,
+		Factor:      2,
+		RetryStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+		},
+		RetryRPCErrorCodes: append([]int(nil), transientRPCErrorCodes...),
+	}
+}
+
+// WithRetryPolicy retries failed calls according to `policy`, the
+// DefaultRetryPolicy when nil.
+var WithRetryPolicy = func(policy *RetryPolicy) ClientOption {
+	return func(cli *Client) *Client {
+		if policy == nil {
+			policy = DefaultRetryPolicy()
+		}
+		cli.retryPolicy = policy
+		return cli
+	}
+}
+
+// retryDelay returns how long to wait before the attempt following the
+// failed `attempt` (starting at 0), or false when `err` must not be retried.
+func (p *RetryPolicy) retryDelay(attempt int, err error) (time.Duration, bool) {
+	if attempt+1 >= p.MaxAttempts || !p.isRetryable(err) {
+		return 0, false
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.RetryAfter > 0 {
+		if httpErr.RetryAfter > p.MaxBackoff {
+			return p.MaxBackoff, true
+		}
+		return httpErr.RetryAfter, true
+	}
+
+	b := &backoff.Backoff{
+		Min:    p.MinBackoff,
+		Max:    p.MaxBackoff,
+		Factor: p.Factor,
+		Jitter: true,
+	}
+	return b.ForAttempt(float64(attempt)), true
+}
+
+func (p *RetryPolicy) isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return containsCode(p.RetryStatusCodes, httpErr.StatusCode)
+	}
+
+	var rpcErr *jsonrpc.RPCError
+	if errors.As(err, &rpcErr) {
+		return containsCode(p.RetryRPCErrorCodes, rpcErr.Code)
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// withRetry performs `attempt` until it succeeds or the client's retry policy
+// gives up. Non `idempotent` attempts are performed once unless the policy
+// allows retrying them.
+func (c *Client) withRetry(ctx context.Context, logger *zap.Logger, idempotent bool, attempt func() error) error {
+	policy := c.retryPolicy
+	if policy == nil || (!idempotent && !policy.RetryNonIdempotent) {
+		return attempt()
+	}
+
+	for i := 0; ; i++ {
+		err := attempt()
+		if err == nil {
+			return nil
+		}
+
+		delay, retry := policy.retryDelay(i, err)
+		if !retry {
+			return err
+		}
+
+		logger.Debug("retrying JSON-RPC call", zap.Int("attempt", i+1), zap.Duration("delay", delay), zap.Error(err))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("retry after %q aborted: %w", err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+func containsCode(codes []int, code int) bool {
+	for _, candidate := range codes {
+		if candidate == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,220 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/streamingfast/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ybbus/jsonrpc"
+	"go.uber.org/atomic"
+)
+
+// mockFlakyJSONRPC answers with the `failures` handlers in turn, then with
+// `success` once they are exhausted. The number of received requests is
+// returned.
+func mockFlakyJSONRPC(failures []http.HandlerFunc, success string) (*httptest.Server, *atomic.Int64) {
+	calls := atomic.NewInt64(0)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		call := int(calls.Inc()) - 1
+		if call < len(failures) {
+			failures[call](rw, req)
+			return
+		}
+		rw.Write([]byte(success))
+	}))
+
+	return server, calls
+}
+
+func httpStatus(code int, headers ...string) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		for i := 0; i+1 < len(headers); i += 2 {
+			rw.Header().Set(headers[i], headers[i+1])
+		}
+		rw.WriteHeader(code)
+		rw.Write([]byte("slow down"))
+	}
+}
+
+func rpcError(code int) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"jsonrpc":"2.0","error":{"code":` + strconv.Itoa(code) + `,"message":"failed"},"id":0}`))
+	}
+}
+
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.MinBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+func TestClient_Retry(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      []http.HandlerFunc
+		policy        *RetryPolicy
+		expectCalls   int64
+		expectErr     bool
+		expectErrCode int
+	}{
+		{
+			name:        "no policy",
+			failures:    []http.HandlerFunc{httpStatus(503)},
+			expectCalls: 1,
+			expectErr:   true,
+		},
+		{
+			name:        "retried statuses",
+			failures:    []http.HandlerFunc{httpStatus(429), httpStatus(502), httpStatus(503)},
+			policy:      testRetryPolicy(),
+			expectCalls: 4,
+		},
+		{
+			name:        "retried rpc error",
+			failures:    []http.HandlerFunc{rpcError(RPCErrorCodeNodeUnhealthy)},
+			policy:      testRetryPolicy(),
+			expectCalls: 2,
+		},
+		{
+			name:          "not retried rpc error",
+			failures:      []http.HandlerFunc{rpcError(-32602)},
+			policy:        testRetryPolicy(),
+			expectCalls:   1,
+			expectErr:     true,
+			expectErrCode: -32602,
+		},
+		{
+			name:        "not retried status",
+			failures:    []http.HandlerFunc{httpStatus(400)},
+			policy:      testRetryPolicy(),
+			expectCalls: 1,
+			expectErr:   true,
+		},
+		{
+			name:        "max attempts",
+			failures:    []http.HandlerFunc{httpStatus(503), httpStatus(503), httpStatus(503), httpStatus(503), httpStatus(503)},
+			policy:      testRetryPolicy(),
+			expectCalls: 5,
+			expectErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server, calls := mockFlakyJSONRPC(test.failures, `{"jsonrpc":"2.0","result":42,"id":0}`)
+			defer server.Close()
+
+			var opts []ClientOption
+			if test.policy != nil {
+				opts = append(opts, WithRetryPolicy(test.policy))
+			}
+
+			slot, err := NewClient(server.URL, opts...).GetSlot(context.Background(), nil)
+			assert.Equal(t, test.expectCalls, calls.Load())
+
+			if !test.expectErr {
+				require.NoError(t, err)
+				assert.Equal(t, uint64(42), slot)
+				return
+			}
+
+			require.Error(t, err)
+			if test.expectErrCode != 0 {
+				var rpcErr *jsonrpc.RPCError
+				require.True(t, errors.As(err, &rpcErr))
+				assert.Equal(t, test.expectErrCode, rpcErr.Code)
+			} else {
+				var httpErr *HTTPError
+				require.True(t, errors.As(err, &httpErr))
+				assert.Equal(t, "slow down", string(httpErr.Body))
+			}
+		})
+	}
+}
+
+func TestClient_Retry_NonIdempotent(t *testing.T) {
+	account := solana.MustPublicKeyFromBase58("11111111111111111111111111111111")
+
+	server, calls := mockFlakyJSONRPC([]http.HandlerFunc{httpStatus(503)}, `{"jsonrpc":"2.0","result":"sig","id":0}`)
+	defer server.Close()
+
+	_, err := NewClient(server.URL, WithRetryPolicy(testRetryPolicy())).RequestAirdrop(context.Background(), &account, 1, CommitmentFinalized)
+	require.Error(t, err)
+	assert.Equal(t, int64(1), calls.Load())
+
+	policy := testRetryPolicy()
+	policy.RetryNonIdempotent = true
+
+	signature, err := NewClient(server.URL, WithRetryPolicy(policy)).RequestAirdrop(context.Background(), &account, 1, CommitmentFinalized)
+	require.NoError(t, err)
+	assert.Equal(t, "sig", signature)
+	assert.Equal(t, int64(2), calls.Load())
+}
+
+func TestClient_Retry_RetryAfter(t *testing.T) {
+	server, calls := mockFlakyJSONRPC([]http.HandlerFunc{httpStatus(429, "Retry-After", "1")}, `{"jsonrpc":"2.0","result":42,"id":0}`)
+	defer server.Close()
+
+	policy := testRetryPolicy()
+	policy.MaxBackoff = time.Minute
+	client := NewClient(server.URL, WithRetryPolicy(policy))
+
+	start := time.Now()
+	_, err := client.GetSlot(context.Background(), nil)
+	require.NoError(t, err)
+	assert.True(t, time.Since(start) >= time.Second, "expected to wait for Retry-After, waited %s", time.Since(start))
+	assert.Equal(t, int64(2), calls.Load())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	calls.Store(0)
+	_, err = client.GetSlot(ctx, nil)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, int64(1), calls.Load())
+
+	client = NewClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+
+	calls.Store(0)
+	start = time.Now()
+	_, err = client.GetSlot(context.Background(), nil)
+	require.NoError(t, err)
+	assert.True(t, time.Since(start) < time.Second, "expected Retry-After to be capped to MaxBackoff, waited %s", time.Since(start))
+	assert.Equal(t, int64(2), calls.Load())
+}
+
+func TestClient_Retry_Batch(t *testing.T) {
+	server, calls := mockFlakyJSONRPC([]http.HandlerFunc{httpStatus(502), rpcError(429)}, `[{"jsonrpc":"2.0","result":42,"id":0}]`)
+	defer server.Close()
+
+	policy := testRetryPolicy()
+	policy.RetryRPCErrorCodes = append(policy.RetryRPCErrorCodes, 429)
+
+	var slot uint64
+	requests := []*BatchRequest{NewBatchRequest(&slot, "getSlot")}
+
+	require.NoError(t, NewClient(server.URL, WithRetryPolicy(policy)).DoBatch(context.Background(), requests))
+	require.NoError(t, requests[0].Err)
+	assert.Equal(t, uint64(42), slot)
+	assert.Equal(t, int64(3), calls.Load())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-3", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("Sun, 01 May 2022 10:00:30 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("Sun, 01 May 2022 09:00:00 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+}
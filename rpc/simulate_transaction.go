@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"fmt"
 	bin "github.com/streamingfast/binary"
@@ -13,7 +14,7 @@ type SimulateTransactionResponse struct {
 	Logs []string
 }
 
-func (c *Client) SimulateTransaction(transaction *solana.Transaction) (*SimulateTransactionResponse, error) {
+func (c *Client) SimulateTransaction(ctx context.Context, transaction *solana.Transaction) (*SimulateTransactionResponse, error) {
 	buf := new(bytes.Buffer)
 	if err := bin.NewEncoder(buf).Encode(transaction); err != nil {
 		return nil, fmt.Errorf("send transaction: encode transaction: %w", err)
@@ -31,7 +32,7 @@ func (c *Client) SimulateTransaction(transaction *solana.Transaction) (*Simulate
 	}
 
 	var out *SimulateTransactionResponse
-	if err := c.DoRequest(&out, "simulateTransaction", params...); err != nil {
+	if err := c.DoRequest(ctx, &out, "simulateTransaction", params...); err != nil {
 		return nil, fmt.Errorf("send transaction: rpc send: %w", err)
 	}
 
@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+)
+
+// slotIteratorPageSpan is the number of slots covered by each `getBlocks`
+// call of a SlotIterator.
+const slotIteratorPageSpan = 10_000
+
+// SlotIterator walks the slots holding a confirmed block in a range, skipped
+// slots are left out. Slots past the latest slot of the node at the iterator's
+// commitment are not known to be skipped yet: the iteration stops at that
+// slot with an error, a new iterator can resume from there once the node
+// caught up. It's used like a bufio.Scanner:
+//
+//	it := client.NewSlotIterator(startSlot, endSlot, rpc.CommitmentFinalized)
+//	for it.Next(ctx) {
+//		block, err := client.GetBlock(ctx, it.Slot())
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type SlotIterator struct {
+	client     *Client
+	commitment CommitmentType
+
+	next uint64
+	end  uint64
+	done bool
+	// latest is the latest slot of the node, refreshed when the iteration
+	// gets past it.
+	latest uint64
+
+	buffered []uint64
+	slot     uint64
+	err      error
+}
+
+// NewSlotIterator returns an iterator over the confirmed blocks between
+// `startSlot` and `endSlot` included. Only CommitmentConfirmed and
+// CommitmentFinalized (default) are supported.
+func (c *Client) NewSlotIterator(startSlot, endSlot uint64, commitment CommitmentType) *SlotIterator {
+	return &SlotIterator{
+		client:     c,
+		commitment: commitment,
+		next:       startSlot,
+		end:        endSlot,
+		done:       startSlot > endSlot,
+	}
+}
+
+// Next advances to the next confirmed slot, fetching the following page of
+// blocks when needed. It returns false at the end of the range or when an
+// error occurred, see Err.
+func (it *SlotIterator) Next(ctx context.Context) bool {
+	for len(it.buffered) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		if err := it.fetch(ctx); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	it.slot, it.buffered = it.buffered[0], it.buffered[1:]
+	return true
+}
+
+// Slot returns the current slot, valid after Next returned true.
+func (it *SlotIterator) Slot() uint64 {
+	return it.slot
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *SlotIterator) Err() error {
+	return it.err
+}
+
+func (it *SlotIterator) fetch(ctx context.Context) error {
+	pageEnd := it.end
+	if it.end-it.next >= slotIteratorPageSpan {
+		pageEnd = it.next + slotIteratorPageSpan - 1
+	}
+
+	if pageEnd > it.latest {
+		var commitment *CommitmentType
+		if it.commitment != "" {
+			commitment = &it.commitment
+		}
+
+		latest, err := it.client.GetSlot(ctx, commitment)
+		if err != nil {
+			return fmt.Errorf("get latest slot: %w", err)
+		}
+		it.latest = latest
+	}
+	if it.next > it.latest {
+		return fmt.Errorf("slot %d is past the latest slot %d of the node", it.next, it.latest)
+	}
+	if pageEnd > it.latest {
+		pageEnd = it.latest
+	}
+
+	slots, err := it.client.GetBlocks(ctx, it.next, &pageEnd, it.commitment)
+	if err != nil {
+		return fmt.Errorf("get blocks [%d, %d]: %w", it.next, pageEnd, err)
+	}
+
+	for _, slot := range slots {
+		if slot >= it.next && slot <= pageEnd {
+			it.buffered = append(it.buffered, slot)
+		}
+	}
+
+	if pageEnd == it.end {
+		it.done = true
+	} else {
+		it.next = pageEnd + 1
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockGetBlocks answers `getSlot` with `*latest` and `getBlocks` with the
+// slots of the requested range up to `*latest` not in `skipped`, the ranges
+// requested to `getBlocks` are returned.
+func mockGetBlocks(t *testing.T, latest *uint64, skipped map[uint64]bool) (*httptest.Server, *[][2]uint64) {
+	ranges := &[][2]uint64{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var request struct {
+			ID     int             `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&request))
+
+		if request.Method == "getSlot" {
+			json.NewEncoder(rw).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": *latest})
+			return
+		}
+
+		var params []json.RawMessage
+		require.NoError(t, json.Unmarshal(request.Params, &params))
+
+		var start, end uint64
+		require.NoError(t, json.Unmarshal(params[0], &start))
+		require.NoError(t, json.Unmarshal(params[1], &end))
+		*ranges = append(*ranges, [2]uint64{start, end})
+
+		slots := []uint64{}
+		for slot := start; slot <= end && slot <= *latest; slot++ {
+			if !skipped[slot] {
+				slots = append(slots, slot)
+			}
+		}
+
+		json.NewEncoder(rw).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": slots})
+	}))
+
+	return server, ranges
+}
+
+func TestSlotIterator(t *testing.T) {
+	skipped := map[uint64]bool{100: true, 10_099: true, 10_100: true, 25_000: true}
+	latest := uint64(30_000)
+	server, ranges := mockGetBlocks(t, &latest, skipped)
+	defer server.Close()
+
+	it := newTestClient(server.URL).NewSlotIterator(100, 25_000, "")
+
+	var count uint64
+	previous := uint64(100)
+	for it.Next(context.Background()) {
+		slot := it.Slot()
+		assert.False(t, skipped[slot], "skipped slot %d returned", slot)
+		assert.True(t, slot > previous, "slot %d after %d", slot, previous)
+		previous = slot
+		count++
+	}
+	require.NoError(t, it.Err())
+
+	assert.Equal(t, uint64(24_897), count)
+	assert.Equal(t, uint64(24_999), previous)
+	assert.Equal(t, [][2]uint64{{100, 10_099}, {10_100, 20_099}, {20_100, 25_000}}, *ranges)
+	assert.False(t, it.Next(context.Background()))
+}
+
+func TestSlotIterator_Error(t *testing.T) {
+	server, closer := mockJSONRPC(t, json.RawMessage(`{"jsonrpc":"2.0","error":{"code":-32602,"message":"Slot range too large"},"id":0}`))
+	defer closer()
+
+	it := newTestClient(server.URL).NewSlotIterator(1, 2, "")
+	assert.False(t, it.Next(context.Background()))
+	assert.EqualError(t, it.Err(), "get latest slot: rpc response: -32602:Slot range too large")
+}
+
+func TestSlotIterator_PastLatestSlot(t *testing.T) {
+	latest := uint64(150)
+	server, ranges := mockGetBlocks(t, &latest, nil)
+	defer server.Close()
+
+	it := newTestClient(server.URL).NewSlotIterator(100, 300, CommitmentConfirmed)
+
+	var slots []uint64
+	for it.Next(context.Background()) {
+		slots = append(slots, it.Slot())
+		if it.Slot() == 150 {
+			// The node catches up while iterating
+			latest = 200
+		}
+	}
+
+	assert.Len(t, slots, 101)
+	assert.Equal(t, uint64(200), slots[len(slots)-1])
+	assert.EqualError(t, it.Err(), "slot 201 is past the latest slot 200 of the node")
+	assert.Equal(t, [][2]uint64{{100, 150}, {151, 200}}, *ranges)
+}
+
+func TestSlotIterator_EmptyRange(t *testing.T) {
+	it := NewClient("http://localhost:1").NewSlotIterator(10, 5, "")
+	assert.False(t, it.Next(context.Background()))
+	assert.NoError(t, it.Err())
+}
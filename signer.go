@@ -0,0 +1,19 @@
+package solana
+
+// Signer produces signatures for a given public key without exposing how the
+// key material is stored, so keys can live in a KMS, an HSM or another process.
+type Signer interface {
+	PublicKey() PublicKey
+	SignMessage(message []byte) (Signature, error)
+}
+
+var _ Signer = PrivateKey(nil)
+var _ Signer = (*Account)(nil)
+
+func (k PrivateKey) SignMessage(message []byte) (Signature, error) {
+	return k.Sign(message)
+}
+
+func (a *Account) SignMessage(message []byte) (Signature, error) {
+	return a.PrivateKey.Sign(message)
+}
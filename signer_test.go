@@ -0,0 +1,166 @@
+package solana
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	bin "github.com/streamingfast/binary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// remoteSigner simulates a signer that does not expose its key material
+type remoteSigner struct {
+	key   PrivateKey
+	calls int
+}
+
+func (s *remoteSigner) PublicKey() PublicKey { return s.key.PublicKey() }
+func (s *remoteSigner) SignMessage(message []byte) (Signature, error) {
+	s.calls++
+	return s.key.Sign(message)
+}
+
+func TestTransaction_Sign(t *testing.T) {
+	_, payerKey, err := NewRandomPrivateKey()
+	require.NoError(t, err)
+	_, otherKey, err := NewRandomPrivateKey()
+	require.NoError(t, err)
+	_, unusedKey, err := NewRandomPrivateKey()
+	require.NoError(t, err)
+
+	payer := &Account{PrivateKey: payerKey}
+	other := &remoteSigner{key: otherKey}
+
+	trx, err := NewTransaction([]Instruction{
+		&testTransactionInstructions{
+			accounts: []*AccountMeta{
+				{PublicKey: payer.PublicKey(), IsSigner: true, IsWritable: true},
+				{PublicKey: other.PublicKey(), IsSigner: true},
+			},
+			programID: MustPublicKeyFromBase58("11111111111111111111111111111111"),
+		},
+	}, MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn"))
+	require.NoError(t, err)
+
+	signatures, err := trx.Sign(other, unusedKey, payer)
+	require.NoError(t, err)
+	require.Len(t, signatures, 2)
+	assert.Equal(t, 1, other.calls)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, bin.NewEncoder(buf).Encode(trx.Message))
+	assert.True(t, signatures[0].Verify(payer.PublicKey(), buf.Bytes()))
+	assert.True(t, signatures[1].Verify(other.PublicKey(), buf.Bytes()))
+}
+
+func TestTransaction_Sign_MissingSigner(t *testing.T) {
+	_, payerKey, err := NewRandomPrivateKey()
+	require.NoError(t, err)
+
+	trx, err := NewTransaction([]Instruction{
+		&testTransactionInstructions{
+			accounts: []*AccountMeta{
+				{PublicKey: payerKey.PublicKey(), IsSigner: true, IsWritable: true},
+			},
+			programID: MustPublicKeyFromBase58("11111111111111111111111111111111"),
+		},
+	}, MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn"))
+	require.NoError(t, err)
+
+	_, err = trx.Sign()
+	assert.Error(t, err)
+}
+
+func TestTransaction_PartialSign_OfflineFlow(t *testing.T) {
+	_, payerKey, err := NewRandomPrivateKey()
+	require.NoError(t, err)
+	_, offlineKey, err := NewRandomPrivateKey()
+	require.NoError(t, err)
+
+	trx, err := NewTransaction([]Instruction{
+		&testTransactionInstructions{
+			accounts: []*AccountMeta{
+				{PublicKey: payerKey.PublicKey(), IsSigner: true, IsWritable: true},
+				{PublicKey: offlineKey.PublicKey(), IsSigner: true},
+			},
+			programID: MustPublicKeyFromBase58("11111111111111111111111111111111"),
+		},
+	}, MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn"))
+	require.NoError(t, err)
+
+	signatures, err := trx.PartialSign(payerKey)
+	require.NoError(t, err)
+	require.Len(t, signatures, 2)
+	assert.False(t, signatures[0].IsZero())
+	assert.True(t, signatures[1].IsZero())
+	assert.Equal(t, []PublicKey{offlineKey.PublicKey()}, trx.MissingSigners())
+	assert.Error(t, trx.VerifySignatures())
+
+	// The air-gapped party signs the exported message
+	exported, err := trx.MessageBase64()
+	require.NoError(t, err)
+	message, err := base64.StdEncoding.DecodeString(exported)
+	require.NoError(t, err)
+	offlineSignature, err := offlineKey.Sign(message)
+	require.NoError(t, err)
+
+	assert.Error(t, trx.AddSignature(offlineKey.PublicKey(), signatures[0]))
+	assert.Error(t, trx.AddSignature(MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn"), offlineSignature))
+	require.NoError(t, trx.AddSignature(offlineKey.PublicKey(), offlineSignature))
+
+	assert.Len(t, trx.MissingSigners(), 0)
+	require.NoError(t, trx.VerifySignatures())
+
+	// Signing again keeps the slots instead of appending
+	_, err = trx.Sign(payerKey)
+	require.NoError(t, err)
+	require.Len(t, trx.Signatures, 2)
+	require.NoError(t, trx.VerifySignatures())
+
+	encoded, err := trx.ToBase64()
+	require.NoError(t, err)
+	decoded, err := TransactionFromBase64(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, trx.Signatures, decoded.Signatures)
+	require.NoError(t, decoded.VerifySignatures())
+}
+
+func TestTransaction_Sign_StaleSignature(t *testing.T) {
+	_, payerKey, err := NewRandomPrivateKey()
+	require.NoError(t, err)
+	_, coSignerKey, err := NewRandomPrivateKey()
+	require.NoError(t, err)
+
+	trx, err := NewTransaction([]Instruction{
+		&testTransactionInstructions{
+			accounts: []*AccountMeta{
+				{PublicKey: payerKey.PublicKey(), IsSigner: true, IsWritable: true},
+				{PublicKey: coSignerKey.PublicKey(), IsSigner: true},
+			},
+			programID: MustPublicKeyFromBase58("11111111111111111111111111111111"),
+		},
+	}, MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn"))
+	require.NoError(t, err)
+
+	_, err = trx.Sign(payerKey, coSignerKey)
+	require.NoError(t, err)
+
+	// Refreshing the blockhash invalidates the co-signer's signature
+	trx.Message.RecentBlockhash = MustPublicKeyFromBase58("9hFtYBYmBJCVguRYs9pBTWKYAFoKfjYR7zBPpEkVsmD")
+	_, err = trx.Sign(payerKey)
+	assert.EqualError(t, err, fmt.Sprintf("signature of key %q does not match the message, it must be signed again", coSignerKey.PublicKey()))
+
+	_, err = trx.Sign(payerKey, coSignerKey)
+	require.NoError(t, err)
+	require.NoError(t, trx.VerifySignatures())
+
+	// Extra signatures are dropped
+	trx.Signatures = append(trx.Signatures, Signature{0x01})
+	_, err = trx.PartialSign(payerKey)
+	require.NoError(t, err)
+	require.Len(t, trx.Signatures, 2)
+	require.NoError(t, trx.VerifySignatures())
+}
@@ -2,9 +2,14 @@ package solana
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"math"
 	"sort"
 
+	"github.com/mr-tron/base58"
 	bin "github.com/streamingfast/binary"
 	"go.uber.org/zap"
 )
@@ -20,7 +25,17 @@ type TransactionOption interface {
 }
 
 type transactionOptions struct {
-	payer PublicKey
+	payer            PublicKey
+	addressTables    map[PublicKey][]PublicKey
+	computeUnitLimit *uint32
+	computeUnitPrice *uint64
+	durableNonce     *durableNonce
+}
+
+type durableNonce struct {
+	account   PublicKey
+	authority PublicKey
+	nonce     PublicKey
 }
 
 type transactionOptionFunc func(opts *transactionOptions)
@@ -33,6 +48,124 @@ func TransactionPayer(payer PublicKey) TransactionOption {
 	return transactionOptionFunc(func(opts *transactionOptions) { opts.payer = payer })
 }
 
+// TransactionAddressTables gives the already fetched lookup tables (table
+// address to its list of addresses) that can be used to compile the
+// transaction. When at least one of them makes the transaction smaller, a v0
+// message referencing it is produced, otherwise the message stays legacy.
+func TransactionAddressTables(tables map[PublicKey][]PublicKey) TransactionOption {
+	return transactionOptionFunc(func(opts *transactionOptions) { opts.addressTables = tables })
+}
+
+// TransactionComputeUnitLimit prepends a compute budget SetComputeUnitLimit
+// instruction to the transaction.
+func TransactionComputeUnitLimit(units uint32) TransactionOption {
+	return transactionOptionFunc(func(opts *transactionOptions) { opts.computeUnitLimit = &units })
+}
+
+// TransactionComputeUnitPrice prepends a compute budget SetComputeUnitPrice
+// instruction to the transaction, the priority fee being expressed in
+// micro-lamports per compute unit.
+func TransactionComputeUnitPrice(microLamports uint64) TransactionOption {
+	return transactionOptionFunc(func(opts *transactionOptions) { opts.computeUnitPrice = &microLamports })
+}
+
+// TransactionDurableNonce makes the transaction use the durable `nonce`
+// currently stored in `nonceAccount` instead of a recent blockhash, so it can be
+// signed long before being sent. The blockhash given to NewTransaction is
+// ignored and an AdvanceNonceAccount instruction, signed by `nonceAuthority`,
+// is put first as required by the runtime.
+func TransactionDurableNonce(nonceAccount, nonceAuthority, nonce PublicKey) TransactionOption {
+	return transactionOptionFunc(func(opts *transactionOptions) {
+		opts.durableNonce = &durableNonce{account: nonceAccount, authority: nonceAuthority, nonce: nonce}
+	})
+}
+
+// systemProgramID and sysvarRecentBlockhashesID are the same as the ones defined
+// in `programs/system`, which cannot be imported from here.
+var systemProgramID = MustPublicKeyFromBase58("11111111111111111111111111111111")
+var sysvarRecentBlockhashesID = MustPublicKeyFromBase58("SysvarRecentB1ockHashes11111111111111111111")
+
+const systemAdvanceNonceAccount uint32 = 4
+
+func (n *durableNonce) advanceInstruction() Instruction {
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, systemAdvanceNonceAccount)
+
+	return &rawInstruction{
+		programID: systemProgramID,
+		accounts: []*AccountMeta{
+			{PublicKey: n.account, IsWritable: true},
+			{PublicKey: sysvarRecentBlockhashesID},
+			{PublicKey: n.authority, IsSigner: true},
+		},
+		data: data,
+	}
+}
+
+// computeBudgetProgramID is the same as `computebudget.PROGRAM_ID`, the package
+// cannot be imported from here as it depends on this one.
+var computeBudgetProgramID = MustPublicKeyFromBase58("ComputeBudget111111111111111111111111111111")
+
+const (
+	computeBudgetSetComputeUnitLimit uint8 = 2
+	computeBudgetSetComputeUnitPrice uint8 = 3
+)
+
+type rawInstruction struct {
+	programID PublicKey
+	accounts  []*AccountMeta
+	data      []byte
+}
+
+func (i *rawInstruction) Accounts() []*AccountMeta { return i.accounts }
+func (i *rawInstruction) ProgramID() PublicKey     { return i.programID }
+func (i *rawInstruction) Data() ([]byte, error)    { return i.data, nil }
+
+// computeBudgetInstructions returns the compute budget instructions requested
+// through the options, failing if the caller already provided the same ones as
+// the runtime rejects duplicated compute budget instructions.
+func (o *transactionOptions) computeBudgetInstructions(instructions []Instruction) ([]Instruction, error) {
+	var out []Instruction
+	if o.computeUnitLimit != nil {
+		data := make([]byte, 5)
+		data[0] = computeBudgetSetComputeUnitLimit
+		binary.LittleEndian.PutUint32(data[1:], *o.computeUnitLimit)
+		out = append(out, &rawInstruction{programID: computeBudgetProgramID, data: data})
+	}
+	if o.computeUnitPrice != nil {
+		data := make([]byte, 9)
+		data[0] = computeBudgetSetComputeUnitPrice
+		binary.LittleEndian.PutUint64(data[1:], *o.computeUnitPrice)
+		out = append(out, &rawInstruction{programID: computeBudgetProgramID, data: data})
+	}
+
+	for idx, instruction := range instructions {
+		if !instruction.ProgramID().Equals(computeBudgetProgramID) {
+			continue
+		}
+		data, err := instruction.Data()
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode instructions [%d]: %w", idx, err)
+		}
+		if len(data) == 0 {
+			continue
+		}
+		if data[0] == computeBudgetSetComputeUnitLimit && o.computeUnitLimit != nil {
+			return nil, fmt.Errorf("compute unit limit set both by option and by instruction [%d]", idx)
+		}
+		if data[0] == computeBudgetSetComputeUnitPrice && o.computeUnitPrice != nil {
+			return nil, fmt.Errorf("compute unit price set both by option and by instruction [%d]", idx)
+		}
+	}
+	return out, nil
+}
+
+// MaxTransactionSize is the maximum size of a serialized transaction, signatures
+// included, that fits in a single network packet.
+const MaxTransactionSize = 1232
+
+var ErrTransactionTooLarge = errors.New("transaction too large")
+
 func NewTransaction(instructions []Instruction, blockHash PublicKey, opts ...TransactionOption) (*Transaction, error) {
 	if len(instructions) == 0 {
 		return nil, fmt.Errorf("requires at-least one instruction to create a transaction")
@@ -58,84 +191,46 @@ func NewTransaction(instructions []Instruction, blockHash PublicKey, opts ...Tra
 		}
 	}
 
-	programIDs := []PublicKey{}
-	accounts := []*AccountMeta{}
-	for _, instruction := range instructions {
-		for _, key := range instruction.Accounts() {
-			accounts = append(accounts, key)
-		}
-		programIDs = append(programIDs, instruction.ProgramID())
+	// Prepended after the fee payer is resolved since the fallback looks at the
+	// caller's first instruction
+	budgetInstructions, err := options.computeBudgetInstructions(instructions)
+	if err != nil {
+		return nil, err
 	}
-
-	// Add programID to the account list
-	for _, programID := range programIDs {
-		accounts = append(accounts, &AccountMeta{
-			PublicKey:  programID,
-			IsSigner:   false,
-			IsWritable: false,
-		})
+	if len(budgetInstructions) > 0 {
+		instructions = append(budgetInstructions, instructions...)
 	}
-
-	// Sort. Prioritizing first by signer, then by writable
-	sort.SliceStable(accounts, func(i, j int) bool {
-		return accounts[i].less(accounts[j])
-	})
-
-	uniqAccountsMap := map[PublicKey]uint64{}
-	uniqAccounts := []*AccountMeta{}
-	for _, acc := range accounts {
-		if index, found := uniqAccountsMap[acc.PublicKey]; found {
-			uniqAccounts[index].IsWritable = uniqAccounts[index].IsWritable || acc.IsWritable
-			continue
-		}
-		uniqAccounts = append(uniqAccounts, acc)
-		uniqAccountsMap[acc.PublicKey] = uint64(len(uniqAccounts) - 1)
+	if options.durableNonce != nil {
+		instructions = append([]Instruction{options.durableNonce.advanceInstruction()}, instructions...)
+		blockHash = options.durableNonce.nonce
 	}
 
-	zlog.Debug("unique account sorted", zap.Int("account_count", len(uniqAccounts)))
-	// Move fee payer to the front
-	feePayerIndex := -1
-	for idx, acc := range uniqAccounts {
-		if acc.PublicKey.Equals(feePayer) {
-			feePayerIndex = idx
-		}
+	finalAccounts, err := compileKeys(instructions, feePayer)
+	if err != nil {
+		return nil, err
 	}
-	zlog.Debug("current fee payer index", zap.Int("fee_payer_index", feePayerIndex))
 
-	accountCount := len(uniqAccounts)
-	if feePayerIndex < 0 {
-		// fee payer is not part of accounts we want to add it
-		accountCount++
+	message := Message{
+		RecentBlockhash: blockHash,
 	}
-	finalAccounts := make([]*AccountMeta, accountCount)
 
-	itr := 1
-	for idx, uniqAccount := range uniqAccounts {
-		if idx == feePayerIndex {
-			uniqAccount.IsSigner = true
-			uniqAccount.IsWritable = true
-			finalAccounts[0] = uniqAccount
-			continue
-		}
-		finalAccounts[itr] = uniqAccount
-		itr++
-	}
-	if feePayerIndex < 0 {
-		// fee payer is not part of accounts we want to add it
-		finalAccounts[0] = &AccountMeta{
-			PublicKey:  feePayer,
-			IsSigner:   true,
-			IsWritable: true,
+	if len(options.addressTables) > 0 {
+		staticAccounts, lookups, loaded := compileAddressTableLookups(finalAccounts, options.addressTables)
+		if len(lookups) > 0 {
+			zlog.Debug("moving accounts to address table lookups",
+				zap.Int("lookup_table_count", len(lookups)),
+				zap.Int("loaded_writable_count", len(loaded.Writable)),
+				zap.Int("loaded_readonly_count", len(loaded.Readonly)),
+			)
+			finalAccounts = staticAccounts
+			message.Version = MessageVersionV0
+			message.AddressTableLookups = lookups
+			message.SetLoadedAddresses(loaded)
 		}
 	}
 
-	message := Message{
-		RecentBlockhash: blockHash,
-	}
-	accountKeyIndex := map[string]uint8{}
-	for idx, acc := range finalAccounts {
+	for _, acc := range finalAccounts {
 		message.AccountKeys = append(message.AccountKeys, acc.PublicKey)
-		accountKeyIndex[acc.PublicKey.String()] = uint8(idx)
 		if acc.IsSigner {
 			message.Header.NumRequiredSignatures++
 			if !acc.IsWritable {
@@ -154,18 +249,23 @@ func NewTransaction(instructions []Instruction, blockHash PublicKey, opts ...Tra
 		zap.Uint8("num_readonly_unsigned_accounts", message.Header.NumReadonlyUnsignedAccounts),
 	)
 
+	accountKeyIndex := map[PublicKey]uint8{}
+	for idx, key := range message.AllAccountKeys() {
+		accountKeyIndex[key] = uint8(idx)
+	}
+
 	for trxIdx, instruction := range instructions {
-		accounts = instruction.Accounts()
+		accounts := instruction.Accounts()
 		accountIndex := make([]uint8, len(accounts))
 		for idx, acc := range accounts {
-			accountIndex[idx] = accountKeyIndex[acc.PublicKey.String()]
+			accountIndex[idx] = accountKeyIndex[acc.PublicKey]
 		}
 		data, err := instruction.Data()
 		if err != nil {
 			return nil, fmt.Errorf("unable to encode instructions [%d]: %w", trxIdx, err)
 		}
 		message.Instructions = append(message.Instructions, CompiledInstruction{
-			ProgramIDIndex: accountKeyIndex[instruction.ProgramID().String()],
+			ProgramIDIndex: accountKeyIndex[instruction.ProgramID()],
 			AccountCount:   bin.Varuint16(uint16(len(accountIndex))),
 			Accounts:       accountIndex,
 			DataLength:     bin.Varuint16(uint16(len(data))),
@@ -173,34 +273,406 @@ func NewTransaction(instructions []Instruction, blockHash PublicKey, opts ...Tra
 		})
 	}
 
-	return &Transaction{
+	trx := &Transaction{
 		Message: message,
-	}, nil
+	}
+
+	size, err := trx.EncodedSize()
+	if err != nil {
+		return nil, err
+	}
+	zlog.Debug("transaction compiled", zap.Stringer("version", message.Version), zap.Int("size", size))
+
+	if size > MaxTransactionSize {
+		return nil, fmt.Errorf("%w: %d bytes, maximum is %d bytes", ErrTransactionTooLarge, size, MaxTransactionSize)
+	}
+
+	return trx, nil
 }
 
-type privateKeyGetter func(key PublicKey) *PrivateKey
+// maxAccountKeys is the maximum number of accounts a message can reference,
+// static and loaded ones combined, as instructions index them with a single byte.
+const maxAccountKeys = 256
+
+// compiledKey is an account referenced by the transaction with the flags merged
+// across all its occurrences.
+type compiledKey struct {
+	PublicKey  PublicKey
+	IsSigner   bool
+	IsWritable bool
+	IsInvoked  bool
+}
 
-func (t *Transaction) Sign(getter privateKeyGetter) (out []Signature, err error) {
+// compileKeys returns the unique accounts used by the instructions, programs
+// included, in the same order as the validator's `CompiledKeys`: the fee payer
+// first, then writable signers, readonly signers, writable non-signers and
+// readonly non-signers, each group sorted by public key bytes. The caller's
+// account metas are never modified.
+func compileKeys(instructions []Instruction, feePayer PublicKey) ([]*compiledKey, error) {
+	keys := map[PublicKey]*compiledKey{}
+	getOrCreate := func(key PublicKey) *compiledKey {
+		compiled, found := keys[key]
+		if !found {
+			compiled = &compiledKey{PublicKey: key}
+			keys[key] = compiled
+		}
+		return compiled
+	}
+
+	for _, instruction := range instructions {
+		getOrCreate(instruction.ProgramID()).IsInvoked = true
+		for _, acc := range instruction.Accounts() {
+			compiled := getOrCreate(acc.PublicKey)
+			compiled.IsSigner = compiled.IsSigner || acc.IsSigner
+			compiled.IsWritable = compiled.IsWritable || acc.IsWritable
+		}
+	}
+
+	payer := getOrCreate(feePayer)
+	payer.IsSigner = true
+	payer.IsWritable = true
+
+	var writableSigners, readonlySigners, writableNonSigners, readonlyNonSigners []*compiledKey
+	for key, compiled := range keys {
+		switch {
+		case key == feePayer:
+		case compiled.IsSigner && compiled.IsWritable:
+			writableSigners = append(writableSigners, compiled)
+		case compiled.IsSigner:
+			readonlySigners = append(readonlySigners, compiled)
+		case compiled.IsWritable:
+			writableNonSigners = append(writableNonSigners, compiled)
+		default:
+			readonlyNonSigners = append(readonlyNonSigners, compiled)
+		}
+	}
+
+	signerCount := 1 + len(writableSigners) + len(readonlySigners)
+	if signerCount > math.MaxUint8 || len(readonlySigners) > math.MaxUint8 || len(readonlyNonSigners) > math.MaxUint8 {
+		return nil, fmt.Errorf("too many accounts for message header: %d signers, %d readonly signers, %d readonly non-signers", signerCount, len(readonlySigners), len(readonlyNonSigners))
+	}
+	if len(keys) > maxAccountKeys {
+		return nil, fmt.Errorf("transaction references %d accounts, maximum is %d", len(keys), maxAccountKeys)
+	}
+
+	out := make([]*compiledKey, 0, len(keys))
+	out = append(out, payer)
+	for _, group := range [][]*compiledKey{writableSigners, readonlySigners, writableNonSigners, readonlyNonSigners} {
+		sort.Slice(group, func(i, j int) bool {
+			return bytes.Compare(group[i].PublicKey[:], group[j].PublicKey[:]) < 0
+		})
+		out = append(out, group...)
+	}
+
+	zlog.Debug("accounts compiled", zap.Int("account_count", len(out)))
+	return out, nil
+}
+
+// compileAddressTableLookups moves as many accounts as possible out of the
+// static account keys into lookups against the given tables. Signers and
+// invoked programs must stay static. A key costs 32 bytes inline but only one
+// index byte through a lookup, while each lookup costs 34 bytes (table address
+// and two length prefixes), so a table is only used when it replaces at least
+// two keys. Tables are picked greedily, the one covering the most remaining
+// keys first.
+func compileAddressTableLookups(
+	accounts []*compiledKey,
+	tables map[PublicKey][]PublicKey,
+) (static []*compiledKey, lookups []MessageAddressTableLookup, loaded LoadedAddresses) {
+	candidates := map[PublicKey]*compiledKey{}
+	for _, acc := range accounts {
+		if acc.IsSigner || acc.IsInvoked {
+			continue
+		}
+		candidates[acc.PublicKey] = acc
+	}
+
+	// Iterate tables in a stable order so compilation is deterministic
+	tableKeys := make([]PublicKey, 0, len(tables))
+	for key := range tables {
+		tableKeys = append(tableKeys, key)
+	}
+	sort.Slice(tableKeys, func(i, j int) bool {
+		return bytes.Compare(tableKeys[i][:], tableKeys[j][:]) < 0
+	})
+
+	tableIndexes := make(map[PublicKey]map[PublicKey]uint8, len(tables))
+	for _, tableKey := range tableKeys {
+		indexes := map[PublicKey]uint8{}
+		for idx, address := range tables[tableKey] {
+			if idx > math.MaxUint8 {
+				// Lookups index tables with a single byte
+				break
+			}
+			if _, found := indexes[address]; !found {
+				indexes[address] = uint8(idx)
+			}
+		}
+		tableIndexes[tableKey] = indexes
+	}
+
+	moved := map[PublicKey]bool{}
+	for {
+		bestTable := -1
+		bestCount := 1
+		for i, tableKey := range tableKeys {
+			count := 0
+			for key := range candidates {
+				if _, found := tableIndexes[tableKey][key]; found {
+					count++
+				}
+			}
+			if count > bestCount {
+				bestTable = i
+				bestCount = count
+			}
+		}
+		if bestTable < 0 {
+			break
+		}
+
+		tableKey := tableKeys[bestTable]
+		lookup := MessageAddressTableLookup{AccountKey: tableKey}
+		var writable, readonly []PublicKey
+		// Walk accounts rather than the candidates map to keep their relative order
+		for _, acc := range accounts {
+			if _, found := candidates[acc.PublicKey]; !found {
+				continue
+			}
+			index, found := tableIndexes[tableKey][acc.PublicKey]
+			if !found {
+				continue
+			}
+
+			if acc.IsWritable {
+				lookup.WritableIndexes = append(lookup.WritableIndexes, index)
+				writable = append(writable, acc.PublicKey)
+			} else {
+				lookup.ReadonlyIndexes = append(lookup.ReadonlyIndexes, index)
+				readonly = append(readonly, acc.PublicKey)
+			}
+			moved[acc.PublicKey] = true
+			delete(candidates, acc.PublicKey)
+		}
+
+		lookups = append(lookups, lookup)
+		loaded.Writable = append(loaded.Writable, writable...)
+		loaded.Readonly = append(loaded.Readonly, readonly...)
+	}
+
+	for _, acc := range accounts {
+		if !moved[acc.PublicKey] {
+			static = append(static, acc)
+		}
+	}
+	return
+}
+
+// EncodedSize returns the size in bytes of the serialized transaction once all
+// its required signatures are present, which is the size checked against
+// MaxTransactionSize by the cluster.
+func (t *Transaction) EncodedSize() (int, error) {
+	buf := new(bytes.Buffer)
+	if err := bin.NewEncoder(buf).Encode(t.Message); err != nil {
+		return 0, fmt.Errorf("unable to encode message: %w", err)
+	}
+
+	signatureCount := int(t.Message.Header.NumRequiredSignatures)
+	return shortVecEncodedLen(signatureCount) + signatureCount*64 + buf.Len(), nil
+}
+
+func shortVecEncodedLen(value int) int {
+	size := 1
+	for value >= 0x80 {
+		value >>= 7
+		size++
+	}
+	return size
+}
+
+// MarshalMessage returns the serialized message, the payload every signer
+// must sign.
+func (t *Transaction) MarshalMessage() ([]byte, error) {
 	buf := new(bytes.Buffer)
-	if err = bin.NewEncoder(buf).Encode(t.Message); err != nil {
+	if err := bin.NewEncoder(buf).Encode(t.Message); err != nil {
+		return nil, fmt.Errorf("unable to encode message: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// MessageBase58 returns the serialized message encoded in base58, to be handed
+// to an offline signer.
+func (t *Transaction) MessageBase58() (string, error) {
+	cnt, err := t.MarshalMessage()
+	if err != nil {
+		return "", err
+	}
+	return base58.Encode(cnt), nil
+}
+
+// MessageBase64 returns the serialized message encoded in base64, to be handed
+// to an offline signer.
+func (t *Transaction) MessageBase64() (string, error) {
+	cnt, err := t.MarshalMessage()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(cnt), nil
+}
+
+// Sign signs the message with the signer matching each key required by the
+// message, failing if any of them is missing. Signatures already attached for
+// the other keys, by a previous PartialSign or AddSignature, are kept only if
+// they are valid for the current message: changing the message, like its
+// recent blockhash, requires them to be produced again. Signers not required
+// by the message are ignored.
+func (t *Transaction) Sign(signers ...Signer) (out []Signature, err error) {
+	signerByKey := make(map[PublicKey]bool, len(signers))
+	for _, signer := range signers {
+		signerByKey[signer.PublicKey()] = true
+	}
+
+	var messageCnt []byte
+	for idx, key := range t.Message.signerKeys() {
+		if signerByKey[key] {
+			continue
+		}
+		if idx >= len(t.Signatures) || t.Signatures[idx].IsZero() {
+			return nil, fmt.Errorf("signer key %q not found. Ensure all the signer keys are provided", key.String())
+		}
+
+		if messageCnt == nil {
+			if messageCnt, err = t.MarshalMessage(); err != nil {
+				return nil, fmt.Errorf("unable to encode message for signing: %w", err)
+			}
+		}
+		if !t.Signatures[idx].Verify(key, messageCnt) {
+			return nil, fmt.Errorf("signature of key %q does not match the message, it must be signed again", key.String())
+		}
+	}
+
+	return t.PartialSign(signers...)
+}
+
+// PartialSign fills the signature slots of the given signers, leaving the
+// other slots untouched (zeroed when not signed yet) so the remaining parties
+// can sign later on. Signers not required by the message are ignored.
+func (t *Transaction) PartialSign(signers ...Signer) (out []Signature, err error) {
+	messageCnt, err := t.MarshalMessage()
+	if err != nil {
 		return nil, fmt.Errorf("unable to encode message for signing: %w", err)
 	}
-	messageCnt := buf.Bytes()
 
-	signerKeys := t.Message.signerKeys()
+	signerByKey := make(map[PublicKey]Signer, len(signers))
+	for _, signer := range signers {
+		signerByKey[signer.PublicKey()] = signer
+	}
 
-	for _, key := range signerKeys {
-		privateKey := getter(key)
-		if privateKey == nil {
-			return nil, fmt.Errorf("signer key %q not found. Ensure all the signer keys are in the vault", key.String())
+	t.ensureSignatureSlots()
+	for idx, key := range t.Message.signerKeys() {
+		signer, found := signerByKey[key]
+		if !found {
+			continue
 		}
 
-		s, err := privateKey.Sign(messageCnt)
+		s, err := signer.SignMessage(messageCnt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to signed with key %q: %w", key.String(), err)
 		}
 
-		t.Signatures = append(t.Signatures, s)
+		t.Signatures[idx] = s
 	}
 	return t.Signatures, nil
 }
+
+// AddSignature attaches a signature produced elsewhere for the given signer
+// key. The signature is verified against the message before being added.
+func (t *Transaction) AddSignature(key PublicKey, signature Signature) error {
+	index := -1
+	for idx, signerKey := range t.Message.signerKeys() {
+		if signerKey.Equals(key) {
+			index = idx
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("key %q is not a signer of the transaction", key.String())
+	}
+
+	messageCnt, err := t.MarshalMessage()
+	if err != nil {
+		return err
+	}
+	if !signature.Verify(key, messageCnt) {
+		return fmt.Errorf("invalid signature for key %q", key.String())
+	}
+
+	t.ensureSignatureSlots()
+	t.Signatures[index] = signature
+	return nil
+}
+
+// MissingSigners returns the signer keys for which the transaction does not
+// have a signature yet.
+func (t *Transaction) MissingSigners() (out []PublicKey) {
+	for idx, key := range t.Message.signerKeys() {
+		if idx >= len(t.Signatures) || t.Signatures[idx].IsZero() {
+			out = append(out, key)
+		}
+	}
+	return
+}
+
+// VerifySignatures checks that the transaction holds exactly one valid
+// signature for each signer key required by its message.
+func (t *Transaction) VerifySignatures() error {
+	signerKeys := t.Message.signerKeys()
+	if len(t.Signatures) != len(signerKeys) {
+		return fmt.Errorf("expected %d signatures, got %d", len(signerKeys), len(t.Signatures))
+	}
+
+	messageCnt, err := t.MarshalMessage()
+	if err != nil {
+		return err
+	}
+
+	for idx, key := range signerKeys {
+		if t.Signatures[idx].IsZero() {
+			return fmt.Errorf("missing signature for key %q", key.String())
+		}
+		if !t.Signatures[idx].Verify(key, messageCnt) {
+			return fmt.Errorf("invalid signature for key %q", key.String())
+		}
+	}
+	return nil
+}
+
+// ensureSignatureSlots sizes the signatures to the number of signatures
+// required by the message, dropping extra ones and zeroing missing ones.
+func (t *Transaction) ensureSignatureSlots() {
+	required := int(t.Message.Header.NumRequiredSignatures)
+	if len(t.Signatures) > required {
+		t.Signatures = t.Signatures[:required]
+	}
+	if len(t.Signatures) < required {
+		t.Signatures = append(t.Signatures, make([]Signature, required-len(t.Signatures))...)
+	}
+}
+
+// ToBase64 returns the serialized transaction, signatures included, encoded in
+// base64 as expected by the `sendTransaction` RPC call.
+func (t *Transaction) ToBase64() (string, error) {
+	buf := new(bytes.Buffer)
+	if err := bin.NewEncoder(buf).Encode(t); err != nil {
+		return "", fmt.Errorf("unable to encode transaction: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func TransactionFromBase64(in string) (*Transaction, error) {
+	data, err := base64.StdEncoding.DecodeString(in)
+	if err != nil {
+		return nil, fmt.Errorf("base64 decode: %w", err)
+	}
+	return TransactionFromData(data)
+}
@@ -1,9 +1,12 @@
 package solana
 
 import (
+	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/magiconair/properties/assert"
+	bin "github.com/streamingfast/binary"
 	"github.com/stretchr/testify/require"
 )
 
@@ -66,14 +69,14 @@ func TestNewTransaction(t *testing.T) {
 		MustPublicKeyFromBase58("9hFtYBYmBJCVguRYs9pBTWKYAFoKfjYR7zBPpEkVsmD"),
 		MustPublicKeyFromBase58("6FzXPEhCJoBx7Zw3SN9qhekHemd6E2b8kVguitmVAngW"),
 		MustPublicKeyFromBase58("SysvarS1otHashes111111111111111111111111111"),
-		MustPublicKeyFromBase58("SysvarC1ock11111111111111111111111111111111"),
 		MustPublicKeyFromBase58("11111111111111111111111111111111"),
+		MustPublicKeyFromBase58("SysvarC1ock11111111111111111111111111111111"),
 		MustPublicKeyFromBase58("Vote111111111111111111111111111111111111111"),
 	})
 
 	assert.Equal(t, trx.Message.Instructions, []CompiledInstruction{
 		{
-			ProgramIDIndex: 5,
+			ProgramIDIndex: 4,
 			AccountCount:   2,
 			Accounts:       []uint8{0, 01},
 			DataLength:     2,
@@ -82,9 +85,248 @@ func TestNewTransaction(t *testing.T) {
 		{
 			ProgramIDIndex: 6,
 			AccountCount:   4,
-			Accounts:       []uint8{4, 3, 1, 2},
+			Accounts:       []uint8{5, 3, 1, 2},
 			DataLength:     2,
 			Data:           []byte{0xcc, 0xdd},
 		},
 	})
 }
+
+func TestNewTransaction_AddressTables(t *testing.T) {
+	_, signerKey, err := NewRandomPrivateKey()
+	require.NoError(t, err)
+	signer := signerKey.PublicKey()
+
+	programID := MustPublicKeyFromBase58("Vote111111111111111111111111111111111111111")
+	writable1 := MustPublicKeyFromBase58("SysvarS1otHashes111111111111111111111111111")
+	writable2 := MustPublicKeyFromBase58("9hFtYBYmBJCVguRYs9pBTWKYAFoKfjYR7zBPpEkVsmD")
+	readonly1 := MustPublicKeyFromBase58("SysvarC1ock11111111111111111111111111111111")
+	lonely := MustPublicKeyFromBase58("6FzXPEhCJoBx7Zw3SN9qhekHemd6E2b8kVguitmVAngW")
+
+	table := MustPublicKeyFromBase58("2kPGkTUzGZDTwxamiC99vggZZ52Dj6TKLNTErXmbNVwt")
+	smallTable := MustPublicKeyFromBase58("Gr5UanqwiKA54GGnw4b1bB5M8eatQzj6s6FQ9FeTze5C")
+
+	instructions := []Instruction{
+		&testTransactionInstructions{
+			accounts: []*AccountMeta{
+				{PublicKey: signer, IsSigner: true, IsWritable: true},
+				{PublicKey: writable1, IsWritable: true},
+				{PublicKey: readonly1},
+				{PublicKey: writable2, IsWritable: true},
+				{PublicKey: lonely},
+			},
+			data:      []byte{0xaa},
+			programID: programID,
+		},
+	}
+
+	blockhash := MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn")
+	trx, err := NewTransaction(instructions, blockhash, TransactionAddressTables(map[PublicKey][]PublicKey{
+		// Signer and invoked program must never be moved to a lookup
+		table:      {signer, programID, readonly1, writable2, writable1},
+		smallTable: {lonely},
+	}))
+	require.NoError(t, err)
+
+	require.Equal(t, MessageVersionV0, trx.Message.Version)
+	require.Equal(t, []PublicKey{signer, programID, lonely}, trx.Message.AccountKeys)
+	require.Equal(t, MessageHeader{
+		NumRequiredSignatures:       1,
+		NumReadonlySignedAccounts:   0,
+		NumReadonlyUnsignedAccounts: 2,
+	}, trx.Message.Header)
+	require.Equal(t, []MessageAddressTableLookup{
+		{AccountKey: table, WritableIndexes: []uint8{3, 4}, ReadonlyIndexes: []uint8{2}},
+	}, trx.Message.AddressTableLookups)
+
+	require.Len(t, trx.Message.Instructions, 1)
+	require.Equal(t, []uint8{0, 4, 5, 3, 2}, trx.Message.Instructions[0].Accounts)
+	require.Equal(t, uint8(1), trx.Message.Instructions[0].ProgramIDIndex)
+
+	require.True(t, trx.IsWritable(writable1))
+	require.True(t, trx.IsWritable(writable2))
+	require.False(t, trx.IsWritable(readonly1))
+
+	_, err = trx.Sign(signerKey)
+	require.NoError(t, err)
+
+	size, err := trx.EncodedSize()
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, bin.NewEncoder(buf).Encode(trx))
+	require.Equal(t, buf.Len(), size)
+}
+
+func TestNewTransaction_AddressTables_NotWorthIt(t *testing.T) {
+	signer := MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn")
+	other := MustPublicKeyFromBase58("9hFtYBYmBJCVguRYs9pBTWKYAFoKfjYR7zBPpEkVsmD")
+
+	instructions := []Instruction{
+		&testTransactionInstructions{
+			accounts: []*AccountMeta{
+				{PublicKey: signer, IsSigner: true, IsWritable: true},
+				{PublicKey: other, IsWritable: true},
+			},
+			programID: MustPublicKeyFromBase58("11111111111111111111111111111111"),
+		},
+	}
+
+	trx, err := NewTransaction(instructions, signer, TransactionAddressTables(map[PublicKey][]PublicKey{
+		MustPublicKeyFromBase58("2kPGkTUzGZDTwxamiC99vggZZ52Dj6TKLNTErXmbNVwt"): {other},
+	}))
+	require.NoError(t, err)
+
+	require.Equal(t, MessageVersionLegacy, trx.Message.Version)
+	require.Len(t, trx.Message.AddressTableLookups, 0)
+	require.Len(t, trx.Message.AccountKeys, 3)
+}
+
+func TestNewTransaction_TooLarge(t *testing.T) {
+	instructions := []Instruction{
+		&testTransactionInstructions{
+			accounts: []*AccountMeta{
+				{PublicKey: MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn"), IsSigner: true, IsWritable: true},
+			},
+			data:      make([]byte, MaxTransactionSize),
+			programID: MustPublicKeyFromBase58("11111111111111111111111111111111"),
+		},
+	}
+
+	_, err := NewTransaction(instructions, MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn"))
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrTransactionTooLarge))
+}
+
+func TestNewTransaction_ComputeBudget(t *testing.T) {
+	signer := MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn")
+	instructions := []Instruction{
+		&testTransactionInstructions{
+			accounts: []*AccountMeta{
+				{PublicKey: signer, IsSigner: true, IsWritable: true},
+			},
+			data:      []byte{0xaa},
+			programID: MustPublicKeyFromBase58("11111111111111111111111111111111"),
+		},
+	}
+
+	trx, err := NewTransaction(instructions, signer, TransactionComputeUnitLimit(200000), TransactionComputeUnitPrice(10000))
+	require.NoError(t, err)
+
+	require.Equal(t, []PublicKey{
+		signer,
+		MustPublicKeyFromBase58("11111111111111111111111111111111"),
+		MustPublicKeyFromBase58("ComputeBudget111111111111111111111111111111"),
+	}, trx.Message.AccountKeys)
+	require.Len(t, trx.Message.Instructions, 3)
+	require.Equal(t, uint8(2), trx.Message.Instructions[0].ProgramIDIndex)
+	require.Equal(t, Base58{0x02, 0x40, 0x0d, 0x03, 0x00}, trx.Message.Instructions[0].Data)
+	require.Equal(t, uint8(2), trx.Message.Instructions[1].ProgramIDIndex)
+	require.Equal(t, Base58{0x03, 0x10, 0x27, 0, 0, 0, 0, 0, 0}, trx.Message.Instructions[1].Data)
+	require.Equal(t, Base58{0xaa}, trx.Message.Instructions[2].Data)
+}
+
+func TestNewTransaction_ComputeBudget_Duplicated(t *testing.T) {
+	signer := MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn")
+	instructions := []Instruction{
+		&testTransactionInstructions{
+			accounts:  []*AccountMeta{{PublicKey: signer, IsSigner: true, IsWritable: true}},
+			programID: MustPublicKeyFromBase58("11111111111111111111111111111111"),
+		},
+		&testTransactionInstructions{
+			data:      []byte{0x03, 0x10, 0x27, 0, 0, 0, 0, 0, 0},
+			programID: MustPublicKeyFromBase58("ComputeBudget111111111111111111111111111111"),
+		},
+	}
+
+	_, err := NewTransaction(instructions, signer, TransactionComputeUnitPrice(10000))
+	require.Error(t, err)
+}
+
+func TestNewTransaction_MergesFlagsWithoutMutatingInputs(t *testing.T) {
+	payer := MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn")
+	shared := MustPublicKeyFromBase58("9hFtYBYmBJCVguRYs9pBTWKYAFoKfjYR7zBPpEkVsmD")
+
+	payerMeta := &AccountMeta{PublicKey: payer, IsSigner: true}
+	sharedReadonlySigner := &AccountMeta{PublicKey: shared, IsSigner: true}
+	sharedWritable := &AccountMeta{PublicKey: shared, IsWritable: true}
+
+	instructions := []Instruction{
+		&testTransactionInstructions{
+			accounts:  []*AccountMeta{payerMeta, sharedReadonlySigner},
+			programID: MustPublicKeyFromBase58("11111111111111111111111111111111"),
+		},
+		&testTransactionInstructions{
+			accounts:  []*AccountMeta{sharedWritable},
+			programID: MustPublicKeyFromBase58("Vote111111111111111111111111111111111111111"),
+		},
+	}
+
+	trx, err := NewTransaction(instructions, payer)
+	require.NoError(t, err)
+
+	require.Equal(t, MessageHeader{
+		NumRequiredSignatures:       2,
+		NumReadonlySignedAccounts:   0,
+		NumReadonlyUnsignedAccounts: 2,
+	}, trx.Message.Header)
+	require.Equal(t, []PublicKey{payer, shared}, trx.Message.AccountKeys[:2])
+	require.True(t, trx.IsSigner(shared))
+	require.True(t, trx.IsWritable(shared))
+
+	require.Equal(t, &AccountMeta{PublicKey: payer, IsSigner: true}, payerMeta)
+	require.Equal(t, &AccountMeta{PublicKey: shared, IsSigner: true}, sharedReadonlySigner)
+	require.Equal(t, &AccountMeta{PublicKey: shared, IsWritable: true}, sharedWritable)
+}
+
+func TestNewTransaction_TooManyAccounts(t *testing.T) {
+	payer := MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn")
+
+	accounts := []*AccountMeta{{PublicKey: payer, IsSigner: true, IsWritable: true}}
+	for i := 0; i < 256; i++ {
+		var key PublicKey
+		key[0], key[1] = 1, byte(i)
+		accounts = append(accounts, &AccountMeta{PublicKey: key})
+	}
+
+	_, err := NewTransaction([]Instruction{
+		&testTransactionInstructions{
+			accounts:  accounts,
+			programID: MustPublicKeyFromBase58("11111111111111111111111111111111"),
+		},
+	}, payer)
+	require.Error(t, err)
+}
+
+func TestNewTransaction_DurableNonce(t *testing.T) {
+	payer := MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn")
+	nonceAccount := MustPublicKeyFromBase58("2kPGkTUzGZDTwxamiC99vggZZ52Dj6TKLNTErXmbNVwt")
+	nonce := MustPublicKeyFromBase58("Gr5UanqwiKA54GGnw4b1bB5M8eatQzj6s6FQ9FeTze5C")
+
+	instructions := []Instruction{
+		&testTransactionInstructions{
+			accounts:  []*AccountMeta{{PublicKey: payer, IsSigner: true, IsWritable: true}},
+			data:      []byte{0xaa},
+			programID: MustPublicKeyFromBase58("Vote111111111111111111111111111111111111111"),
+		},
+	}
+
+	trx, err := NewTransaction(instructions, PublicKey{}, TransactionDurableNonce(nonceAccount, payer, nonce), TransactionComputeUnitPrice(1))
+	require.NoError(t, err)
+
+	require.Equal(t, nonce, trx.Message.RecentBlockhash)
+	require.Len(t, trx.Message.Instructions, 3)
+
+	advance := trx.Message.Instructions[0]
+	programID, err := trx.ResolveProgramIDIndex(advance.ProgramIDIndex)
+	require.NoError(t, err)
+	require.Equal(t, MustPublicKeyFromBase58("11111111111111111111111111111111"), programID)
+	require.Equal(t, Base58{0x04, 0x00, 0x00, 0x00}, advance.Data)
+
+	accounts, err := advance.ResolveInstructionAccounts(&trx.Message)
+	require.NoError(t, err)
+	require.Len(t, accounts, 3)
+	require.Equal(t, &AccountMeta{PublicKey: nonceAccount, IsWritable: true}, accounts[0])
+	require.Equal(t, &AccountMeta{PublicKey: MustPublicKeyFromBase58("SysvarRecentB1ockHashes11111111111111111111")}, accounts[1])
+	require.Equal(t, &AccountMeta{PublicKey: payer, IsSigner: true, IsWritable: true}, accounts[2])
+}
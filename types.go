@@ -15,6 +15,7 @@
 package solana
 
 import (
+	"encoding/json"
 	"fmt"
 
 	bin "github.com/streamingfast/binary"
@@ -33,65 +34,277 @@ func (t *Transaction) ResolveProgramIDIndex(programIDIndex uint8) (PublicKey, er
 	return t.Message.ResolveProgramIDIndex(programIDIndex)
 }
 
+// MessageVersion identifies the wire format of a Message. The zero value is
+// the legacy format so that messages built by hand keep their historical
+// encoding.
+type MessageVersion int
+
+const (
+	MessageVersionLegacy MessageVersion = iota
+	MessageVersionV0
+)
+
+// messageVersionPrefix is the high bit set on the first byte of a versioned
+// message, the remaining 7 bits holding the actual version number. Legacy
+// messages start with `NumRequiredSignatures` which can never have that bit set.
+const messageVersionPrefix = byte(0x80)
+
+func (v MessageVersion) String() string {
+	switch v {
+	case MessageVersionLegacy:
+		return "legacy"
+	case MessageVersionV0:
+		return "v0"
+	}
+	return fmt.Sprintf("unknown(%d)", int(v))
+}
+
+// MarshalJSON renders the version the same way Solana RPC nodes do, that is
+// `"legacy"` for legacy messages and the numerical version otherwise.
+func (v MessageVersion) MarshalJSON() ([]byte, error) {
+	if v == MessageVersionLegacy {
+		return json.Marshal("legacy")
+	}
+	return json.Marshal(int(v) - 1)
+}
+
+func (v *MessageVersion) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" || string(data) == `"legacy"` {
+		*v = MessageVersionLegacy
+		return nil
+	}
+
+	var version int
+	if err := json.Unmarshal(data, &version); err != nil {
+		return fmt.Errorf("invalid message version %s: %w", string(data), err)
+	}
+	if version != 0 {
+		return fmt.Errorf("unsupported message version %d", version)
+	}
+
+	*v = MessageVersionV0
+	return nil
+}
+
 type Message struct {
-	Header          MessageHeader `json:"header"`
-	AccountKeys     []PublicKey   `json:"accountKeys"`
-	RecentBlockhash PublicKey/* TODO: change to Hash */ `json:"recentBlockhash"`
-	Instructions    []CompiledInstruction `json:"instructions"`
+	Version             MessageVersion              `json:"-"`
+	Header              MessageHeader               `json:"header"`
+	AccountKeys         []PublicKey                 `json:"accountKeys"`
+	RecentBlockhash     PublicKey                   `json:"recentBlockhash"` // TODO: change to Hash
+	Instructions        []CompiledInstruction       `json:"instructions"`
+	AddressTableLookups []MessageAddressTableLookup `json:"addressTableLookups,omitempty"`
+
+	// loadedAddresses holds the addresses resolved from AddressTableLookups,
+	// set through SetAddressTables or SetLoadedAddresses.
+	loadedAddresses *LoadedAddresses
+}
+
+// MessageAddressTableLookup references accounts of an on-chain address lookup
+// table by index, it's only present in versioned messages.
+type MessageAddressTableLookup struct {
+	AccountKey      PublicKey `json:"accountKey"`
+	WritableIndexes []uint8   `json:"writableIndexes"`
+	ReadonlyIndexes []uint8   `json:"readonlyIndexes"`
+}
+
+// LoadedAddresses are the account keys resolved from the address table lookups
+// of a message, in the order the runtime appends them after the static keys.
+type LoadedAddresses struct {
+	Writable []PublicKey `json:"writable"`
+	Readonly []PublicKey `json:"readonly"`
+}
+
+func (m *Message) IsVersioned() bool {
+	return m.Version != MessageVersionLegacy
+}
+
+// SetAddressTables resolves the message's address table lookups against the
+// given lookup tables content, keyed by lookup table account address.
+func (m *Message) SetAddressTables(tables map[PublicKey][]PublicKey) error {
+	loaded := &LoadedAddresses{}
+	for _, lookup := range m.AddressTableLookups {
+		addresses, found := tables[lookup.AccountKey]
+		if !found {
+			return fmt.Errorf("address lookup table %s not provided", lookup.AccountKey)
+		}
+
+		for _, index := range lookup.WritableIndexes {
+			if int(index) >= len(addresses) {
+				return fmt.Errorf("writable index %d out of range for address lookup table %s with %d addresses", index, lookup.AccountKey, len(addresses))
+			}
+			loaded.Writable = append(loaded.Writable, addresses[index])
+		}
+		for _, index := range lookup.ReadonlyIndexes {
+			if int(index) >= len(addresses) {
+				return fmt.Errorf("readonly index %d out of range for address lookup table %s with %d addresses", index, lookup.AccountKey, len(addresses))
+			}
+			loaded.Readonly = append(loaded.Readonly, addresses[index])
+		}
+	}
+
+	m.loadedAddresses = loaded
+	return nil
+}
+
+// SetLoadedAddresses uses already resolved lookup addresses, like the ones
+// found in the `loadedAddresses` field of a transaction's meta returned by RPC nodes.
+func (m *Message) SetLoadedAddresses(loaded LoadedAddresses) {
+	m.loadedAddresses = &loaded
+}
+
+// AllAccountKeys returns the static account keys followed by the writable and
+// then readonly addresses loaded from lookup tables, which is the list
+// compiled instructions' account indexes refer to.
+func (m *Message) AllAccountKeys() []PublicKey {
+	if m.loadedAddresses == nil {
+		return m.AccountKeys
+	}
+
+	out := make([]PublicKey, 0, len(m.AccountKeys)+len(m.loadedAddresses.Writable)+len(m.loadedAddresses.Readonly))
+	out = append(out, m.AccountKeys...)
+	out = append(out, m.loadedAddresses.Writable...)
+	return append(out, m.loadedAddresses.Readonly...)
 }
 
 func (m *Message) AccountMetaList() (out []*AccountMeta) {
-	return m.AccountMetaList()
-	for _, a := range m.AccountKeys {
+	for idx, a := range m.AllAccountKeys() {
 		out = append(out, &AccountMeta{
 			PublicKey:  a,
-			IsSigner:   m.IsSigner(a),
-			IsWritable: m.IsWritable(a),
+			IsSigner:   m.isSignerIndex(idx),
+			IsWritable: m.isWritableIndex(idx),
 		})
 	}
 	return out
 }
 
 func (m *Message) ResolveProgramIDIndex(programIDIndex uint8) (PublicKey, error) {
-	if int(programIDIndex) < len(m.AccountKeys) {
-		return m.AccountKeys[programIDIndex], nil
+	keys := m.AllAccountKeys()
+	if int(programIDIndex) < len(keys) {
+		return keys[programIDIndex], nil
 	}
 	return PublicKey{}, fmt.Errorf("programID index not found %d", programIDIndex)
 }
 
 func (m *Message) TouchAccount(account PublicKey) bool {
-	for _, a := range m.AccountKeys {
-		if a.Equals(account) {
-			return true
-		}
-	}
-	return false
+	return m.accountIndex(account) >= 0
 }
 
 func (m *Message) IsSigner(account PublicKey) bool {
-	for idx, acc := range m.AccountKeys {
-		if acc.Equals(account) {
-			return idx < int(m.Header.NumRequiredSignatures)
-		}
+	index := m.accountIndex(account)
+	if index < 0 {
+		return false
 	}
-	return false
+	return m.isSignerIndex(index)
 }
 
 func (m *Message) IsWritable(account PublicKey) bool {
-	index := 0
-	found := false
-	for idx, acc := range m.AccountKeys {
+	index := m.accountIndex(account)
+	if index < 0 {
+		return false
+	}
+	return m.isWritableIndex(index)
+}
+
+func (m *Message) accountIndex(account PublicKey) int {
+	for idx, acc := range m.AllAccountKeys() {
 		if acc.Equals(account) {
-			found = true
-			index = idx
+			return idx
 		}
 	}
-	if !found {
-		return false
+	return -1
+}
+
+func (m *Message) isSignerIndex(index int) bool {
+	return index < int(m.Header.NumRequiredSignatures)
+}
+
+func (m *Message) isWritableIndex(index int) bool {
+	staticCount := len(m.AccountKeys)
+	if index >= staticCount {
+		// Loaded addresses, writable ones come first
+		return m.loadedAddresses != nil && index-staticCount < len(m.loadedAddresses.Writable)
 	}
+
 	h := m.Header
-	return (index < int(h.NumRequiredSignatures-h.NumReadonlySignedAccounts)) ||
-		((index >= int(h.NumRequiredSignatures)) && (index < len(m.AccountKeys)-int(h.NumReadonlyUnsignedAccounts)))
+	return (index < int(h.NumRequiredSignatures)-int(h.NumReadonlySignedAccounts)) ||
+		((index >= int(h.NumRequiredSignatures)) && (index < staticCount-int(h.NumReadonlyUnsignedAccounts)))
+}
+
+func (m Message) MarshalBinary(encoder *bin.Encoder) error {
+	switch m.Version {
+	case MessageVersionLegacy:
+	case MessageVersionV0:
+		if err := encoder.WriteUint8(messageVersionPrefix); err != nil {
+			return fmt.Errorf("unable to write message version: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported message version %s", m.Version)
+	}
+
+	if err := encoder.Encode(m.Header); err != nil {
+		return fmt.Errorf("unable to write message header: %w", err)
+	}
+	if err := encoder.Encode(m.AccountKeys); err != nil {
+		return fmt.Errorf("unable to write account keys: %w", err)
+	}
+	if err := encoder.Encode(m.RecentBlockhash); err != nil {
+		return fmt.Errorf("unable to write recent blockhash: %w", err)
+	}
+	if err := encoder.Encode(m.Instructions); err != nil {
+		return fmt.Errorf("unable to write instructions: %w", err)
+	}
+
+	if m.Version == MessageVersionV0 {
+		if err := encoder.Encode(m.AddressTableLookups); err != nil {
+			return fmt.Errorf("unable to write address table lookups: %w", err)
+		}
+	}
+	return nil
+}
+
+func (m *Message) UnmarshalBinary(decoder *bin.Decoder) (err error) {
+	first, err := decoder.ReadUint8()
+	if err != nil {
+		return fmt.Errorf("unable to read message first byte: %w", err)
+	}
+
+	if first&messageVersionPrefix != 0 {
+		version := first &^ messageVersionPrefix
+		if version != 0 {
+			return fmt.Errorf("unsupported message version %d", version)
+		}
+
+		m.Version = MessageVersionV0
+		if err := decoder.Decode(&m.Header); err != nil {
+			return fmt.Errorf("unable to read message header: %w", err)
+		}
+	} else {
+		m.Version = MessageVersionLegacy
+		m.Header.NumRequiredSignatures = first
+		if m.Header.NumReadonlySignedAccounts, err = decoder.ReadUint8(); err != nil {
+			return fmt.Errorf("unable to read message header: %w", err)
+		}
+		if m.Header.NumReadonlyUnsignedAccounts, err = decoder.ReadUint8(); err != nil {
+			return fmt.Errorf("unable to read message header: %w", err)
+		}
+	}
+
+	if err := decoder.Decode(&m.AccountKeys); err != nil {
+		return fmt.Errorf("unable to read account keys: %w", err)
+	}
+	if err := decoder.Decode(&m.RecentBlockhash); err != nil {
+		return fmt.Errorf("unable to read recent blockhash: %w", err)
+	}
+	if err := decoder.Decode(&m.Instructions); err != nil {
+		return fmt.Errorf("unable to read instructions: %w", err)
+	}
+
+	if m.Version == MessageVersionV0 {
+		if err := decoder.Decode(&m.AddressTableLookups); err != nil {
+			return fmt.Errorf("unable to read address table lookups: %w", err)
+		}
+	}
+	return nil
 }
 
 func (m *Message) signerKeys() []PublicKey {
@@ -112,12 +325,18 @@ type CompiledInstruction struct {
 	Data           Base58        `json:"data"`
 }
 
-func (ci *CompiledInstruction) ResolveInstructionAccounts(message *Message) (out []*AccountMeta) {
+// ResolveInstructionAccounts returns the AccountMeta of each account of the
+// instruction. Versioned messages using address table lookups must have their
+// loaded addresses set beforehand, their indexes are out of range otherwise.
+func (ci *CompiledInstruction) ResolveInstructionAccounts(message *Message) (out []*AccountMeta, err error) {
 	metas := message.AccountMetaList()
 	for _, acct := range ci.Accounts {
+		if int(acct) >= len(metas) {
+			return nil, fmt.Errorf("account index %d out of range, message has %d accounts", acct, len(metas))
+		}
 		out = append(out, metas[acct])
 	}
-	return
+	return out, nil
 }
 
 func TransactionFromData(in []byte) (*Transaction, error) {
@@ -16,6 +16,7 @@ package solana
 
 import (
 	"bytes"
+	"encoding/json"
 	"testing"
 
 	bin "github.com/streamingfast/binary"
@@ -37,3 +38,122 @@ func TestCompiledInstructions(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []byte{5, 3, 2, 5, 8, 5, 1, 2, 3, 4, 5}, buf.Bytes())
 }
+
+func TestMessage_V0_BinaryRoundTrip(t *testing.T) {
+	payer := MustPublicKeyFromBase58("A9QnpgfhCkmiBSjgBuWk76Wo3HxzxvDopUq9x6UUMmjn")
+	program := MustPublicKeyFromBase58("Vote111111111111111111111111111111111111111")
+	table := MustPublicKeyFromBase58("9hFtYBYmBJCVguRYs9pBTWKYAFoKfjYR7zBPpEkVsmD")
+
+	trx := &Transaction{
+		Signatures: []Signature{{0x01}},
+		Message: Message{
+			Version: MessageVersionV0,
+			Header: MessageHeader{
+				NumRequiredSignatures:       1,
+				NumReadonlySignedAccounts:   0,
+				NumReadonlyUnsignedAccounts: 1,
+			},
+			AccountKeys:     []PublicKey{payer, program},
+			RecentBlockhash: MustPublicKeyFromBase58("SysvarC1ock11111111111111111111111111111111"),
+			Instructions: []CompiledInstruction{
+				{ProgramIDIndex: 1, AccountCount: 3, Accounts: []uint8{0, 2, 3}, DataLength: 1, Data: []byte{0xaa}},
+			},
+			AddressTableLookups: []MessageAddressTableLookup{
+				{AccountKey: table, WritableIndexes: []uint8{1}, ReadonlyIndexes: []uint8{0}},
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, bin.NewEncoder(buf).Encode(trx))
+
+	data := buf.Bytes()
+	// 1 byte signature count, 64 bytes signature, then the version prefix
+	assert.Equal(t, byte(0x80), data[65])
+	// Lookups are the very last elements of the message
+	assert.Equal(t, append(append([]byte{1}, table[:]...), 1, 1, 1, 0), data[len(data)-37:])
+
+	actual, err := TransactionFromData(data)
+	require.NoError(t, err)
+	assert.Equal(t, trx, actual)
+}
+
+func TestMessage_Legacy_BinaryRoundTrip(t *testing.T) {
+	message := Message{
+		Header:          MessageHeader{NumRequiredSignatures: 2, NumReadonlySignedAccounts: 1, NumReadonlyUnsignedAccounts: 1},
+		AccountKeys:     []PublicKey{{0x01}, {0x02}, {0x03}},
+		RecentBlockhash: PublicKey{0x04},
+		Instructions: []CompiledInstruction{
+			{ProgramIDIndex: 2, AccountCount: 2, Accounts: []uint8{0, 1}, DataLength: 0, Data: []byte{}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, bin.NewEncoder(buf).Encode(message))
+	assert.Equal(t, []byte{2, 1, 1, 3}, buf.Bytes()[0:4])
+
+	var actual Message
+	require.NoError(t, bin.NewDecoder(buf.Bytes()).Decode(&actual))
+	assert.Equal(t, MessageVersionLegacy, actual.Version)
+	assert.Equal(t, message, actual)
+}
+
+func TestMessage_AddressTables(t *testing.T) {
+	payer := PublicKey{0x01}
+	program := PublicKey{0x02}
+	table := PublicKey{0x03}
+	loaded := []PublicKey{{0x10}, {0x11}, {0x12}}
+
+	message := Message{
+		Version:     MessageVersionV0,
+		Header:      MessageHeader{NumRequiredSignatures: 1, NumReadonlyUnsignedAccounts: 1},
+		AccountKeys: []PublicKey{payer, program},
+		Instructions: []CompiledInstruction{
+			{ProgramIDIndex: 1, AccountCount: 3, Accounts: []uint8{0, 2, 3}},
+		},
+		AddressTableLookups: []MessageAddressTableLookup{
+			{AccountKey: table, WritableIndexes: []uint8{2}, ReadonlyIndexes: []uint8{0}},
+		},
+	}
+
+	assert.False(t, message.TouchAccount(loaded[2]))
+
+	_, err := message.Instructions[0].ResolveInstructionAccounts(&message)
+	assert.EqualError(t, err, "account index 2 out of range, message has 2 accounts")
+
+	require.Error(t, message.SetAddressTables(map[PublicKey][]PublicKey{}))
+	require.Error(t, message.SetAddressTables(map[PublicKey][]PublicKey{table: loaded[0:2]}))
+	require.NoError(t, message.SetAddressTables(map[PublicKey][]PublicKey{table: loaded}))
+
+	assert.Equal(t, []PublicKey{payer, program, loaded[2], loaded[0]}, message.AllAccountKeys())
+	assert.Equal(t, []*AccountMeta{
+		{PublicKey: payer, IsSigner: true, IsWritable: true},
+		{PublicKey: program, IsSigner: false, IsWritable: false},
+		{PublicKey: loaded[2], IsSigner: false, IsWritable: true},
+		{PublicKey: loaded[0], IsSigner: false, IsWritable: false},
+	}, message.AccountMetaList())
+
+	assert.True(t, message.IsWritable(loaded[2]))
+	assert.False(t, message.IsSigner(loaded[2]))
+	assert.False(t, message.IsWritable(loaded[0]))
+	assert.False(t, message.IsWritable(loaded[1]))
+
+	accounts, err := message.Instructions[0].ResolveInstructionAccounts(&message)
+	require.NoError(t, err)
+	assert.Equal(t, []PublicKey{payer, loaded[2], loaded[0]}, []PublicKey{accounts[0].PublicKey, accounts[1].PublicKey, accounts[2].PublicKey})
+}
+
+func TestMessageVersion_JSON(t *testing.T) {
+	for _, version := range []MessageVersion{MessageVersionLegacy, MessageVersionV0} {
+		data, err := json.Marshal(version)
+		require.NoError(t, err)
+
+		var actual MessageVersion
+		require.NoError(t, json.Unmarshal(data, &actual))
+		assert.Equal(t, version, actual)
+	}
+
+	data, err := json.Marshal(MessageVersionV0)
+	require.NoError(t, err)
+	assert.Equal(t, "0", string(data))
+}